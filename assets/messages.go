@@ -1,98 +1,139 @@
+// Package messages - каталог текстов бота. Ключи объявлены здесь, сами тексты
+// лежат по языкам в messages_<язык>.go; выбирает язык пакет i18n.
 package messages
 
-const (
+// Key - ключ текста в каталоге. Отдельный тип, чтобы ключ нельзя было
+// случайно отправить пользователю вместо текста.
+type Key string
 
+const (
 	// Game
-	BotIsNotAdmin = `⚠️ Бот должен быть администратором, чтобы начать игру.
-Пожалуйста, назначьте его админом и попробуйте снова.`
-
-	GameNotStarted = `❌ Игра ещё не начата!`
-
-	OnlyGroupChat = `👥 Эта команда работает только в групповых чатах!`
-
-	TheEndMessages = `🎉 Все задания уже использованы! Спасибо за игру.`
-
-	ErrorMessagesForUser = `Упс… Что-то пошло не так. Попробуйте ещё раз.`
-
-	FinishGameMassage = `Спасибо за игру! Надеюсь, вам понравилось!
-Буду благодарен за обратную связь и ваши предложения по улучшению.`
+	BotIsNotAdmin        Key = "BotIsNotAdmin"
+	GameNotStarted       Key = "GameNotStarted"
+	OnlyGroupChat        Key = "OnlyGroupChat"
+	PlayAlone            Key = "PlayAlone"
+	TheEndMessages       Key = "TheEndMessages"
+	ErrorMessagesForUser Key = "ErrorMessagesForUser"
+	BotRestarting        Key = "BotRestarting"
+	FinishGameMassage    Key = "FinishGameMassage"
+	Preparing            Key = "Preparing"
+	FinalScore           Key = "FinalScore"
+	GameScore            Key = "GameScore"
+	RoundScore           Key = "RoundScore"
+	AnonymousPlayer      Key = "AnonymousPlayer"
+
+	// Access
+	AccessCheckFailed Key = "AccessCheckFailed"
+	OnlyAdminButton   Key = "OnlyAdminButton"
+	OnlyAdminCommand  Key = "OnlyAdminCommand"
 
 	// Vote
-	VotedAlready = `⚠️ Вы уже проголосовали в этом раунде!`
-
-	VotedForSelf = `⚠️ За себя голосовать не честно!`
-
-	VotedEarler = `⏳ Голосование ещё не началось или уже завершено.`
-
-	VotedReceived = `✔️ Ваш голос учтён! Ожидаем результатов.`
-
-	VotingStartedMessage = `🗳 Время рассказывать истории и голосовать!`
-
-	VoitingMessage = `⏳ Когда проголосуют все желающие, завершите голосование.`
+	VotedAlready         Key = "VotedAlready"
+	VotedForSelf         Key = "VotedForSelf"
+	VotedEarler          Key = "VotedEarler"
+	VoteStale            Key = "VoteStale"
+	VotedReceived        Key = "VotedReceived"
+	VoteCast             Key = "VoteCast"
+	VotingStartedMessage Key = "VotingStartedMessage"
+	VoitingMessage       Key = "VoitingMessage"
+	VoteCountdown        Key = "VoteCountdown"
+	VoteAutoFinished     Key = "VoteAutoFinished"
+	VoteCountdownStopped Key = "VoteCountdownStopped"
+	NoActiveRound        Key = "NoActiveRound"
+	VotingNotActive      Key = "VotingNotActive"
 
 	// Photo
-	NotEnoughPhoto = `Никто не скинул фотографии. Если не нравится вопрос - запустите /newround.`
-
-	PhotoReceived = `✅ <b>Фото принято!</b>
-Ждём других участников или начинайте голосование.`
-
-	BlitsPhotoReceived = `✅ <b>Фото для БЛИТЦ-раунда принято!</b>
-Ждём других участников. Вы увидите все фото игроков по команде /vote.`
-
-	HelpMessage = `📖 Команды Photo Battle Bot:
-
-/startgame - начать новую игру (все старые данные будут сброшены)
-/endgame - завершить игру и показать финальный счёт
-
-/newround - начать новый раунд с новым заданием
-/vote - начать голосование за лучшее фото
-/finishvote - досрочно завершить голосование
-/score - показать текущие очки игроков
-/feedback - дать обратную связь`
-
-	RoundStartedMessage = `🎲 Новый раунд начался!`
-
-	UnknownCommandMessage = `❓ Неизвестная команда. Введите /help для списка доступных команд.`
-
-	WelcomeSingleMessage = `👋 Привет!
-Ты запустил <b>Memento Game</b> - проект, созданный, чтобы помогать сохранять связь с друзьями на расстоянии. Каждую мелочь не расскажешь и не вспомнишь - поэтому этот бот поможет тебе делиться историями через фотографии из галереи.
-
-В приватном чате ты можешь попробовать основные механики и понять, как работает бот, но настоящая игра доступна только в групповом чате.
-
-👥 Чтобы начать игру по-настоящему, добавь бота в чат с друзьями и нажми /startgame.`
-
-	WelcomeGroupMessage = `📸 Добро пожаловать в <b>Memento</b> - игру, которая помогает оставаться ближе, даже на расстоянии!
-
-🎯 Скоро вы получите задание - оно поможет вспомнить тёплый, забавный или неожиданный момент.
-
-💬 Поделитесь не только фото, но и историей, которая с ним связана.
-Это важно - друзьям интересно знать, что стоит за снимком!
-
-🛑 Это <b>не конкурс фотографий</b>, а способ быть рядом, даже когда вы далеко.
-
-🔐 <i>Фото не сохраняются и не передаются.</i> Бот хранит только очки и статистику.`
-
-	GameRulesText = `🎯 <b>Правила игры:</b>
-
-1. Бот присылает задание - выберите фото из своей галереи.
-2. Отправьте фото в чат. Бот его удалит и покажет все изображения по команде.
-3. Расскажите о своём фото. Можете проголосовать за то, что понравилось.
-4. Нажмите кнопку «Новый раунд», чтобы начать следующий!`
+	NotEnoughPhoto        Key = "NotEnoughPhoto"
+	PhotoReceived         Key = "PhotoReceived"
+	BlitsPhotoReceived    Key = "BlitsPhotoReceived"
+	HelpMessage           Key = "HelpMessage"
+	RoundStartedMessage   Key = "RoundStartedMessage"
+	SubmitDeadlineMessage Key = "SubmitDeadlineMessage"
+	SubmitOneMinuteLeft   Key = "SubmitOneMinuteLeft"
+	SubmitTimeIsUp        Key = "SubmitTimeIsUp"
+	AllPhotosReceived     Key = "AllPhotosReceived"
+	NoPhotosByDeadline    Key = "NoPhotosByDeadline"
+	UnknownCommandMessage Key = "UnknownCommandMessage"
+	WelcomeSingleMessage  Key = "WelcomeSingleMessage"
+	WelcomeGroupMessage   Key = "WelcomeGroupMessage"
+	GameRulesText         Key = "GameRulesText"
 
 	// Feedback
-	AboutFeedback = `✉️ Хотите улучшить игру?
-
-Поделитесь своими идеями, вариантами вопросов или просто скажите спасибо.
-Если не хотите оставлять отзыв - нажмите кнопку "Отмена".`
-
-	ThanksFeedbackMessage = `✅ Спасибо за ваш отзыв!`
-
-	StartFeedbackMessage = `📝 Спасибо за желание помочь!
-
-Чтобы оставить отзыв, перейдите в личную переписку с ботом, нажав кнопку ниже.`
-
-	AfterFewGames = `💬 Вы уже сыграли в эту игру несколько раз!
-
-Если у вас есть идеи или предложения - автор будет рад услышать их.
-Оставить отзыв можно по кнопке ниже.`
+	AboutFeedback            Key = "AboutFeedback"
+	ThanksFeedbackMessage    Key = "ThanksFeedbackMessage"
+	StartFeedbackMessage     Key = "StartFeedbackMessage"
+	AfterFewGames            Key = "AfterFewGames"
+	FeedbackCancelled        Key = "FeedbackCancelled"
+	FeedbackCancelledMessage Key = "FeedbackCancelledMessage"
+	FeedbackForAdmin         Key = "FeedbackForAdmin"
+
+	// Language
+	LanguageName    Key = "LanguageName"
+	LanguageChoose  Key = "LanguageChoose"
+	LanguageChanged Key = "LanguageChanged"
+	LanguageUnknown Key = "LanguageUnknown"
+
+	// Packs
+	BlitzTaskLabel   Key = "BlitzTaskLabel"
+	BlitzRules       Key = "BlitzRules"
+	BlitzSpeedBonus  Key = "BlitzSpeedBonus"
+	PacksChoose      Key = "PacksChoose"
+	PackEnabled      Key = "PackEnabled"
+	PackDisabled     Key = "PackDisabled"
+	PacksLastEnabled Key = "PacksLastEnabled"
+	HistoryReset     Key = "HistoryReset"
+
+	// Suggestions
+	SuggestUsage          Key = "SuggestUsage"
+	SuggestTooLong        Key = "SuggestTooLong"
+	SuggestDuplicate      Key = "SuggestDuplicate"
+	SuggestPending        Key = "SuggestPending"
+	SuggestApproved       Key = "SuggestApproved"
+	SuggestRejected       Key = "SuggestRejected"
+	SuggestAlreadyDecided Key = "SuggestAlreadyDecided"
+
+	// Submissions
+	SubmitUsage               Key = "SubmitUsage"
+	SubmitReceived            Key = "SubmitReceived"
+	SubmissionCard            Key = "SubmissionCard"
+	SubmissionEditHint        Key = "SubmissionEditHint"
+	SubmissionEditUsage       Key = "SubmissionEditUsage"
+	SubmissionApproved        Key = "SubmissionApproved"
+	SubmissionRejected        Key = "SubmissionRejected"
+	SubmissionAlreadyReviewed Key = "SubmissionAlreadyReviewed"
+	SubmissionQueueEmpty      Key = "SubmissionQueueEmpty"
+	SubmissionAuthorThanks    Key = "SubmissionAuthorThanks"
+	TaskAuthorCredit          Key = "TaskAuthorCredit"
+
+	// Task catalogue
+	TaskAdminHelp  Key = "TaskAdminHelp"
+	TaskAdded      Key = "TaskAdded"
+	TaskUpdated    Key = "TaskUpdated"
+	TaskDisabled   Key = "TaskDisabled"
+	TaskEnabled    Key = "TaskEnabled"
+	TaskNotFound   Key = "TaskNotFound"
+	TaskBadField   Key = "TaskBadField"
+	TaskBadValue   Key = "TaskBadValue"
+	TaskDuplicate  Key = "TaskDuplicate"
+	TaskListEmpty  Key = "TaskListEmpty"
+	TaskListHeader Key = "TaskListHeader"
+
+	// Buttons
+	BtnStartGame      Key = "BtnStartGame"
+	BtnStartRound     Key = "BtnStartRound"
+	BtnChangeTask     Key = "BtnChangeTask"
+	BtnStartVote      Key = "BtnStartVote"
+	BtnFinishVote     Key = "BtnFinishVote"
+	BtnVoteForPhoto   Key = "BtnVoteForPhoto"
+	BtnFeedback       Key = "BtnFeedback"
+	BtnCancelFeedback Key = "BtnCancelFeedback"
+	BtnPackOn         Key = "BtnPackOn"
+	BtnPackOff        Key = "BtnPackOff"
+
+	BtnApproveSuggestion Key = "BtnApproveSuggestion"
+	BtnRejectSuggestion  Key = "BtnRejectSuggestion"
+
+	BtnApproveSubmission Key = "BtnApproveSubmission"
+	BtnEditSubmission    Key = "BtnEditSubmission"
+	BtnRejectSubmission  Key = "BtnRejectSubmission"
 )
@@ -0,0 +1,289 @@
+package messages
+
+// EN - английские тексты
+var EN = map[Key]string{
+	// Game
+	BotIsNotAdmin: `⚠️ The bot must be an administrator to start a game.
+Please make it an admin and try again.`,
+
+	GameNotStarted: `❌ The game hasn't started yet!`,
+
+	OnlyGroupChat: `👥 This command only works in group chats!`,
+
+	PlayAlone: `Playing alone is no fun. Add me to a group with your friends.`,
+
+	TheEndMessages: `🎉 All tasks have been used! Thanks for playing.`,
+
+	ErrorMessagesForUser: `Oops… Something went wrong. Please try again.`,
+
+	BotRestarting: `🔄 The bot is restarting. The game is saved and will continue in a couple of minutes - no need to do anything.`,
+
+	FinishGameMassage: `Thanks for playing! Hope you enjoyed it!
+I'd be grateful for your feedback and ideas for improvement.`,
+
+	Preparing: `⏳ Getting ready`,
+
+	FinalScore: `🏁 The game is over!
+
+📊 Final score:`,
+
+	GameScore: `🏆 Current game score:`,
+
+	RoundScore: `⭐ Round results:`,
+
+	AnonymousPlayer: `Anonymous Sturgeon`,
+
+	// Access
+	AccessCheckFailed: `⚠️ Could not check your permissions.`,
+
+	OnlyAdminButton: `🚫 Only an administrator can use this button.`,
+
+	OnlyAdminCommand: `🚫 Only an administrator can use this command.`,
+
+	// Vote
+	VotedAlready: `⚠️ You have already voted in this round!`,
+
+	VotedForSelf: `⚠️ Voting for yourself isn't fair!`,
+
+	VotedEarler: `⏳ Voting hasn't started yet or is already over.`,
+
+	VoteStale: `⚠️ This button is from a previous round or another game. Vote under the photos of the current round.`,
+
+	VotedReceived: `✔️ Your vote is counted! Waiting for the results.`,
+
+	VoteCast: `%s has voted`,
+
+	VotingStartedMessage: `🗳 Time to tell your stories and vote!`,
+
+	VoitingMessage: `⏳ When everyone who wants to has voted, finish the voting.`,
+
+	VoteCountdown: `⏳ Voting ends in: <b>%s</b>`,
+
+	VoteAutoFinished: `⌛ Time is up - voting finished automatically!`,
+
+	VoteCountdownStopped: `🏁 Voting is over`,
+
+	NoActiveRound: `There is no round in progress right now`,
+
+	VotingNotActive: `Voting is not active right now.`,
+
+	// Photo
+	NotEnoughPhoto: `Nobody has sent a photo. If you don't like the task, run /newround.`,
+
+	PhotoReceived: `✅ <b>Photo accepted!</b>
+Waiting for the others, or start the voting.`,
+
+	BlitsPhotoReceived: `✅ <b>Photo for the BLITZ round accepted!</b>
+Waiting for the others. All the photos will be shown in the chat when time is up.`,
+
+	HelpMessage: `📖 Photo Battle Bot commands:
+
+/startgame - start a new game (all previous data will be reset)
+/endgame - finish the game and show the final score
+
+/newround - start a new round with a new task
+/vote - start voting for the best photo
+/finishvote - finish the voting early
+/score - show the current score
+/feedback - send feedback
+/language - bot language in this chat
+/packs - which task packs to play in this chat
+/resethistory - forget tasks played in previous games
+/suggest - suggest your own task for this chat
+/submit - suggest a task for the bot's shared catalogue`,
+
+	RoundStartedMessage: `🎲 A new round has started!`,
+
+	SubmitDeadlineMessage: `⏱ Time to send a photo: <b>%s</b>. After that the voting opens automatically.`,
+
+	SubmitOneMinuteLeft: `⏳ 1 minute left to send a photo!`,
+
+	SubmitTimeIsUp: `⌛ Time to send photos is up!`,
+
+	AllPhotosReceived: `📸 Everyone has sent a photo!`,
+
+	NoPhotosByDeadline: `⌛ Time is up, but nobody has sent a photo. Change the task with /newround.`,
+
+	UnknownCommandMessage: `❓ Unknown command. Type /help for the list of commands.`,
+
+	WelcomeSingleMessage: `👋 Hi!
+You have started <b>Memento Game</b> - a project made to help you stay in touch with friends who are far away. You can't tell or remember every little thing, so this bot helps you share stories through photos from your gallery.
+
+In a private chat you can try out the main mechanics and see how the bot works, but the real game is only available in a group chat.
+
+👥 To play for real, add the bot to a chat with your friends and press /startgame.`,
+
+	WelcomeGroupMessage: `📸 Welcome to <b>Memento</b> - a game that helps you stay close, even at a distance!
+
+🎯 Soon you will get a task - it will help you remember a warm, funny or unexpected moment.
+
+💬 Share not only the photo but also the story behind it.
+That matters - your friends want to know what's behind the picture!
+
+🛑 This is <b>not a photo contest</b>, but a way to be together even when you are apart.
+
+🔐 <i>Photos are not stored or shared.</i> The bot only keeps the scores and statistics.`,
+
+	GameRulesText: `🎯 <b>Game rules:</b>
+
+1. The bot sends a task - pick a photo from your gallery.
+2. Send the photo to the chat. The bot deletes it and shows all the pictures on command.
+3. Tell the story behind your photo. You can vote for the ones you like.
+4. Press "New round" to start the next one!`,
+
+	// Feedback
+	AboutFeedback: `✉️ Want to make the game better?
+
+Share your ideas, task suggestions, or just say thanks.
+If you don't want to leave feedback, press "Cancel".`,
+
+	ThanksFeedbackMessage: `✅ Thank you for your feedback!`,
+
+	StartFeedbackMessage: `📝 Thanks for wanting to help!
+
+To leave feedback, open a private chat with the bot using the button below.`,
+
+	AfterFewGames: `💬 You have played this game several times already!
+
+If you have ideas or suggestions, the author will be happy to hear them.
+You can leave feedback with the button below.`,
+
+	FeedbackCancelled: `Feedback cancelled.`,
+
+	FeedbackCancelledMessage: `Sending feedback was cancelled.`,
+
+	FeedbackForAdmin: `📬 New feedback from @%s (%d):
+
+%s`,
+
+	// Language
+	LanguageName: `English`,
+
+	LanguageChoose: `🌐 Bot language in this chat: <b>%s</b>
+Choose another one:`,
+
+	LanguageChanged: `✅ I speak English now.`,
+
+	LanguageUnknown: `❓ Unknown language. Available: %s`,
+
+	// Packs
+	BlitzTaskLabel: `[BLITZ]`,
+
+	BlitzRules: `⚡ Blitz! You have only <b>%s</b> for a photo. Photos are accepted silently and all shown at once when time is up. The first %d get +1 point for speed.`,
+
+	BlitzSpeedBonus: `⚡ Speed point: %s`,
+
+	PacksChoose: `🗂 Task packs in this chat.
+Tap a pack to turn it on or off. Changes apply from the next round.`,
+
+	PackEnabled: `✅ Pack "%s" is on`,
+
+	PackDisabled: `Pack "%s" is off`,
+
+	PacksLastEnabled: `⚠️ At least one pack must stay on.`,
+
+	// Suggestions
+	SuggestUsage: `💡 Came up with a task for your group? Write it after the command:
+/suggest A photo of the weirdest thing you bought this month`,
+
+	SuggestTooLong: `⚠️ The task is too long, keep it under %d characters.`,
+
+	SuggestDuplicate: `⚠️ This chat already has this task or it is waiting for a decision.`,
+
+	SuggestPending: `💡 %s suggests a task for this chat:
+<b>%s</b>
+
+Chat administrators can add it to the games.`,
+
+	SuggestApproved: `✅ The task from %s is added to this chat's games:
+<b>%s</b>`,
+
+	SuggestRejected: `❌ The task from %s was not added:
+<s>%s</s>`,
+
+	SuggestAlreadyDecided: `This task has already been reviewed.`,
+
+	// Submissions
+	SubmitUsage: `✍️ Came up with a task everyone would enjoy? Write it after the command:
+/submit A photo of your oldest gadget
+
+Once reviewed, it joins the shared catalogue with your name on it.`,
+
+	SubmitReceived: `📬 Thanks! Your task has been sent for review.`,
+
+	SubmissionCard: `📥 Submission #%d from %s:
+<b>%s</b>`,
+
+	SubmissionEditHint: `✏️ Copy the command and adjust the text:
+<code>/editsubmission %d %s</code>`,
+
+	SubmissionEditUsage: `Format: /editsubmission &lt;number&gt; &lt;new text&gt;`,
+
+	SubmissionApproved: `✅ Added to the catalogue as <code>%s</code>.`,
+
+	SubmissionRejected: `❌ Rejected.`,
+
+	SubmissionAlreadyReviewed: `This submission has already been reviewed.`,
+
+	SubmissionQueueEmpty: `📭 No new submissions.`,
+
+	SubmissionAuthorThanks: `🎉 Your task has been added to the shared catalogue:
+<b>%s</b>
+
+It will start coming up in games soon.`,
+
+	TaskAuthorCredit: `✍️ Task by %s`,
+
+	HistoryReset: `🧹 The chat's task history is cleared (%d tasks). They can come up again in future games.`,
+
+	// Task catalogue
+	TaskAdminHelp: `🛠 Task catalogue:
+
+/tasks [pack] - list tasks
+/addtask &lt;pack&gt; &lt;text&gt; - add a task
+/edittask &lt;id&gt; &lt;field&gt; &lt;value&gt; - edit a task, fields: %s
+/disabletask &lt;id&gt; - take a task out of rounds
+/enabletask &lt;id&gt; - bring a task back
+/queue - task submissions from players
+
+Changes apply immediately, no restart needed.`,
+
+	TaskAdded: `✅ Task <code>%s</code> added to pack "%s".`,
+
+	TaskUpdated: `✅ Task <code>%s</code> updated. Pack imports will no longer change it.`,
+
+	TaskDisabled: `⏸ Task <code>%s</code> is off.`,
+
+	TaskEnabled: `▶️ Task <code>%s</code> is back in the game.`,
+
+	TaskNotFound: `❓ There is no task <code>%s</code>. See /tasks`,
+
+	TaskBadField: `❓ Unknown field. You can edit: %s`,
+
+	TaskBadValue: `⚠️ Invalid value. Pack IDs use latin letters, digits and dashes; blitz is on or off; rating is general or mature; the main language text can't be empty.`,
+
+	TaskDuplicate: `⚠️ A task with this text already exists.`,
+
+	TaskListEmpty: `No tasks.`,
+
+	TaskListHeader: `📋 Tasks: %d. ⏸ - off, 🗄 - archived, ✏️ - edited by hand, in brackets - photos/skips.`,
+
+	// Buttons
+	BtnStartGame:      `New game`,
+	BtnStartRound:     `Start round`,
+	BtnChangeTask:     `🔁 Change task`,
+	BtnStartVote:      `Start voting`,
+	BtnFinishVote:     `Finish voting`,
+	BtnVoteForPhoto:   `Vote for photo #%d`,
+	BtnFeedback:       `Leave feedback`,
+	BtnCancelFeedback: `Cancel feedback`,
+	BtnPackOn:         `✅ %s (%d)`,
+	BtnPackOff:        `▫️ %s (%d)`,
+
+	BtnApproveSuggestion: `✅ Add`,
+	BtnRejectSuggestion:  `❌ Reject`,
+
+	BtnApproveSubmission: `✅ To catalogue`,
+	BtnEditSubmission:    `✏️ Edit`,
+	BtnRejectSubmission:  `❌ Reject`,
+}
@@ -0,0 +1,289 @@
+package messages
+
+// RU - русские тексты, язык по умолчанию
+var RU = map[Key]string{
+	// Game
+	BotIsNotAdmin: `⚠️ Бот должен быть администратором, чтобы начать игру.
+Пожалуйста, назначьте его админом и попробуйте снова.`,
+
+	GameNotStarted: `❌ Игра ещё не начата!`,
+
+	OnlyGroupChat: `👥 Эта команда работает только в групповых чатах!`,
+
+	TheEndMessages: `🎉 Все задания уже использованы! Спасибо за игру.`,
+
+	ErrorMessagesForUser: `Упс… Что-то пошло не так. Попробуйте ещё раз.`,
+
+	BotRestarting: `🔄 Бот перезапускается. Игра сохранена и продолжится через пару минут - ничего делать не нужно.`,
+
+	FinishGameMassage: `Спасибо за игру! Надеюсь, вам понравилось!
+Буду благодарен за обратную связь и ваши предложения по улучшению.`,
+
+	PlayAlone: `Играть в одиночестве - не интересно. Добавь меня в группу друзей.`,
+
+	Preparing: `⏳ Подготовка`,
+
+	FinalScore: `🏁 Игра завершена!
+
+📊 Финальный счёт:`,
+
+	GameScore: `🏆 Текущий результат игры:`,
+
+	RoundScore: `⭐ Результаты раунда:`,
+
+	AnonymousPlayer: `Анонимный Осётр`,
+
+	// Access
+	AccessCheckFailed: `⚠️ Не удалось проверить доступ.`,
+
+	OnlyAdminButton: `🚫 Только администратор может использовать эту кнопку.`,
+
+	OnlyAdminCommand: `🚫 Только администратор может использовать эту команду.`,
+
+	// Vote
+	VotedAlready: `⚠️ Вы уже проголосовали в этом раунде!`,
+
+	VotedForSelf: `⚠️ За себя голосовать не честно!`,
+
+	VotedEarler: `⏳ Голосование ещё не началось или уже завершено.`,
+
+	VoteStale: `⚠️ Эта кнопка из прошлого раунда или другой игры. Голосуйте под фото текущего раунда.`,
+
+	VotedReceived: `✔️ Ваш голос учтён! Ожидаем результатов.`,
+
+	VoteCast: `%s проголосовал(а)`,
+
+	VotingStartedMessage: `🗳 Время рассказывать истории и голосовать!`,
+
+	VoitingMessage: `⏳ Когда проголосуют все желающие, завершите голосование.`,
+
+	VoteCountdown: `⏳ До конца голосования: <b>%s</b>`,
+
+	VoteAutoFinished: `⌛ Время вышло - голосование завершено автоматически!`,
+
+	VoteCountdownStopped: `🏁 Голосование завершено`,
+
+	NoActiveRound: `На данный момент нет запущенного раунда`,
+
+	VotingNotActive: `Сейчас голосование не активно.`,
+
+	// Photo
+	NotEnoughPhoto: `Никто не скинул фотографии. Если не нравится вопрос - запустите /newround.`,
+
+	PhotoReceived: `✅ <b>Фото принято!</b>
+Ждём других участников или начинайте голосование.`,
+
+	BlitsPhotoReceived: `✅ <b>Фото для БЛИТЦ-раунда принято!</b>
+Ждём других участников. Все фото откроются в чате, когда время выйдет.`,
+
+	HelpMessage: `📖 Команды Photo Battle Bot:
+
+/startgame - начать новую игру (все старые данные будут сброшены)
+/endgame - завершить игру и показать финальный счёт
+
+/newround - начать новый раунд с новым заданием
+/vote - начать голосование за лучшее фото
+/finishvote - досрочно завершить голосование
+/score - показать текущие очки игроков
+/feedback - дать обратную связь
+/language - язык бота в этом чате
+/packs - какие паки заданий играть в этом чате
+/resethistory - забыть задания, сыгранные в прошлых играх
+/suggest - предложить своё задание для этого чата
+/submit - предложить задание в общий каталог бота`,
+
+	RoundStartedMessage: `🎲 Новый раунд начался!`,
+
+	SubmitDeadlineMessage: `⏱ На отправку фото: <b>%s</b>. Потом голосование откроется само.`,
+
+	SubmitOneMinuteLeft: `⏳ Осталась 1 минута, чтобы прислать фото!`,
+
+	SubmitTimeIsUp: `⌛ Время на отправку фото вышло!`,
+
+	AllPhotosReceived: `📸 Все участники прислали фото!`,
+
+	NoPhotosByDeadline: `⌛ Время вышло, но никто не прислал фото. Поменяйте задание через /newround.`,
+
+	UnknownCommandMessage: `❓ Неизвестная команда. Введите /help для списка доступных команд.`,
+
+	WelcomeSingleMessage: `👋 Привет!
+Ты запустил <b>Memento Game</b> - проект, созданный, чтобы помогать сохранять связь с друзьями на расстоянии. Каждую мелочь не расскажешь и не вспомнишь - поэтому этот бот поможет тебе делиться историями через фотографии из галереи.
+
+В приватном чате ты можешь попробовать основные механики и понять, как работает бот, но настоящая игра доступна только в групповом чате.
+
+👥 Чтобы начать игру по-настоящему, добавь бота в чат с друзьями и нажми /startgame.`,
+
+	WelcomeGroupMessage: `📸 Добро пожаловать в <b>Memento</b> - игру, которая помогает оставаться ближе, даже на расстоянии!
+
+🎯 Скоро вы получите задание - оно поможет вспомнить тёплый, забавный или неожиданный момент.
+
+💬 Поделитесь не только фото, но и историей, которая с ним связана.
+Это важно - друзьям интересно знать, что стоит за снимком!
+
+🛑 Это <b>не конкурс фотографий</b>, а способ быть рядом, даже когда вы далеко.
+
+🔐 <i>Фото не сохраняются и не передаются.</i> Бот хранит только очки и статистику.`,
+
+	GameRulesText: `🎯 <b>Правила игры:</b>
+
+1. Бот присылает задание - выберите фото из своей галереи.
+2. Отправьте фото в чат. Бот его удалит и покажет все изображения по команде.
+3. Расскажите о своём фото. Можете проголосовать за то, что понравилось.
+4. Нажмите кнопку «Новый раунд», чтобы начать следующий!`,
+
+	// Feedback
+	AboutFeedback: `✉️ Хотите улучшить игру?
+
+Поделитесь своими идеями, вариантами вопросов или просто скажите спасибо.
+Если не хотите оставлять отзыв - нажмите кнопку "Отмена".`,
+
+	ThanksFeedbackMessage: `✅ Спасибо за ваш отзыв!`,
+
+	StartFeedbackMessage: `📝 Спасибо за желание помочь!
+
+Чтобы оставить отзыв, перейдите в личную переписку с ботом, нажав кнопку ниже.`,
+
+	AfterFewGames: `💬 Вы уже сыграли в эту игру несколько раз!
+
+Если у вас есть идеи или предложения - автор будет рад услышать их.
+Оставить отзыв можно по кнопке ниже.`,
+
+	FeedbackCancelled: `Отзыв отменён.`,
+
+	FeedbackCancelledMessage: `Отправка отзыва отменена.`,
+
+	FeedbackForAdmin: `📬 Новый отзыв от @%s (%d):
+
+%s`,
+
+	// Language
+	LanguageName: `Русский`,
+
+	LanguageChoose: `🌐 Язык бота в этом чате: <b>%s</b>
+Выберите другой:`,
+
+	LanguageChanged: `✅ Теперь я говорю по-русски.`,
+
+	LanguageUnknown: `❓ Такого языка нет. Доступны: %s`,
+
+	// Packs
+	BlitzTaskLabel: `[БЛИЦ]`,
+
+	BlitzRules: `⚡ Блиц! На фото всего <b>%s</b>. Фото принимаются молча и откроются все сразу, когда время выйдет. Первые %d получат +1 очко за скорость.`,
+
+	BlitzSpeedBonus: `⚡ Очко за скорость: %s`,
+
+	PacksChoose: `🗂 Паки заданий в этом чате.
+Нажмите на пак, чтобы включить или выключить его. Изменения действуют со следующего раунда.`,
+
+	PackEnabled: `✅ Пак «%s» включён`,
+
+	PackDisabled: `Пак «%s» выключен`,
+
+	PacksLastEnabled: `⚠️ Нужен хотя бы один включённый пак.`,
+
+	// Suggestions
+	SuggestUsage: `💡 Придумали задание для своей компании? Напишите его после команды:
+/suggest Фото самой странной покупки этого месяца`,
+
+	SuggestTooLong: `⚠️ Слишком длинное задание, уложитесь в %d символов.`,
+
+	SuggestDuplicate: `⚠️ Такое задание в чате уже есть или ждёт решения.`,
+
+	SuggestPending: `💡 %s предлагает задание для этого чата:
+<b>%s</b>
+
+Администраторы чата могут добавить его в игры.`,
+
+	SuggestApproved: `✅ Задание от %s добавлено в игры этого чата:
+<b>%s</b>`,
+
+	SuggestRejected: `❌ Задание от %s не добавлено:
+<s>%s</s>`,
+
+	SuggestAlreadyDecided: `Это задание уже рассмотрели.`,
+
+	// Submissions
+	SubmitUsage: `✍️ Придумали задание, которое понравится всем? Напишите его после команды:
+/submit Фото вашего самого старого гаджета
+
+После проверки оно попадёт в общий каталог с вашим именем.`,
+
+	SubmitReceived: `📬 Спасибо! Задание отправлено на проверку.`,
+
+	SubmissionCard: `📥 Заявка №%d от %s:
+<b>%s</b>`,
+
+	SubmissionEditHint: `✏️ Скопируйте команду и поправьте текст:
+<code>/editsubmission %d %s</code>`,
+
+	SubmissionEditUsage: `Формат: /editsubmission &lt;номер&gt; &lt;новый текст&gt;`,
+
+	SubmissionApproved: `✅ Добавлено в каталог как <code>%s</code>.`,
+
+	SubmissionRejected: `❌ Отклонено.`,
+
+	SubmissionAlreadyReviewed: `Эту заявку уже рассмотрели.`,
+
+	SubmissionQueueEmpty: `📭 Новых заявок нет.`,
+
+	SubmissionAuthorThanks: `🎉 Ваше задание добавлено в общий каталог:
+<b>%s</b>
+
+Скоро оно начнёт выпадать в играх.`,
+
+	TaskAuthorCredit: `✍️ Автор задания: %s`,
+
+	HistoryReset: `🧹 История заданий чата очищена (заданий: %d). Они снова могут выпасть в следующих играх.`,
+
+	// Task catalogue
+	TaskAdminHelp: `🛠 Каталог заданий:
+
+/tasks [пак] - список заданий
+/addtask &lt;пак&gt; &lt;текст&gt; - добавить задание
+/edittask &lt;id&gt; &lt;поле&gt; &lt;значение&gt; - изменить задание, поля: %s
+/disabletask &lt;id&gt; - убрать задание из раундов
+/enabletask &lt;id&gt; - вернуть задание
+/queue - заявки игроков на новые задания
+
+Изменения действуют сразу, без перезапуска.`,
+
+	TaskAdded: `✅ Задание <code>%s</code> добавлено в пак «%s».`,
+
+	TaskUpdated: `✅ Задание <code>%s</code> обновлено. Импорт паков его больше не меняет.`,
+
+	TaskDisabled: `⏸ Задание <code>%s</code> выключено.`,
+
+	TaskEnabled: `▶️ Задание <code>%s</code> снова в игре.`,
+
+	TaskNotFound: `❓ Задания <code>%s</code> нет. Список - /tasks`,
+
+	TaskBadField: `❓ Такого поля нет. Можно менять: %s`,
+
+	TaskBadValue: `⚠️ Неподходящее значение. ID пака - латиница, цифры и дефис; blitz - on или off; rating - general или mature; текст на основном языке не может быть пустым.`,
+
+	TaskDuplicate: `⚠️ Задание с таким текстом уже есть.`,
+
+	TaskListEmpty: `Заданий нет.`,
+
+	TaskListHeader: `📋 Заданий: %d. ⏸ - выключено, 🗄 - в архиве, ✏️ - изменено вручную, в скобках - фото/пропуски.`,
+
+	// Buttons
+	BtnStartGame:      `Новая игра`,
+	BtnStartRound:     `Начать раунд`,
+	BtnChangeTask:     `🔁 Поменять задание`,
+	BtnStartVote:      `Начать голосование`,
+	BtnFinishVote:     `Завершить голосование`,
+	BtnVoteForPhoto:   `Голосовать за фото №%d`,
+	BtnFeedback:       `Оставить отзыв`,
+	BtnCancelFeedback: `Отменить отзыв`,
+	BtnPackOn:         `✅ %s (%d)`,
+	BtnPackOff:        `▫️ %s (%d)`,
+
+	BtnApproveSuggestion: `✅ Добавить`,
+	BtnRejectSuggestion:  `❌ Отклонить`,
+
+	BtnApproveSubmission: `✅ В каталог`,
+	BtnEditSubmission:    `✏️ Изменить`,
+	BtnRejectSubmission:  `❌ Отклонить`,
+}
@@ -1,65 +1,78 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
-	"time"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/kiselevos/memento_game_bot/config"
-	"github.com/kiselevos/memento_game_bot/internal/bot/middleware"
-	"github.com/kiselevos/memento_game_bot/internal/feedback"
-	"github.com/kiselevos/memento_game_bot/internal/game"
-	"github.com/kiselevos/memento_game_bot/internal/handlers"
+	"github.com/kiselevos/memento_game_bot/internal/app"
 	"github.com/kiselevos/memento_game_bot/internal/logging"
-	"github.com/kiselevos/memento_game_bot/internal/repositories"
-	"github.com/kiselevos/memento_game_bot/internal/tasks"
-	"github.com/kiselevos/memento_game_bot/pkg/db"
-
-	tb "gopkg.in/telebot.v3"
 )
 
 func main() {
 
-	logging.InitLogger("logs/bot.log")
-
-	conf := config.LoadConfig()
+	configFile := flag.String("config", "", "файл конфигурации .yaml или .toml (по умолчанию CONFIG_FILE)")
+	printConfig := flag.Bool("print-config", false, "вывести действующую конфигурацию (секреты скрыты) и выйти")
+	apiURL := flag.String("api-url", "", "Bot API URL (по умолчанию TELEGRAM_API_URL или api.telegram.org)")
+	flag.Parse()
 
-	database, err := db.NewDB(conf)
+	conf, err := config.Load(*configFile)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if *apiURL != "" {
+		conf.TG.APIURL = *apiURL
+	}
 
-	// Repository
-	userRepo := repositories.NewUserRepository(database)
-	sessionRepo := repositories.NewSessionRepository(database)
-	taskRepo := repositories.NewTaskRepository(database)
+	if *printConfig {
+		if err := conf.Print(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		if err := conf.Validate(); err != nil {
+			log.Fatalf("invalid configuration:\n%v", err)
+		}
+		return
+	}
 
-	// Tg settings
-	pref := tb.Settings{
-		Token:  conf.TG.Token,
-		Poller: middleware.DropOldMessages(10 * time.Second),
-		OnError: func(err error, c tb.Context) {
-			log.Printf("Error: %v\n", err)
-		},
+	if err := conf.Validate(); err != nil {
+		log.Fatalf("invalid configuration:\n%v", err)
 	}
 
-	b, err := tb.NewBot(pref)
+	logs, err := logging.Setup(conf.Log)
 	if err != nil {
 		log.Fatal(err)
 	}
+	defer logs.Close()
 
-	botInfo := b.Me
+	for _, w := range conf.Warnings() {
+		slog.Warn(w)
+	}
 
-	// Инициализация GameManager
-	tl, err := tasks.NewTasksList("assets/tasks.json")
+	a, err := app.New(conf)
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("Бот не запущен", "err", err)
+		logs.Close()
+		os.Exit(1)
 	}
-	gm := game.NewGameManager(userRepo, sessionRepo, taskRepo)
-	fm := feedback.NewFeedbackManager(10 * time.Minute)
 
-	h := handlers.NewHandlers(b, fm, conf.Admin.AdminsID, botInfo, gm, tl)
-	h.RegisterAll()
+	// SIGTERM (docker stop) и Ctrl+C - корректная остановка
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go a.Run()
 
-	log.Println("Bot starts...")
-	b.Start()
+	<-ctx.Done()
+	stop() // повторный сигнал завершит процесс сразу
+	slog.Info("Получен сигнал остановки", "timeout", conf.Shutdown.Timeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), conf.Shutdown.Timeout)
+	defer cancel()
+	if err := a.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Остановка не завершена вовремя", "err", err)
+	}
 }
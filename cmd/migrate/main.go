@@ -0,0 +1,87 @@
+// Утилита миграций: go run ./cmd/migrate [-config file] [status|up|down [n]]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/kiselevos/memento_game_bot/config"
+	"github.com/kiselevos/memento_game_bot/migrations"
+	"github.com/kiselevos/memento_game_bot/pkg/db"
+)
+
+const usage = "usage: migrate [-config file] [status|up|down [n]]"
+
+func main() {
+
+	configFile := flag.String("config", "", "файл конфигурации .yaml или .toml (по умолчанию CONFIG_FILE)")
+	flag.Parse()
+	args := flag.Args()
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	// Токен бота и прочее утилите не нужны - проверяется только БД
+	conf, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := conf.Db.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	database, err := db.NewDB(conf.Db)
+	if err != nil {
+		log.Fatalf("failed to connect to DB: %v", err)
+	}
+
+	m := migrations.NewMigrator(database.DB)
+
+	switch command {
+	case "status":
+		list, err := m.Status()
+		if err != nil {
+			log.Fatalf("status failed: %v", err)
+		}
+		for _, s := range list {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-30s %s\n", s.Version, s.Name, state)
+		}
+
+	case "up":
+		done, err := m.Up()
+		for _, mig := range done {
+			log.Printf("applied %04d_%s", mig.Version, mig.Name)
+		}
+		if err != nil {
+			log.Fatalf("migration failed: %v", err)
+		}
+		log.Println("migrations applied successfully")
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				log.Fatalf("invalid steps '%s': %s", args[1], usage)
+			}
+		}
+		done, err := m.Down(steps)
+		for _, mig := range done {
+			log.Printf("rolled back %04d_%s", mig.Version, mig.Name)
+		}
+		if err != nil {
+			log.Fatalf("rollback failed: %v", err)
+		}
+
+	default:
+		log.Fatal(usage)
+	}
+}
@@ -1,102 +1,269 @@
+// Package config - настройки бота. Источники по возрастанию приоритета:
+// значения по умолчанию, файл YAML или TOML, переменные окружения (и .env).
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
-	"strconv"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Db    DbConfig
-	TG    TgConfig
-	Admin AdminsConfig
+	Db       DbConfig       `yaml:"db" toml:"db"`
+	TG       TgConfig       `yaml:"telegram" toml:"telegram"`
+	Admin    AdminsConfig   `yaml:"admin" toml:"admin"`
+	Game     GameConfig     `yaml:"game" toml:"game"`
+	Stats    StatsConfig    `yaml:"stats" toml:"stats"`
+	Log      LogConfig      `yaml:"log" toml:"log"`
+	Shutdown ShutdownConfig `yaml:"shutdown" toml:"shutdown"`
+	Monitor  MonitorConfig  `yaml:"monitor" toml:"monitor"`
 }
 
+// Поддерживаемые драйверы хранилища
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Способы получения апдейтов
+const (
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
+)
+
+// Стратегии выбора задания раунда
+const (
+	TaskSelectionUniform  = "uniform"
+	TaskSelectionWeighted = "weighted"
+)
+
+// Форматы логов
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// Куда писать логи
+const (
+	LogOutputFile   = "file"
+	LogOutputStdout = "stdout"
+)
+
 type DbConfig struct {
-	Dsn string
+	Driver string `yaml:"driver" toml:"driver"` // postgres (по умолчанию) или sqlite
+	Dsn    string `yaml:"dsn" toml:"dsn"`       // строка подключения к Postgres
+	Path   string `yaml:"path" toml:"path"`     // путь к файлу SQLite
 }
 
 type TgConfig struct {
-	Token string
+	Token   string        `yaml:"token" toml:"token"`
+	APIURL  string        `yaml:"api_url" toml:"api_url"` // пусто - официальный api.telegram.org
+	Mode    string        `yaml:"mode" toml:"mode"`       // polling (по умолчанию) или webhook
+	Webhook WebhookConfig `yaml:"webhook" toml:"webhook"`
+
+	PollTimeout  time.Duration `yaml:"poll_timeout" toml:"poll_timeout"`     // long polling: сколько Telegram держит запрос
+	MaxUpdateAge time.Duration `yaml:"max_update_age" toml:"max_update_age"` // более старые сообщения отбрасываются, 0 - не отбрасывать
+}
+
+type WebhookConfig struct {
+	Listen      string `yaml:"listen" toml:"listen"`             // адрес листенера, например :8443
+	Path        string `yaml:"path" toml:"path"`                 // путь, на который Telegram шлёт апдейты
+	PublicURL   string `yaml:"public_url" toml:"public_url"`     // внешний адрес для setWebhook (за reverse proxy)
+	SecretToken string `yaml:"secret_token" toml:"secret_token"` // проверяется в заголовке X-Telegram-Bot-Api-Secret-Token
+	TLSCert     string `yaml:"tls_cert" toml:"tls_cert"`         // TLS на самом боте - пути к сертификату и ключу, сертификат загружается в Telegram
+	TLSKey      string `yaml:"tls_key" toml:"tls_key"`
 }
 
 type AdminsConfig struct {
-	AdminsID []int64
+	AdminsID []int64 `yaml:"ids" toml:"ids"`
 }
 
-func GetDsn() string {
-	env := os.Getenv("APP_ENV")
+type GameConfig struct {
+	VoteTimeout   time.Duration `yaml:"vote_timeout" toml:"vote_timeout"`     // 0 - голосование завершается только вручную
+	SubmitTimeout time.Duration `yaml:"submit_timeout" toml:"submit_timeout"` // 0 - голосование запускает админ
+	BlitzTimeout  time.Duration `yaml:"blitz_timeout" toml:"blitz_timeout"`   // время на фото в блиц-раунде, 0 - блиц как обычный раунд
+	TaskPacks     []string      `yaml:"task_packs" toml:"task_packs"`         // файлы паков заданий, можно шаблоны
+	ImportTasks   bool          `yaml:"import_tasks" toml:"import_tasks"`     // при старте синхронизировать каталог в БД с паками
 
-	host := os.Getenv("DB_HOST")
-	if host == "" {
-		host = "localhost"
-		if env == "docker" {
-			host = "postgres"
-		}
-	}
+	TaskSelection   string  `yaml:"task_selection" toml:"task_selection"`     // uniform или weighted (по статистике фото и пропусков)
+	TaskExploration float64 `yaml:"task_exploration" toml:"task_exploration"` // доля раундов с новым заданием при weighted
 
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable&TimeZone=UTC",
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		host,
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_NAME"),
-	)
+	FeedbackTimeout time.Duration `yaml:"feedback_timeout" toml:"feedback_timeout"` // сколько ждать текст отзыва после кнопки
+	AnimationFrames int           `yaml:"animation_frames" toml:"animation_frames"` // кадры анимации перед первой игрой, 0 - без неё
+	AnimationStep   time.Duration `yaml:"animation_step" toml:"animation_step"`     // пауза между кадрами
+	RevealDelay     time.Duration `yaml:"reveal_delay" toml:"reveal_delay"`         // пауза между объявлением голосования и фото
+}
+
+type StatsConfig struct {
+	BufferSize    int           `yaml:"buffer_size" toml:"buffer_size"`       // очередь записей статистики
+	FlushInterval time.Duration `yaml:"flush_interval" toml:"flush_interval"` // как часто пачка пишется в БД
+}
+
+type LogConfig struct {
+	Level  slog.Level `yaml:"level" toml:"level"`
+	Format string     `yaml:"format" toml:"format"` // text или json
+	Output string     `yaml:"output" toml:"output"` // file или stdout (docker)
+
+	File        string        `yaml:"file" toml:"file"`                 // путь к файлу лога
+	MaxSizeMB   int           `yaml:"max_size_mb" toml:"max_size_mb"`   // ротация по размеру
+	MaxAgeDays  int           `yaml:"max_age_days" toml:"max_age_days"` // сколько дней хранить старые файлы
+	MaxBackups  int           `yaml:"max_backups" toml:"max_backups"`   // сколько старых файлов хранить
+	RotateEvery time.Duration `yaml:"rotate_every" toml:"rotate_every"` // ротация по времени, 0 - только по размеру
+}
+
+type ShutdownConfig struct {
+	Timeout     time.Duration `yaml:"timeout" toml:"timeout"`           // сколько ждать завершения обработчиков и записи в БД
+	NotifyChats bool          `yaml:"notify_chats" toml:"notify_chats"` // предупредить чаты с активной игрой о перезапуске
+}
 
-	return dsn
+type MonitorConfig struct {
+	Listen string `yaml:"listen" toml:"listen"` // адрес /healthz, /readyz и /metrics, пусто или off - выключено
 }
 
-func LoadAminsID() []int64 {
-	raw := os.Getenv("ADMINS_ID")
-	if raw == "" {
-		log.Println("ADMINS_ID is not set")
-		return nil
+// Default - конфигурация без файла и переменных окружения
+func Default() *Config {
+	return &Config{
+		Db: DbConfig{
+			Driver: DriverPostgres,
+			Path:   "data/memento.db",
+		},
+		TG: TgConfig{
+			Mode: ModePolling,
+			Webhook: WebhookConfig{
+				Listen: ":8443",
+				Path:   "/telegram/webhook",
+			},
+			PollTimeout:  10 * time.Second,
+			MaxUpdateAge: 10 * time.Second,
+		},
+		Game: GameConfig{
+			TaskPacks:       []string{"assets/tasks/*.json"},
+			ImportTasks:     true,
+			BlitzTimeout:    time.Minute,
+			TaskSelection:   TaskSelectionWeighted,
+			TaskExploration: 0.2,
+			FeedbackTimeout: 10 * time.Minute,
+			AnimationFrames: 5,
+			AnimationStep:   time.Second,
+			RevealDelay:     time.Second,
+		},
+		Stats: StatsConfig{
+			BufferSize:    1024,
+			FlushInterval: 5 * time.Second,
+		},
+		Log: LogConfig{
+			Level:       slog.LevelInfo,
+			Format:      LogFormatText,
+			Output:      LogOutputFile,
+			File:        "logs/bot.log",
+			MaxSizeMB:   50,
+			MaxAgeDays:  30,
+			MaxBackups:  10,
+			RotateEvery: 24 * time.Hour,
+		},
+		Shutdown: ShutdownConfig{
+			Timeout: 30 * time.Second,
+		},
+		Monitor: MonitorConfig{
+			Listen: ":9090",
+		},
 	}
+}
 
-	admins := strings.Split(raw, ",")
-	var res []int64
+var loadEnvOnce sync.Once
 
-	for _, strID := range admins {
-		strID = strings.TrimSpace(strID)
-		if strID == "" {
-			continue
+// LoadEnv - подгружает .env вне docker-окружения. Повторные вызовы ничего не делают.
+func LoadEnv() {
+	loadEnvOnce.Do(func() {
+		if os.Getenv("APP_ENV") != "docker" {
+			_ = godotenv.Load()
 		}
-		id, err := strconv.ParseInt(strID, 10, 64)
-		if err != nil {
-			log.Printf("invalid admin ID '%s': %v", strID, err)
-			continue
+	})
+}
+
+// Load - собирает конфигурацию: умолчания, файл path (или CONFIG_FILE, если path пуст),
+// затем переменные окружения. Проверка значений - отдельно, в Validate.
+func Load(path string) (*Config, error) {
+	LoadEnv()
+
+	conf := Default()
+
+	if path == "" {
+		path = strings.TrimSpace(os.Getenv("CONFIG_FILE"))
+	}
+	if path != "" {
+		if err := conf.readFile(path); err != nil {
+			return nil, err
 		}
-		res = append(res, id)
 	}
-	return res
+
+	if err := conf.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	conf.normalize()
+	return conf, nil
 }
 
-func LoadConfig() *Config {
+// readFile - поверх текущих значений; формат по расширению, неизвестные ключи - ошибка
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
 
-	if os.Getenv("APP_ENV") != "docker" {
-		if err := godotenv.Load(); err != nil {
-			log.Println("⚠️  .env file not found, using system environment variables")
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("config file %s: %w", path, err)
 		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), c)
+		if err != nil {
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("config file %s: unknown keys %v", path, undecoded)
+		}
+	default:
+		return fmt.Errorf("config file %s: unsupported format %q (expected .yaml, .yml or .toml)", path, ext)
 	}
+	return nil
+}
 
-	token := os.Getenv("TELEGRAM_TOKEN")
-	if token == "" {
-		log.Fatal("TELEGRAM_TOKEN not set")
+// normalize - приводит значения к каноническому виду
+func (c *Config) normalize() {
+	c.Db.Driver = strings.ToLower(strings.TrimSpace(c.Db.Driver))
+	c.TG.Mode = strings.ToLower(strings.TrimSpace(c.TG.Mode))
+	c.Log.Format = strings.ToLower(strings.TrimSpace(c.Log.Format))
+	c.Log.Output = strings.ToLower(strings.TrimSpace(c.Log.Output))
+
+	if strings.EqualFold(strings.TrimSpace(c.Monitor.Listen), "off") {
+		c.Monitor.Listen = ""
 	}
+}
 
-	return &Config{
-		TG: TgConfig{
-			Token: token,
-		},
-		Db: DbConfig{
-			Dsn: GetDsn(),
-		},
-		Admin: AdminsConfig{
-			AdminsID: LoadAminsID(),
-		},
+// Warnings - допустимые, но подозрительные настройки: выводятся в лог при старте
+func (c *Config) Warnings() []string {
+	var res []string
+	if len(c.Admin.AdminsID) == 0 {
+		res = append(res, "admin.ids (ADMINS_ID) is empty: feedback is not delivered to anyone")
+	}
+	if c.TG.Mode == ModeWebhook && c.TG.Webhook.SecretToken == "" {
+		res = append(res, "telegram.webhook.secret_token (WEBHOOK_SECRET) is not set, webhook requests are not verified")
 	}
+	return res
 }
@@ -0,0 +1,218 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// applyEnv - переменные окружения поверх файла. Пустая переменная не меняет значение.
+func (c *Config) applyEnv() error {
+	var env envReader
+
+	env.str("TELEGRAM_TOKEN", &c.TG.Token)
+	env.str("TELEGRAM_API_URL", &c.TG.APIURL)
+	env.str("BOT_MODE", &c.TG.Mode)
+	env.duration("POLL_TIMEOUT", &c.TG.PollTimeout)
+	env.duration("MAX_UPDATE_AGE", &c.TG.MaxUpdateAge)
+	env.str("WEBHOOK_LISTEN", &c.TG.Webhook.Listen)
+	env.str("WEBHOOK_PATH", &c.TG.Webhook.Path)
+	env.str("WEBHOOK_URL", &c.TG.Webhook.PublicURL)
+	env.str("WEBHOOK_SECRET", &c.TG.Webhook.SecretToken)
+	env.str("WEBHOOK_TLS_CERT", &c.TG.Webhook.TLSCert)
+	env.str("WEBHOOK_TLS_KEY", &c.TG.Webhook.TLSKey)
+
+	env.str("DB_DRIVER", &c.Db.Driver)
+	env.str("DB_DSN", &c.Db.Dsn)
+	env.str("SQLITE_PATH", &c.Db.Path)
+	if c.Db.Dsn == "" && os.Getenv("DB_NAME") != "" {
+		c.Db.Dsn = GetDsn()
+	}
+
+	env.int64s("ADMINS_ID", &c.Admin.AdminsID)
+
+	env.duration("VOTE_TIMEOUT", &c.Game.VoteTimeout)
+	env.duration("SUBMIT_TIMEOUT", &c.Game.SubmitTimeout)
+	env.duration("BLITZ_TIMEOUT", &c.Game.BlitzTimeout)
+	env.strs("TASK_PACKS", &c.Game.TaskPacks)
+	env.boolean("IMPORT_TASKS", &c.Game.ImportTasks)
+	env.str("TASK_SELECTION", &c.Game.TaskSelection)
+	env.float("TASK_EXPLORATION", &c.Game.TaskExploration)
+	env.duration("FEEDBACK_TIMEOUT", &c.Game.FeedbackTimeout)
+	env.integer("ANIMATION_FRAMES", &c.Game.AnimationFrames)
+	env.duration("ANIMATION_STEP", &c.Game.AnimationStep)
+	env.duration("REVEAL_DELAY", &c.Game.RevealDelay)
+
+	env.integer("STATS_BUFFER_SIZE", &c.Stats.BufferSize)
+	env.duration("STATS_FLUSH_INTERVAL", &c.Stats.FlushInterval)
+
+	env.level("LOG_LEVEL", &c.Log.Level)
+	env.str("LOG_FORMAT", &c.Log.Format)
+	env.str("LOG_OUTPUT", &c.Log.Output)
+	env.str("LOG_FILE", &c.Log.File)
+	env.integer("LOG_MAX_SIZE_MB", &c.Log.MaxSizeMB)
+	env.integer("LOG_MAX_AGE_DAYS", &c.Log.MaxAgeDays)
+	env.integer("LOG_MAX_BACKUPS", &c.Log.MaxBackups)
+	env.duration("LOG_ROTATE_EVERY", &c.Log.RotateEvery)
+
+	env.duration("SHUTDOWN_TIMEOUT", &c.Shutdown.Timeout)
+	env.boolean("SHUTDOWN_NOTIFY", &c.Shutdown.NotifyChats)
+
+	env.str("MONITOR_LISTEN", &c.Monitor.Listen)
+
+	return errors.Join(env.errs...)
+}
+
+// GetDsn - строка подключения к Postgres из DB_HOST, DB_USER, DB_PASSWORD, DB_PORT и DB_NAME
+func GetDsn() string {
+	env := os.Getenv("APP_ENV")
+
+	host := os.Getenv("DB_HOST")
+	if host == "" {
+		host = "localhost"
+		if env == "docker" {
+			host = "postgres"
+		}
+	}
+
+	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable&TimeZone=UTC",
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		host,
+		os.Getenv("DB_PORT"),
+		os.Getenv("DB_NAME"),
+	)
+
+	return dsn
+}
+
+// envReader - разбирает переменные окружения, копя ошибки, чтобы показать их все сразу
+type envReader struct {
+	errs []error
+}
+
+func (r *envReader) lookup(key string) (string, bool) {
+	raw := strings.TrimSpace(os.Getenv(key))
+	return raw, raw != ""
+}
+
+func (r *envReader) fail(key, raw string, err error) {
+	r.errs = append(r.errs, fmt.Errorf("%s: invalid value '%s': %w", key, raw, err))
+}
+
+func (r *envReader) str(key string, dst *string) {
+	if raw, ok := r.lookup(key); ok {
+		*dst = raw
+	}
+}
+
+// duration - формат 90s, 2m
+func (r *envReader) duration(key string, dst *time.Duration) {
+	raw, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		r.fail(key, raw, err)
+		return
+	}
+	*dst = d
+}
+
+func (r *envReader) integer(key string, dst *int) {
+	raw, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		r.fail(key, raw, err)
+		return
+	}
+	*dst = v
+}
+
+func (r *envReader) float(key string, dst *float64) {
+	raw, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		r.fail(key, raw, err)
+		return
+	}
+	*dst = v
+}
+
+// boolean - true/false, 1/0
+func (r *envReader) boolean(key string, dst *bool) {
+	raw, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		r.fail(key, raw, err)
+		return
+	}
+	*dst = v
+}
+
+// strs - список через запятую
+func (r *envReader) strs(key string, dst *[]string) {
+	raw, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+
+	var res []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			res = append(res, part)
+		}
+	}
+	*dst = res
+}
+
+// int64s - список через запятую
+func (r *envReader) int64s(key string, dst *[]int64) {
+	raw, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+
+	var res []int64
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			r.fail(key, raw, err)
+			return
+		}
+		res = append(res, id)
+	}
+	*dst = res
+}
+
+// level - debug, info, warn, error
+func (r *envReader) level(key string, dst *slog.Level) {
+	raw, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(raw)); err != nil {
+		r.fail(key, raw, err)
+		return
+	}
+	*dst = level
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const secretMask = "***"
+
+// dsnPassword - пароль в DSN вида "host=... password=..."
+var dsnPassword = regexp.MustCompile(`(password=)\S+`)
+
+// Masked - копия конфигурации со скрытыми токеном, секретом вебхука и паролем БД
+func (c *Config) Masked() *Config {
+	res := *c
+	res.Admin.AdminsID = append([]int64(nil), c.Admin.AdminsID...)
+
+	// ID бота в начале токена не секрет и помогает понять, какой бот настроен
+	if id, _, ok := strings.Cut(c.TG.Token, ":"); ok {
+		res.TG.Token = id + ":" + secretMask
+	} else if c.TG.Token != "" {
+		res.TG.Token = secretMask
+	}
+	if c.TG.Webhook.SecretToken != "" {
+		res.TG.Webhook.SecretToken = secretMask
+	}
+	res.Db.Dsn = maskDsn(c.Db.Dsn)
+
+	return &res
+}
+
+func maskDsn(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			// UserPassword экранировал бы звёздочки, а Redacted ставит xxxxx
+			return strings.Replace(u.Redacted(), ":xxxxx@", ":"+secretMask+"@", 1)
+		}
+		return dsn
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}"+secretMask)
+}
+
+// Print - действующая конфигурация в YAML (формат файла конфигурации), секреты скрыты
+func (c *Config) Print(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c.Masked()); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Validate - проверяет конфигурацию целиком и возвращает все найденные ошибки.
+// В каждой указан ключ файла и переменная окружения, которой его можно задать.
+func (c *Config) Validate() error {
+	var v validator
+
+	if c.TG.Token == "" {
+		v.fail("telegram.token (TELEGRAM_TOKEN)", "required")
+	}
+	if c.TG.APIURL != "" {
+		v.url("telegram.api_url (TELEGRAM_API_URL)", c.TG.APIURL)
+	}
+	v.oneOf("telegram.mode (BOT_MODE)", c.TG.Mode, ModePolling, ModeWebhook)
+	v.positive("telegram.poll_timeout (POLL_TIMEOUT)", c.TG.PollTimeout)
+	v.nonNegative("telegram.max_update_age (MAX_UPDATE_AGE)", c.TG.MaxUpdateAge)
+
+	if c.TG.Mode == ModeWebhook {
+		hook := c.TG.Webhook
+		if hook.PublicURL == "" {
+			v.fail("telegram.webhook.public_url (WEBHOOK_URL)", "required when mode is webhook")
+		} else {
+			v.url("telegram.webhook.public_url (WEBHOOK_URL)", hook.PublicURL)
+		}
+		if hook.Listen == "" {
+			v.fail("telegram.webhook.listen (WEBHOOK_LISTEN)", "required when mode is webhook")
+		}
+		if !strings.HasPrefix(hook.Path, "/") {
+			v.fail("telegram.webhook.path (WEBHOOK_PATH)", "must start with '/'")
+		}
+		if (hook.TLSCert == "") != (hook.TLSKey == "") {
+			v.fail("telegram.webhook.tls_cert, tls_key (WEBHOOK_TLS_CERT, WEBHOOK_TLS_KEY)", "must be set together")
+		}
+	}
+
+	v.err(c.Db.Validate())
+
+	v.nonNegative("game.vote_timeout (VOTE_TIMEOUT)", c.Game.VoteTimeout)
+	v.nonNegative("game.submit_timeout (SUBMIT_TIMEOUT)", c.Game.SubmitTimeout)
+	v.nonNegative("game.blitz_timeout (BLITZ_TIMEOUT)", c.Game.BlitzTimeout)
+	// Без импорта задания берутся только из каталога в БД
+	if c.Game.ImportTasks {
+		v.files("game.task_packs (TASK_PACKS)", c.Game.TaskPacks)
+	}
+	v.oneOf("game.task_selection (TASK_SELECTION)", c.Game.TaskSelection, TaskSelectionUniform, TaskSelectionWeighted)
+	if c.Game.TaskExploration < 0 || c.Game.TaskExploration > 1 {
+		v.fail("game.task_exploration (TASK_EXPLORATION)", "must be between 0 and 1")
+	}
+	v.positive("game.feedback_timeout (FEEDBACK_TIMEOUT)", c.Game.FeedbackTimeout)
+	if c.Game.AnimationFrames < 0 {
+		v.fail("game.animation_frames (ANIMATION_FRAMES)", "must not be negative")
+	}
+	v.nonNegative("game.animation_step (ANIMATION_STEP)", c.Game.AnimationStep)
+	v.nonNegative("game.reveal_delay (REVEAL_DELAY)", c.Game.RevealDelay)
+
+	if c.Stats.BufferSize <= 0 {
+		v.fail("stats.buffer_size (STATS_BUFFER_SIZE)", "must be positive")
+	}
+	v.positive("stats.flush_interval (STATS_FLUSH_INTERVAL)", c.Stats.FlushInterval)
+
+	v.err(c.Log.Validate())
+
+	v.positive("shutdown.timeout (SHUTDOWN_TIMEOUT)", c.Shutdown.Timeout)
+
+	return v.result()
+}
+
+// Validate - настройки хранилища отдельно: утилите миграций остальное не нужно
+func (c DbConfig) Validate() error {
+	var v validator
+
+	switch c.Driver {
+	case DriverPostgres:
+		if c.Dsn == "" {
+			v.fail("db.dsn (DB_DSN or DB_USER, DB_PASSWORD, DB_NAME, DB_PORT)", "required for postgres")
+		}
+	case DriverSQLite:
+		if c.Path == "" {
+			v.fail("db.path (SQLITE_PATH)", "required for sqlite")
+		}
+	default:
+		v.oneOf("db.driver (DB_DRIVER)", c.Driver, DriverPostgres, DriverSQLite)
+	}
+
+	return v.result()
+}
+
+// Validate - настройки логов отдельно: они применяются до остальной конфигурации
+func (c LogConfig) Validate() error {
+	var v validator
+
+	v.oneOf("log.format (LOG_FORMAT)", c.Format, LogFormatText, LogFormatJSON)
+	v.oneOf("log.output (LOG_OUTPUT)", c.Output, LogOutputFile, LogOutputStdout)
+	if c.Output == LogOutputFile && c.File == "" {
+		v.fail("log.file (LOG_FILE)", "required when output is file")
+	}
+	if c.MaxSizeMB < 0 || c.MaxAgeDays < 0 || c.MaxBackups < 0 {
+		v.fail("log.max_size_mb, max_age_days, max_backups", "must not be negative")
+	}
+	v.nonNegative("log.rotate_every (LOG_ROTATE_EVERY)", c.RotateEvery)
+
+	return v.result()
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) fail(field, msg string) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", field, msg))
+}
+
+// err - ошибки вложенной проверки уже содержат имена полей
+func (v *validator) err(err error) {
+	if err != nil {
+		v.errs = append(v.errs, err)
+	}
+}
+
+func (v *validator) oneOf(field, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.fail(field, fmt.Sprintf("unknown value '%s' (expected %s)", value, strings.Join(allowed, " or ")))
+}
+
+func (v *validator) positive(field string, d time.Duration) {
+	if d <= 0 {
+		v.fail(field, "must be positive")
+	}
+}
+
+func (v *validator) nonNegative(field string, d time.Duration) {
+	if d < 0 {
+		v.fail(field, "must not be negative")
+	}
+}
+
+func (v *validator) url(field, raw string) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		v.fail(field, fmt.Sprintf("invalid URL '%s'", raw))
+	}
+}
+
+// files - каждый путь или шаблон должен находить хотя бы один файл
+func (v *validator) files(field string, patterns []string) {
+	if len(patterns) == 0 {
+		v.fail(field, "required")
+		return
+	}
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			v.fail(field, fmt.Sprintf("bad pattern '%s': %v", pattern, err))
+		} else if len(matches) == 0 {
+			v.fail(field, fmt.Sprintf("no files match '%s'", pattern))
+		}
+	}
+}
+
+func (v *validator) result() error {
+	return errors.Join(v.errs...)
+}
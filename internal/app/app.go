@@ -0,0 +1,316 @@
+// Package app - сборка бота из конфигурации: БД, репозитории, статистика,
+// события, игровой менеджер и хендлеры. Используется cmd/main.go и
+// интеграционными тестами, чтобы они запускали одно и то же.
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	messages "github.com/kiselevos/memento_game_bot/assets"
+	"github.com/kiselevos/memento_game_bot/config"
+	"github.com/kiselevos/memento_game_bot/internal/events"
+	"github.com/kiselevos/memento_game_bot/internal/feedback"
+	"github.com/kiselevos/memento_game_bot/internal/game"
+	"github.com/kiselevos/memento_game_bot/internal/handlers"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
+	"github.com/kiselevos/memento_game_bot/internal/metrics"
+	"github.com/kiselevos/memento_game_bot/internal/repositories"
+	"github.com/kiselevos/memento_game_bot/internal/stats"
+	"github.com/kiselevos/memento_game_bot/internal/tasks"
+	"github.com/kiselevos/memento_game_bot/migrations"
+	"github.com/kiselevos/memento_game_bot/pkg/db"
+
+	tb "gopkg.in/telebot.v3"
+)
+
+const updatesBufferSize = 100
+
+// catalogRefreshInterval - как часто каталог заданий перечитывает статистику из БД
+const catalogRefreshInterval = 10 * time.Minute
+
+var logger = logging.Component("app")
+
+type App struct {
+	Conf        *config.Config
+	DB          *db.Db
+	Bot         *tb.Bot
+	GameManager *game.GameManager
+	Handlers    *handlers.Handlers
+	Locales     *i18n.Locales
+
+	Metrics *metrics.Metrics
+
+	stats       *stats.Writer
+	stopRefresh func()   // останавливает обновление каталога заданий
+	monitor     *monitor // nil - мониторинг выключен
+
+	stop     chan struct{} // закрывается, когда пора прекратить приём апдейтов
+	done     chan struct{} // закрывается, когда Run дочитал все полученные апдейты
+	running  atomic.Bool
+	inFlight sync.WaitGroup // обработчики, которые ещё работают
+	stopOnce sync.Once
+	stopErr  error
+}
+
+// New - подключается к БД, проверяет схему и собирает бота. Бот ещё не запущен.
+func New(conf *config.Config) (*App, error) {
+
+	database, err := db.NewDB(conf.Db)
+	if err != nil {
+		return nil, err
+	}
+
+	// Бот не стартует на схеме, не совпадающей с его миграциями
+	if err := migrations.NewMigrator(database.DB).Check(); err != nil {
+		return nil, fmt.Errorf("%w - выполните `make migrate` (go run ./cmd/migrate up)", err)
+	}
+
+	// Repository
+	userRepo := repositories.NewUserRepository(database)
+	sessionRepo := repositories.NewSessionRepository(database)
+	taskRepo := repositories.NewTaskRepository(database)
+	snapshotRepo := repositories.NewSnapshotRepository(database)
+	chatSettingsRepo := repositories.NewChatSettingsRepository(database)
+	chatHistoryRepo := repositories.NewChatHistoryRepository(database)
+	chatTaskRepo := repositories.NewChatTaskRepository(database)
+	submissionRepo := repositories.NewTaskSubmissionRepository(database)
+
+	// Tg settings
+	pref := tb.Settings{
+		Token: conf.TG.Token,
+		URL:   conf.TG.APIURL,
+		// Обработчики запускает App.Run в своих горутинах, чтобы дождаться их при остановке
+		Synchronous: true,
+		OnError: func(err error, c tb.Context) {
+			logging.From(c).Error("Ошибка обработчика", "err", err)
+		},
+	}
+
+	b, err := tb.NewBot(pref)
+	if err != nil {
+		return nil, err
+	}
+
+	// Задания раундов берутся из каталога в БД, файлы паков только импортируются в него
+	catalog, err := tasks.NewCatalog(taskRepo)
+	if err != nil {
+		return nil, err
+	}
+	if conf.Game.ImportTasks {
+		packs, err := tasks.LoadPacks(conf.Game.TaskPacks...)
+		if err != nil {
+			return nil, err
+		}
+		res, err := catalog.Import(packs)
+		if err != nil {
+			// Остальные задания импортированы, бот работает и без проблемных
+			logger.Error("Импорт заданий завершён с ошибками", "err", err)
+		}
+		logger.Info("Каталог заданий синхронизирован с паками", "added", res.Added, "updated", res.Updated,
+			"adopted", res.Adopted, "archived", res.Archived, "skipped", res.Skipped)
+	}
+	if len(catalog.List().Packs()) == 0 {
+		return nil, fmt.Errorf("task catalogue is empty - enable game.import_tasks (IMPORT_TASKS)")
+	}
+	selector, err := tasks.NewSelector(conf.Game.TaskSelection, conf.Game.TaskExploration)
+	if err != nil {
+		return nil, err
+	}
+	catalog.List().SetSelector(selector)
+
+	m := metrics.New()
+
+	// Статистика пишется в БД пачками в фоне
+	statsWriter := stats.NewWriter(&stats.RepoStore{
+		Users:    userRepo,
+		Sessions: sessionRepo,
+		Tasks:    taskRepo,
+	}, conf.Stats.BufferSize, conf.Stats.FlushInterval, m)
+
+	bus := events.NewBus()
+	bus.Subscribe(events.LogEvents)
+	bus.Subscribe(stats.FromEvents(statsWriter))
+	bus.Subscribe(metrics.FromEvents(m))
+
+	// Инициализация GameManager
+	gm := game.NewGameManager(userRepo, sessionRepo, snapshotRepo, bus, game.Settings{
+		VoteDuration:   conf.Game.VoteTimeout,
+		SubmitDuration: conf.Game.SubmitTimeout,
+		BlitzDuration:  conf.Game.BlitzTimeout,
+	})
+	gm.Metrics = m
+	m.WatchSessions(gm.SessionsByState)
+
+	fm := feedback.NewFeedbackManager(conf.Game.FeedbackTimeout)
+
+	// Язык чата из /language, в личке - язык Telegram пользователя
+	loc := i18n.NewLocales(chatSettingsRepo, i18n.Default)
+
+	// update_id, chat_id и user_id в логах всех обработчиков
+	b.Use(logging.Middleware)
+
+	// Обработчики регистрируются через обёртку, замеряющую их время
+	h := handlers.NewHandlers(metrics.InstrumentBot(b, m), fm, conf.Admin.AdminsID, b.Me, gm, catalog, loc, chatSettingsRepo, chatHistoryRepo, chatTaskRepo, submissionRepo)
+	h.Game.AnimationFrames = conf.Game.AnimationFrames
+	h.Game.AnimationStep = conf.Game.AnimationStep
+	h.Vote.RevealDelay = conf.Game.RevealDelay
+	h.RegisterAll()
+
+	mon, err := newMonitor(conf.Monitor.Listen, m, database, b)
+	if err != nil {
+		return nil, err
+	}
+
+	// Long polling или webhook - по BOT_MODE. Последним, чтобы не занять порт зря.
+	if err := setupPoller(b, conf.TG); err != nil {
+		if mon != nil {
+			mon.listener.Close()
+		}
+		return nil, err
+	}
+
+	// Статистика копится в БД, каталог перечитывает её для выбора заданий
+	stopRefresh := catalog.RefreshEvery(catalogRefreshInterval)
+
+	return &App{
+		Conf:        conf,
+		DB:          database,
+		Bot:         b,
+		GameManager: gm,
+		Handlers:    h,
+		Locales:     loc,
+		Metrics:     m,
+		stats:       statsWriter,
+		stopRefresh: stopRefresh,
+		monitor:     mon,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}, nil
+}
+
+// Run - запускает приём апдейтов. Блокирует до Shutdown.
+// Вместо Bot.Start: тот при остановке обрывает запросы уже работающих обработчиков.
+func (a *App) Run() {
+	logger.Info("Bot starts...", "mode", a.Conf.TG.Mode)
+	a.running.Store(true)
+	defer close(a.done)
+
+	if a.monitor != nil {
+		go a.monitor.serve()
+	}
+
+	a.Handlers.ResumeTimers()
+
+	updates := make(chan tb.Update, updatesBufferSize)
+	polled := make(chan struct{})
+	go func() {
+		a.Bot.Poller.Poll(a.Bot, updates, a.stop)
+		close(polled)
+	}()
+
+	for {
+		select {
+		case u := <-updates:
+			a.dispatch(u)
+		case <-polled:
+			// Поллер остановлен - обрабатываем то, что он успел получить
+			for {
+				select {
+				case u := <-updates:
+					a.dispatch(u)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (a *App) dispatch(u tb.Update) {
+	a.inFlight.Add(1)
+	go func() {
+		defer a.inFlight.Done()
+		a.Bot.ProcessUpdate(u)
+	}()
+}
+
+// Shutdown - корректная остановка: прекращает приём апдейтов, ждёт работающие
+// обработчики, сохраняет игры, при необходимости предупреждает чаты и дописывает
+// статистику. Если ctx истёк раньше, оставшиеся шаги всё равно выполняются, а
+// возвращается ошибка. Повторный вызов возвращает результат первого.
+func (a *App) Shutdown(ctx context.Context) error {
+	a.stopOnce.Do(func() {
+		a.stopErr = a.shutdown(ctx)
+	})
+	return a.stopErr
+}
+
+func (a *App) shutdown(ctx context.Context) error {
+	logger.Info("Остановка: прекращаем приём апдейтов")
+	if a.monitor != nil {
+		a.monitor.health.SetStopping()
+	}
+	close(a.stop)
+
+	var err error
+	if a.running.Load() {
+		select {
+		case <-a.done:
+			// Новых обработчиков больше не будет - ждём текущие
+			if !waitGroup(ctx, &a.inFlight) {
+				err = fmt.Errorf("обработчики не завершились: %w", ctx.Err())
+			}
+		case <-ctx.Done():
+			err = fmt.Errorf("поллер не остановился: %w", ctx.Err())
+		}
+	}
+	if err != nil {
+		logger.Warn("Остановка не уложилась в таймаут", "err", err)
+	}
+
+	chats := a.GameManager.Shutdown()
+
+	if a.Conf.Shutdown.NotifyChats {
+		for _, chatID := range chats {
+			if _, err := a.Bot.Send(tb.ChatID(chatID), a.Locales.ForChat(chatID).T(messages.BotRestarting)); err != nil {
+				logger.Error("Не удалось предупредить чат о перезапуске", "chat_id", chatID, "err", err)
+			}
+		}
+	}
+
+	a.stopRefresh()
+	a.stats.Close()
+
+	if a.monitor != nil {
+		a.monitor.close(ctx)
+	}
+
+	logger.Info("Бот остановлен")
+	return err
+}
+
+// Stop - Shutdown без ограничения по времени
+func (a *App) Stop() {
+	_ = a.Shutdown(context.Background())
+}
+
+// waitGroup - ждёт WaitGroup не дольше ctx
+func waitGroup(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
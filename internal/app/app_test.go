@@ -0,0 +1,460 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	messages "github.com/kiselevos/memento_game_bot/assets"
+	"github.com/kiselevos/memento_game_bot/config"
+	"github.com/kiselevos/memento_game_bot/internal/fakeapi"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/internal/repositories"
+	"github.com/kiselevos/memento_game_bot/migrations"
+	"github.com/kiselevos/memento_game_bot/pkg/db"
+
+	tb "gopkg.in/telebot.v3"
+)
+
+const (
+	testToken = "42:integration"
+	wait      = 5 * time.Second
+)
+
+// startApp - бот целиком (SQLite + фейковый Bot API), как его собирает cmd/main.go.
+// tune может поменять конфиг до запуска.
+func startApp(t *testing.T, tune ...func(*config.Config)) (*App, *fakeapi.Server) {
+	t.Helper()
+
+	a, srv := newApp(t, tune...)
+	go a.Run()
+	return a, srv
+}
+
+// newApp - то же, что startApp, но без запуска приёма апдейтов
+func newApp(t *testing.T, tune ...func(*config.Config)) (*App, *fakeapi.Server) {
+	t.Helper()
+
+	srv := fakeapi.New(testToken)
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+
+	dbConf := config.DbConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "bot.db")}
+	database, err := db.NewDB(dbConf)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if _, err := migrations.NewMigrator(database.DB).Up(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	conf := config.Default()
+	conf.Db = dbConf
+	conf.TG.Token = testToken
+	conf.TG.APIURL = ts.URL
+	conf.Game.TaskPacks = []string{"../../assets/tasks/*.json"}
+	conf.Game.AnimationStep = time.Millisecond
+	conf.Game.RevealDelay = 0
+	// Паки со случайными заданиями: блиц-задание играется как обычное, чтобы сценарий не зависел от выбора
+	conf.Game.BlitzTimeout = 0
+	conf.Monitor.Listen = ""
+	for _, f := range tune {
+		f(conf)
+	}
+
+	a, err := New(conf)
+	if err != nil {
+		t.Fatalf("new app: %v", err)
+	}
+	t.Cleanup(a.Stop)
+
+	return a, srv
+}
+
+func TestNewRefusesOutdatedSchema(t *testing.T) {
+	_, err := New(&config.Config{
+		Db: config.DbConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "empty.db")},
+	})
+	if err == nil || !errors.Is(err, migrations.ErrSchemaOutdated) {
+		t.Fatalf("expected outdated schema error, got %v", err)
+	}
+}
+
+func TestFullGameThroughBotAPI(t *testing.T) {
+	a, srv := startApp(t)
+
+	chat := &tb.Chat{ID: -1001, Type: tb.ChatSuperGroup, Title: "friends"}
+	admin := &tb.User{ID: 1, FirstName: "Ann", Username: "ann"}
+	players := []*tb.User{admin, {ID: 2, FirstName: "Bob", Username: "bob"}, {ID: 3, FirstName: "Cat", Username: "cat"}}
+
+	srv.SetRole(chat.ID, srv.Me.ID, tb.Administrator)
+	srv.SetRole(chat.ID, admin.ID, tb.Creator)
+
+	step := func(name string, after int, match func(fakeapi.Message) bool) fakeapi.Message {
+		t.Helper()
+		msg, ok := srv.WaitMessage(wait, chat.ID, after, match)
+		if !ok {
+			t.Fatalf("%s: no matching message, chat: %+v", name, srv.Messages(chat.ID))
+		}
+		return msg
+	}
+	textIs := func(text string) func(fakeapi.Message) bool {
+		return func(m fakeapi.Message) bool { return m.Text == text }
+	}
+	textHas := func(prefix string) func(fakeapi.Message) bool {
+		return func(m fakeapi.Message) bool { return strings.HasPrefix(m.Text, prefix) }
+	}
+
+	cursor := srv.LastMessageID()
+	srv.SendText(chat, admin, "/startgame")
+	rules := step("rules", cursor, textIs(i18n.RU.T(messages.GameRulesText)))
+
+	startRound, ok := rules.Button("start_round")
+	if !ok {
+		t.Fatalf("rules without round button: %+v", rules.Buttons())
+	}
+	cursor = srv.LastMessageID()
+	srv.Press(admin, rules, startRound)
+	step("round", cursor, textHas(i18n.RU.T(messages.RoundStartedMessage)))
+
+	for _, p := range players {
+		cursor = srv.LastMessageID()
+		id := srv.SendPhoto(chat, p, "photo-"+p.Username)
+		step("photo of "+p.Username, cursor, func(m fakeapi.Message) bool {
+			return strings.Contains(m.Text, p.Username) && strings.Contains(m.Text, "Фото принято")
+		})
+		if m, _ := srv.Message(chat.ID, id); !m.Deleted {
+			t.Errorf("photo of %s must be deleted from the chat", p.Username)
+		}
+	}
+
+	cursor = srv.LastMessageID()
+	srv.SendText(chat, admin, "/vote")
+	step("voting", cursor, textIs(i18n.RU.T(messages.VoitingMessage)))
+
+	photos := make(map[string]fakeapi.Message)
+	for _, m := range srv.Messages(chat.ID) {
+		if m.ID > cursor && m.PhotoID != "" {
+			photos[strings.TrimPrefix(m.PhotoID, "photo-")] = m
+		}
+	}
+	if len(photos) != len(players) {
+		t.Fatalf("expected %d photos for voting, got %d", len(players), len(photos))
+	}
+
+	for i, p := range players {
+		target := photos[players[(i+1)%len(players)].Username]
+		btn, ok := target.Button("vote")
+		if !ok {
+			t.Fatalf("photo without vote button: %+v", target)
+		}
+		cursor = srv.LastMessageID()
+		srv.Press(p, target, btn)
+		step("vote of "+p.Username, cursor, textHas("@"+p.Username+" проголосовал"))
+	}
+	votesAnswered := func(s *fakeapi.Server) bool {
+		n := 0
+		for _, a := range s.Answers() {
+			if a.Text == i18n.RU.T(messages.VotedReceived) {
+				n++
+			}
+		}
+		return n == len(players)
+	}
+	if !srv.WaitFor(wait, votesAnswered) {
+		t.Errorf("expected %d accepted votes, got %+v", len(players), srv.Answers())
+	}
+
+	cursor = srv.LastMessageID()
+	srv.SendText(chat, admin, "/finishvote")
+	result := step("round result", cursor, textHas(i18n.RU.T(messages.RoundScore)))
+	if strings.Count(result.Text, "🔥") != len(players) {
+		t.Errorf("expected one fire per player, got %q", result.Text)
+	}
+
+	cursor = srv.LastMessageID()
+	srv.SendText(chat, admin, "/endgame")
+	step("final", cursor, textHas(i18n.RU.T(messages.FinalScore)))
+
+	// Остановка дописывает статистику в БД
+	a.Stop()
+
+	var users []models.User
+	a.DB.Order("tg_user_id").Find(&users)
+	if len(users) != len(players) {
+		t.Fatalf("expected %d users in db, got %d", len(players), len(users))
+	}
+	for _, u := range users {
+		if u.PhotosSent != 1 || u.UsersVote != 1 {
+			t.Errorf("unexpected stats for %s: %+v", u.UserName, u)
+		}
+	}
+}
+
+// startGame - администратор запускает игру в новом чате, возвращает сообщение с правилами
+func startGame(t *testing.T, srv *fakeapi.Server, chat *tb.Chat, admin *tb.User) fakeapi.Message {
+	t.Helper()
+
+	srv.SetRole(chat.ID, srv.Me.ID, tb.Administrator)
+	srv.SetRole(chat.ID, admin.ID, tb.Creator)
+
+	cursor := srv.LastMessageID()
+	srv.SendText(chat, admin, "/startgame")
+	rules, ok := srv.WaitMessage(wait, chat.ID, cursor, func(m fakeapi.Message) bool {
+		return m.Text == i18n.RU.T(messages.GameRulesText)
+	})
+	if !ok {
+		t.Fatalf("no rules, chat: %+v", srv.Messages(chat.ID))
+	}
+	return rules
+}
+
+// freeAddr - свободный локальный порт для листенера вебхука
+func freeAddr(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	return addr
+}
+
+func TestWebhookMode(t *testing.T) {
+	addr := freeAddr(t)
+	const path, secret = "/hook", "s3cret"
+
+	_, srv := startApp(t, func(c *config.Config) {
+		c.TG.Mode = config.ModeWebhook
+		c.TG.Webhook = config.WebhookConfig{
+			Listen:      addr,
+			Path:        path,
+			PublicURL:   "http://" + addr + path,
+			SecretToken: secret,
+		}
+	})
+
+	if got := srv.WebhookURL(); got != "http://"+addr+path {
+		t.Fatalf("webhook not registered, got %q", got)
+	}
+
+	chat := &tb.Chat{ID: -2001, Type: tb.ChatSuperGroup}
+	admin := &tb.User{ID: 1, FirstName: "Ann", Username: "ann"}
+	rules := startGame(t, srv, chat, admin)
+
+	btn, _ := rules.Button("start_round")
+	cursor := srv.LastMessageID()
+	srv.Press(admin, rules, btn)
+	if _, ok := srv.WaitMessage(wait, chat.ID, cursor, func(m fakeapi.Message) bool {
+		return strings.HasPrefix(m.Text, i18n.RU.T(messages.RoundStartedMessage))
+	}); !ok {
+		t.Fatalf("round did not start, chat: %+v", srv.Messages(chat.ID))
+	}
+
+	for _, d := range srv.Deliveries() {
+		if d.Err != nil || d.StatusCode != http.StatusOK {
+			t.Errorf("delivery of update %d failed: %+v", d.UpdateID, d)
+		}
+	}
+
+	// Чужие запросы без секрета бот не принимает
+	resp, err := http.Post("http://"+addr+path, "application/json", strings.NewReader(`{"update_id":1}`))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without secret, got %d", resp.StatusCode)
+	}
+}
+
+func TestWebhookUploadsCertificate(t *testing.T) {
+	dir := t.TempDir()
+	cert, key := filepath.Join(dir, "bot.pem"), filepath.Join(dir, "bot.key")
+	const pem = "-----BEGIN CERTIFICATE-----\nself-signed\n-----END CERTIFICATE-----\n"
+	if err := os.WriteFile(cert, []byte(pem), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(key, []byte("key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	addr := freeAddr(t)
+	_, srv := newApp(t, func(c *config.Config) {
+		c.TG.Mode = config.ModeWebhook
+		c.TG.Webhook = config.WebhookConfig{Listen: addr, Path: "/", PublicURL: "https://" + addr + "/", TLSCert: cert, TLSKey: key}
+	})
+
+	// Иначе Telegram не примет самоподписанный сертификат бота
+	if got := srv.WebhookCertificate(); got != pem {
+		t.Errorf("expected certificate uploaded with setWebhook, got %q", got)
+	}
+}
+
+func TestStaleUpdatesDropped(t *testing.T) {
+	for _, mode := range []string{config.ModePolling, config.ModeWebhook} {
+		t.Run(mode, func(t *testing.T) {
+			addr := freeAddr(t)
+			_, srv := startApp(t, func(c *config.Config) {
+				c.TG.Mode = mode
+				c.TG.Webhook = config.WebhookConfig{Listen: addr, Path: "/", PublicURL: "http://" + addr + "/"}
+			})
+
+			chat := &tb.Chat{ID: -3001, Type: tb.ChatSuperGroup}
+			admin := &tb.User{ID: 1, FirstName: "Ann", Username: "ann"}
+			srv.SetRole(chat.ID, srv.Me.ID, tb.Administrator)
+			srv.SetRole(chat.ID, admin.ID, tb.Creator)
+
+			// Команда, пролежавшая в очереди дольше maxUpdateAge, игнорируется
+			srv.SendTextAt(chat, admin, "/startgame", time.Now().Add(-time.Hour))
+			if _, ok := srv.WaitMessage(300*time.Millisecond, chat.ID, 0, func(fakeapi.Message) bool { return true }); ok {
+				t.Fatalf("bot answered a stale update: %+v", srv.Messages(chat.ID))
+			}
+
+			startGame(t, srv, chat, admin)
+		})
+	}
+}
+
+func TestShutdownWaitsForHandlersAndNotifiesChats(t *testing.T) {
+	a, srv := newApp(t, func(c *config.Config) {
+		c.Shutdown.NotifyChats = true
+	})
+
+	// Медленный обработчик, который застанет остановку
+	started := make(chan struct{})
+	a.Bot.Handle("/slow", func(c tb.Context) error {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		return c.Send("slow done")
+	})
+	go a.Run()
+
+	chat := &tb.Chat{ID: -4001, Type: tb.ChatSuperGroup}
+	admin := &tb.User{ID: 1, FirstName: "Ann", Username: "ann"}
+	startGame(t, srv, chat, admin)
+	idle := &tb.Chat{ID: -4002, Type: tb.ChatSuperGroup}
+
+	srv.SendText(chat, admin, "/slow")
+	select {
+	case <-started:
+	case <-time.After(wait):
+		t.Fatal("slow handler did not start")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), wait)
+	defer cancel()
+	if err := a.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	msgs := srv.Messages(chat.ID)
+	if len(msgs) < 2 || msgs[len(msgs)-2].Text != "slow done" || msgs[len(msgs)-1].Text != i18n.RU.T(messages.BotRestarting) {
+		t.Fatalf("expected handler reply and then restart notice, chat: %+v", msgs)
+	}
+	if len(srv.Messages(idle.ID)) != 0 {
+		t.Error("chats without a game must not be notified")
+	}
+
+	// Игра сохранена и поднимается следующим запуском
+	snapshots, err := repositories.NewSnapshotRepository(a.DB).GetAll()
+	if err != nil || len(snapshots) != 1 || snapshots[0].ChatID != chat.ID {
+		t.Fatalf("expected saved game of chat %d, got %+v (%v)", chat.ID, snapshots, err)
+	}
+
+	// Апдейты после остановки не обрабатываются
+	cursor := srv.LastMessageID()
+	srv.SendText(chat, admin, "/slow")
+	if _, ok := srv.WaitMessage(300*time.Millisecond, chat.ID, cursor, func(fakeapi.Message) bool { return true }); ok {
+		t.Error("bot must not answer after shutdown")
+	}
+}
+
+func TestMonitoringEndpoints(t *testing.T) {
+	a, srv := startApp(t, func(c *config.Config) {
+		c.Monitor.Listen = "127.0.0.1:0"
+	})
+	base := "http://" + a.monitor.Addr()
+
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(base + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if code, _ := get("/healthz"); code != http.StatusOK {
+		t.Errorf("healthz: %d", code)
+	}
+	if code, body := get("/readyz"); code != http.StatusOK || !strings.Contains(body, `"db":"ok"`) || !strings.Contains(body, `"telegram":"ok"`) {
+		t.Errorf("readyz: %d %s", code, body)
+	}
+
+	chat := &tb.Chat{ID: -5001, Type: tb.ChatSuperGroup}
+	admin := &tb.User{ID: 1, FirstName: "Ann", Username: "ann"}
+	rules := startGame(t, srv, chat, admin)
+	btn, _ := rules.Button("start_round")
+	cursor := srv.LastMessageID()
+	srv.Press(admin, rules, btn)
+	srv.WaitMessage(wait, chat.ID, cursor, func(m fakeapi.Message) bool {
+		return strings.HasPrefix(m.Text, i18n.RU.T(messages.RoundStartedMessage))
+	})
+	srv.SendPhoto(chat, admin, "photo-ann")
+	srv.WaitMessage(wait, chat.ID, cursor, func(m fakeapi.Message) bool {
+		return strings.Contains(m.Text, "Фото принято")
+	})
+
+	// Гистограмма обновляется, когда обработчик уже ответил в чат
+	var body string
+	deadline := time.Now().Add(wait)
+	for time.Now().Before(deadline) {
+		if _, body = get("/metrics"); strings.Contains(body, `handler="photo"`) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, want := range []string{
+		"memento_rounds_started_total 1",
+		"memento_photos_received_total 1",
+		`memento_active_sessions{state="round_start"} 1`,
+		`memento_handler_duration_seconds_count{handler="/startgame",result="ok"} 1`,
+		`memento_handler_duration_seconds_count{handler="photo",result="ok"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics without %q", want)
+		}
+	}
+
+	// Во время остановки бот перестаёт быть готовым
+	a.monitor.health.SetStopping()
+	if code, body := get("/readyz"); code != http.StatusServiceUnavailable || !strings.Contains(body, "stopping") {
+		t.Errorf("readyz while stopping: %d %s", code, body)
+	}
+}
+
+func TestTelegramCheckHidesToken(t *testing.T) {
+	check := telegramCheck(&tb.Bot{URL: "http://" + freeAddr(t), Token: testToken})
+
+	err := check(context.Background())
+	if err == nil {
+		t.Fatal("expected unreachable bot api")
+	}
+	if strings.Contains(err.Error(), testToken) || !strings.Contains(err.Error(), "getMe") {
+		t.Errorf("readyz error must name the method without the token: %v", err)
+	}
+}
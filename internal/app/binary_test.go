@@ -0,0 +1,122 @@
+//go:build integration
+
+package app
+
+import (
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	messages "github.com/kiselevos/memento_game_bot/assets"
+	"github.com/kiselevos/memento_game_bot/internal/fakeapi"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+
+	tb "gopkg.in/telebot.v3"
+)
+
+// Собирает настоящие бинарники и гоняет их через фейковый Bot API.
+// Запуск: go test -tags integration ./internal/app/
+func TestBinaryAgainstFakeAPI(t *testing.T) {
+	dir := t.TempDir()
+
+	build := func(out, pkg string) string {
+		t.Helper()
+		path := filepath.Join(dir, out)
+		cmd := exec.Command("go", "build", "-o", path, pkg)
+		cmd.Dir = "../.."
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("build %s: %v\n%s", pkg, err, output)
+		}
+		return path
+	}
+	botBin := build("bot", "./cmd")
+	migrateBin := build("migrate", "./cmd/migrate")
+
+	srv := fakeapi.New(testToken)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	taskPacks, _ := filepath.Abs("../../assets/tasks")
+	env := append(os.Environ(),
+		"APP_ENV=docker", // без .env
+		"DB_DRIVER=sqlite",
+		"SQLITE_PATH="+filepath.Join(dir, "bot.db"),
+		"TELEGRAM_TOKEN="+testToken,
+		"TASK_PACKS="+filepath.Join(taskPacks, "*.json"),
+		"MONITOR_LISTEN=127.0.0.1:0",
+	)
+
+	// --print-config показывает итоговую конфигурацию без токена
+	bare := exec.Command(botBin, "--print-config")
+	bare.Env = []string{"APP_ENV=docker"}
+	bare.Dir = dir
+	printed, err := bare.CombinedOutput()
+	if err == nil || !strings.Contains(string(printed), "TELEGRAM_TOKEN") {
+		t.Fatalf("print-config without env must fail validation:\n%s", printed)
+	}
+	show := exec.Command(botBin, "--print-config")
+	show.Env = env
+	printed, err = show.CombinedOutput()
+	if err != nil {
+		t.Fatalf("print-config: %v\n%s", err, printed)
+	}
+	if strings.Contains(string(printed), testToken) || !strings.Contains(string(printed), "token: 42:***") {
+		t.Fatalf("token not masked:\n%s", printed)
+	}
+
+	migrate := exec.Command(migrateBin, "up")
+	migrate.Env = env
+	if output, err := migrate.CombinedOutput(); err != nil {
+		t.Fatalf("migrate: %v\n%s", err, output)
+	}
+
+	bot := exec.Command(botBin, "-api-url", ts.URL)
+	bot.Env = env
+	bot.Dir = dir
+	if err := bot.Start(); err != nil {
+		t.Fatalf("start bot: %v", err)
+	}
+	defer func() { _ = bot.Process.Kill() }()
+
+	chat := &tb.Chat{ID: -500, Type: tb.ChatSuperGroup}
+	admin := &tb.User{ID: 1, FirstName: "Ann", Username: "ann"}
+	srv.SetRole(chat.ID, srv.Me.ID, tb.Administrator)
+	srv.SetRole(chat.ID, admin.ID, tb.Creator)
+
+	srv.SendText(chat, admin, "/startgame")
+
+	// Первая игра в чате идёт с анимацией в несколько секунд
+	rules, ok := srv.WaitMessage(15*time.Second, chat.ID, 0, func(m fakeapi.Message) bool {
+		return m.Text == i18n.RU.T(messages.GameRulesText)
+	})
+	if !ok {
+		t.Fatalf("no rules from the binary, chat: %+v", srv.Messages(chat.ID))
+	}
+
+	btn, _ := rules.Button("start_round")
+	cursor := srv.LastMessageID()
+	srv.Press(admin, rules, btn)
+	if _, ok := srv.WaitMessage(wait, chat.ID, cursor, func(m fakeapi.Message) bool {
+		return strings.HasPrefix(m.Text, i18n.RU.T(messages.RoundStartedMessage))
+	}); !ok {
+		t.Fatalf("round did not start, chat: %+v", srv.Messages(chat.ID))
+	}
+
+	// SIGTERM - бот сохраняет игру и выходит сам
+	exited := make(chan error, 1)
+	go func() { exited <- bot.Wait() }()
+	_ = bot.Process.Signal(syscall.SIGTERM)
+	select {
+	case err := <-exited:
+		if err != nil {
+			t.Fatalf("bot exited with error after SIGTERM: %v", err)
+		}
+	case <-time.After(wait):
+		t.Fatal("bot did not stop after SIGTERM")
+	}
+}
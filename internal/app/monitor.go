@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/bot"
+	"github.com/kiselevos/memento_game_bot/internal/metrics"
+	"github.com/kiselevos/memento_game_bot/pkg/db"
+
+	tb "gopkg.in/telebot.v3"
+)
+
+// monitor - HTTP-сервер /healthz, /readyz и /metrics
+type monitor struct {
+	health   *metrics.Health
+	listener net.Listener
+	srv      *http.Server
+}
+
+// newMonitor - занимает порт сразу, чтобы ошибка конфигурации всплыла при старте.
+// Пустой listen - мониторинг выключен, возвращается nil.
+func newMonitor(listen string, m *metrics.Metrics, database *db.Db, b *tb.Bot) (*monitor, error) {
+	if listen == "" {
+		return nil, nil
+	}
+
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		return nil, fmt.Errorf("monitor listen %s: %w", listen, err)
+	}
+
+	health := metrics.NewHealth(m, map[string]metrics.Check{
+		"db":       dbCheck(database),
+		"telegram": telegramCheck(b),
+	})
+	return &monitor{
+		health:   health,
+		listener: ln,
+		srv:      &http.Server{Handler: health, ReadHeaderTimeout: 10 * time.Second},
+	}, nil
+}
+
+// Addr - адрес, на котором слушает мониторинг
+func (mon *monitor) Addr() string {
+	return mon.listener.Addr().String()
+}
+
+func (mon *monitor) serve() {
+	logger.Info("Мониторинг запущен", "addr", mon.Addr())
+	if err := mon.srv.Serve(mon.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("Сервер мониторинга остановился", "err", err)
+	}
+}
+
+func (mon *monitor) close(ctx context.Context) {
+	if err := mon.srv.Shutdown(ctx); err != nil {
+		logger.Warn("Сервер мониторинга не остановился вовремя", "err", err)
+	}
+	// Если serve не запускался, Shutdown не знает о листенере
+	mon.listener.Close()
+}
+
+func dbCheck(database *db.Db) metrics.Check {
+	return func(ctx context.Context) error {
+		sqlDB, err := database.DB.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// telegramCheck - Bot API отвечает на getMe
+func telegramCheck(b *tb.Bot) metrics.Check {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.URL+"/bot"+b.Token+"/getMe", nil)
+		if err != nil {
+			return bot.WithoutURL("getMe", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("bot api unreachable: %w", bot.WithoutURL("getMe", err))
+		}
+		defer resp.Body.Close()
+
+		var res struct {
+			Ok bool `json:"ok"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil || !res.Ok {
+			return fmt.Errorf("bot api answered %s", resp.Status)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/kiselevos/memento_game_bot/config"
+	"github.com/kiselevos/memento_game_bot/internal/bot"
+	"github.com/kiselevos/memento_game_bot/internal/bot/middleware"
+
+	tb "gopkg.in/telebot.v3"
+)
+
+// setupPoller - выбирает способ получения апдейтов по конфигу и сообщает его Telegram.
+// В обоих режимах старые апдейты отбрасываются одинаково.
+func setupPoller(b *tb.Bot, conf config.TgConfig) error {
+
+	if conf.Mode != config.ModeWebhook {
+		// Telegram не отдаёт getUpdates, пока зарегистрирован вебхук
+		if err := b.RemoveWebhook(); err != nil {
+			return fmt.Errorf("delete webhook: %w", err)
+		}
+		b.Poller = middleware.DropOldMessages(&bot.LongPoller{Timeout: conf.PollTimeout}, conf.MaxUpdateAge)
+		return nil
+	}
+
+	hook := conf.Webhook
+
+	// Порт занимаем до setWebhook, чтобы не регистрировать адрес, который не слушаем
+	ln, err := net.Listen("tcp", hook.Listen)
+	if err != nil {
+		return fmt.Errorf("webhook listen %s: %w", hook.Listen, err)
+	}
+
+	// Самоподписанному сертификату Telegram поверит, только если загрузить его в setWebhook.
+	// Без TLS на боте Cert пуст и ничего не загружается.
+	err = b.SetWebhook(&tb.Webhook{
+		SecretToken: hook.SecretToken,
+		Endpoint:    &tb.WebhookEndpoint{PublicURL: hook.PublicURL, Cert: hook.TLSCert},
+	})
+	if err != nil {
+		ln.Close()
+		return fmt.Errorf("set webhook: %w", err)
+	}
+
+	b.Poller = middleware.DropOldMessages(&bot.WebhookPoller{
+		Listener:    ln,
+		Path:        hook.Path,
+		SecretToken: hook.SecretToken,
+		TLSCert:     hook.TLSCert,
+		TLSKey:      hook.TLSKey,
+	}, conf.MaxUpdateAge)
+
+	logger.Info("Webhook зарегистрирован", "addr", ln.Addr().String(), "path", hook.Path, "url", hook.PublicURL)
+	return nil
+}
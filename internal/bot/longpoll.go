@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gopkg.in/telebot.v3"
+)
+
+// pollRetryDelay - пауза после ошибки getUpdates, чтобы не долбить API в цикле
+const pollRetryDelay = time.Second
+
+// LongPoller - long polling, который при остановке сразу обрывает висящий getUpdates.
+// telebot.LongPoller дожидается конца запроса, и остановка бота затягивается на Timeout.
+// Оборванный запрос ничего не теряет: апдейты подтверждаются только следующим offset.
+type LongPoller struct {
+	Timeout time.Duration
+	Client  *http.Client // nil - http.DefaultClient
+
+	lastUpdateID int
+}
+
+// Poll - реализация telebot.Poller
+func (p *LongPoller) Poll(b *telebot.Bot, dest chan telebot.Update, stop chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for ctx.Err() == nil {
+		updates, err := p.getUpdates(ctx, b)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Error("getUpdates не выполнен", "err", err)
+			select {
+			case <-time.After(pollRetryDelay):
+			case <-ctx.Done():
+			}
+			continue
+		}
+
+		for _, u := range updates {
+			p.lastUpdateID = u.ID
+			dest <- u
+		}
+	}
+}
+
+func (p *LongPoller) getUpdates(ctx context.Context, b *telebot.Bot) ([]telebot.Update, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"offset":  p.lastUpdateID + 1,
+		"timeout": int(p.Timeout / time.Second),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.URL+"/bot"+b.Token+"/getUpdates", bytes.NewReader(body))
+	if err != nil {
+		return nil, WithoutURL("getUpdates", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, WithoutURL("getUpdates", err)
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		Ok          bool             `json:"ok"`
+		Result      []telebot.Update `json:"result"`
+		Description string           `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("bad response (%s): %w", resp.Status, err)
+	}
+	if !res.Ok {
+		return nil, fmt.Errorf("%s: %s", resp.Status, res.Description)
+	}
+	return res.Result, nil
+}
@@ -0,0 +1,31 @@
+package bot
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"gopkg.in/telebot.v3"
+)
+
+func TestLongPollerErrorHidesToken(t *testing.T) {
+	// Закрытый порт: соединение не установится, ошибка придёт от http.Client
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	b := &telebot.Bot{URL: "http://" + addr, Token: "123:secret-token"}
+	p := &LongPoller{}
+
+	_, err = p.getUpdates(context.Background(), b)
+	if err == nil {
+		t.Fatal("expected connection error")
+	}
+	if strings.Contains(err.Error(), b.Token) {
+		t.Errorf("error leaks the bot token: %v", err)
+	}
+}
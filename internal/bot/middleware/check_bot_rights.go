@@ -2,16 +2,17 @@ package middleware
 
 import (
 	"errors"
-	"log"
 
 	messages "github.com/kiselevos/memento_game_bot/assets"
 	"github.com/kiselevos/memento_game_bot/internal/botinterface"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
 
 	"gopkg.in/telebot.v3"
 )
 
-// CheckBotAdminRights - проверка является ли бот админом
-func CheckBotAdminRights(c telebot.Context, botUser *telebot.User, bot botinterface.BotInterface) error {
+// CheckBotAdminRights - проверка является ли бот админом. Ответ чату - на языке tr.
+func CheckBotAdminRights(c telebot.Context, botUser *telebot.User, bot botinterface.BotInterface, tr i18n.Lang) error {
 
 	chat := c.Chat()
 
@@ -21,14 +22,14 @@ func CheckBotAdminRights(c telebot.Context, botUser *telebot.User, bot botinterf
 
 	member, err := bot.ChatMemberOf(chat, botUser)
 	if err != nil {
-		log.Printf("[ERROR] Не удалось получить статус бота в чате: %v", err)
-		c.Send(messages.ErrorMessagesForUser)
+		logging.From(c).Error("Не удалось получить статус бота в чате", "err", err)
+		c.Send(tr.T(messages.ErrorMessagesForUser))
 		return errors.New("не удалось получить статус бота")
 	}
 
 	if member.Role != telebot.Administrator {
-		log.Printf("[WARN] Бот не является админом в чате %d (роль: %s)", chat.ID, member.Role)
-		c.Send(messages.BotIsNotAdmin)
+		logging.From(c).Warn("Бот не является админом в чате", "role", member.Role)
+		c.Send(tr.T(messages.BotIsNotAdmin))
 		return errors.New("Бот не админ")
 	}
 
@@ -1,14 +1,16 @@
 package middleware
 
 import (
-	"log"
-
+	messages "github.com/kiselevos/memento_game_bot/assets"
 	"github.com/kiselevos/memento_game_bot/internal/botinterface"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
 
 	"gopkg.in/telebot.v3"
 )
 
-func OnlyAdmins(bot botinterface.BotInterface) func(next telebot.HandlerFunc) telebot.HandlerFunc {
+// OnlyAdmins - пропускает в группах только администраторов, остальным отвечает на языке чата
+func OnlyAdmins(bot botinterface.BotInterface, loc *i18n.Locales) func(next telebot.HandlerFunc) telebot.HandlerFunc {
 	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
 		return func(c telebot.Context) error {
 			chat := c.Chat()
@@ -22,11 +24,11 @@ func OnlyAdmins(bot botinterface.BotInterface) func(next telebot.HandlerFunc) te
 			// Проверка роли пользователя
 			member, err := bot.ChatMemberOf(chat, user)
 			if err != nil {
-				log.Printf("[MIDDLEWARE] Ошибка ChatMemberOf: %v", err)
+				logging.From(c).Error("Не удалось проверить роль пользователя", "err", err)
 				// Можно тоже отправить алерт с ошибкой
 				if c.Callback() != nil {
 					return c.Respond(&telebot.CallbackResponse{
-						Text: "⚠️ Не удалось проверить доступ.",
+						Text: loc.For(c).T(messages.AccessCheckFailed),
 					})
 				}
 				return nil
@@ -39,12 +41,30 @@ func OnlyAdmins(bot botinterface.BotInterface) func(next telebot.HandlerFunc) te
 			// Если это callback, показываем алерт
 			if c.Callback() != nil {
 				return c.Respond(&telebot.CallbackResponse{
-					Text: "🚫 Только администратор может использовать эту кнопку.",
+					Text: loc.For(c).T(messages.OnlyAdminButton),
 				})
 			}
 
 			// Для обычных сообщений (на всякий случай)
-			return c.Reply("🚫 Только администратор может использовать эту команду.")
+			return c.Reply(loc.For(c).T(messages.OnlyAdminCommand))
+		}
+	}
+}
+
+// BotAdmins - пропускает только владельцев бота (AdminsID) в личке с ботом.
+// Остальным команда не видна: бот молчит, как на неизвестную команду в группе.
+func BotAdmins(adminsID []int64) func(next telebot.HandlerFunc) telebot.HandlerFunc {
+	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
+		return func(c telebot.Context) error {
+			if c.Chat().Type == telebot.ChatPrivate && c.Sender() != nil {
+				for _, id := range adminsID {
+					if id == c.Sender().ID {
+						return next(c)
+					}
+				}
+			}
+			logging.From(c).Warn("Команда владельца бота от постороннего", "text", c.Text())
+			return nil
 		}
 	}
 }
@@ -1,17 +1,19 @@
 package middleware
 
 import (
+	messages "github.com/kiselevos/memento_game_bot/assets"
 	"github.com/kiselevos/memento_game_bot/internal/botinterface"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
 
 	"gopkg.in/telebot.v3"
 )
 
 // GroupOnly - Обертка для групповых команд.
-func GroupOnly(handler telebot.HandlerFunc) telebot.HandlerFunc {
+func GroupOnly(loc *i18n.Locales, handler telebot.HandlerFunc) telebot.HandlerFunc {
 	return func(c telebot.Context) error {
 		chatType := c.Chat().Type
 		if chatType != telebot.ChatGroup && chatType != telebot.ChatSuperGroup {
-			return c.Send("Играть в одиночестве - не интересно. Добавь меня в группу друзей.")
+			return c.Send(loc.For(c).T(messages.PlayAlone))
 		}
 		return handler(c)
 	}
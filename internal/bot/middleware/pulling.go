@@ -6,14 +6,49 @@ import (
 	"gopkg.in/telebot.v3"
 )
 
-// DropOldMessages - Мидлварь для обработки longpoolinga
-func DropOldMessages(maxAge time.Duration) *telebot.MiddlewarePoller {
-	return telebot.NewMiddlewarePoller(
-		&telebot.LongPoller{Timeout: 10 * time.Second},
-		func(u *telebot.Update) bool {
+// filterPoller - как telebot.MiddlewarePoller, но при остановке дочитывает
+// апдейты, которые вложенный поллер успел получить. Иначе он может навсегда
+// зависнуть на отправке в канал, а уже подтверждённые апдейты потеряются.
+type filterPoller struct {
+	poller telebot.Poller
+	filter func(*telebot.Update) bool
+}
+
+func (p *filterPoller) Poll(b *telebot.Bot, dest chan telebot.Update, stop chan struct{}) {
+	middle := make(chan telebot.Update)
+	done := make(chan struct{})
+
+	go func() {
+		p.poller.Poll(b, middle, stop)
+		close(done)
+	}()
+
+	for {
+		select {
+		case upd := <-middle:
+			if p.filter(&upd) {
+				dest <- upd
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// DropOldMessages - Мидлварь поверх любого поллера (longpolling или webhook):
+// отбрасывает сообщения старше maxAge, накопившиеся пока бот был выключен.
+// При maxAge 0 поллер возвращается как есть.
+func DropOldMessages(poller telebot.Poller, maxAge time.Duration) telebot.Poller {
+	if maxAge <= 0 {
+		return poller
+	}
+	return &filterPoller{
+		poller: poller,
+		filter: func(u *telebot.Update) bool {
 			if u.Message != nil && time.Since(u.Message.Time()) > maxAge {
 				return false
 			}
 			return true
-		})
+		},
+	}
 }
@@ -1,47 +1,66 @@
 package bot
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
+	messages "github.com/kiselevos/memento_game_bot/assets"
 	"github.com/kiselevos/memento_game_bot/internal/botinterface"
 	"github.com/kiselevos/memento_game_bot/internal/game"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
 
 	"gopkg.in/telebot.v3"
 )
 
-const (
-	FinalScore = "🏁 Игра завершена!\n\n📊 Финальный счёт:"
-	GameScore  = "🏆 Текущий результат игры:"
-	RoundScore = "⭐ Результаты раунда:"
-)
-
-func RenderScore(title string, scores []game.PlayerScore) string {
+// RenderScore - таблица очков с заголовком title (FinalScore, GameScore или RoundScore)
+func RenderScore(tr i18n.Lang, title messages.Key, scores []game.PlayerScore) string {
 	var b strings.Builder
-	b.WriteString(fmt.Sprintf("%s\n\n", title))
+	b.WriteString(fmt.Sprintf("%s\n\n", tr.T(title)))
 	for i, ps := range scores {
-		if title == RoundScore {
-			b.WriteString(fmt.Sprintf("%d. %s - %s\n", i+1, ps.UserName, strings.Repeat("🔥", ps.Value)))
+		name := PlayerName(tr, ps.UserName)
+		if title == messages.RoundScore {
+			b.WriteString(fmt.Sprintf("%d. %s - %s\n", i+1, name, strings.Repeat("🔥", ps.Value)))
 		} else {
-			b.WriteString(fmt.Sprintf("%d. %s - %d 🔥\n", i+1, ps.UserName, ps.Value))
+			b.WriteString(fmt.Sprintf("%d. %s - %d 🔥\n", i+1, name, ps.Value))
 		}
 	}
 	return b.String()
 }
 
-// Анимация загрузки
-func WaitingAnimation(c telebot.Context, bot botinterface.BotInterface, t int) {
+// PlayerName - имя игрока, а если сессия его не знает - анонимная подпись на языке чата
+func PlayerName(tr i18n.Lang, name string) string {
+	if name == "" {
+		return tr.T(messages.AnonymousPlayer)
+	}
+	return name
+}
+
+// WithoutURL - ошибка запроса к Bot API без адреса: в *url.Error есть токен бота,
+// поэтому в логи и /readyz уходят только метод и причина
+func WithoutURL(method string, err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %w", method, urlErr.Err)
+	}
+	return err
+}
+
+// Анимация загрузки: frames кадров с паузой step
+func WaitingAnimation(c telebot.Context, bot botinterface.BotInterface, tr i18n.Lang, frames int, step time.Duration) {
 
-	steps := []string{"⏳ Подготовка", " ⏳ Подготовка.", "  ⏳ Подготовка..", "  ⏳ Подготовка..."}
+	text := tr.T(messages.Preparing)
+	steps := []string{text, " " + text + ".", "  " + text + "..", "  " + text + "..."}
 
 	msg, err := bot.Send(&telebot.Chat{ID: c.Chat().ID}, steps[0])
 	if err != nil {
 		return
 	}
 
-	for i := 0; i < t; i++ {
-		time.Sleep(1 * time.Second)
+	for i := 0; i < frames; i++ {
+		time.Sleep(step)
 		step := steps[i%len(steps)]
 		bot.Edit(msg, step)
 	}
@@ -0,0 +1,24 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	messages "github.com/kiselevos/memento_game_bot/assets"
+	"github.com/kiselevos/memento_game_bot/internal/game"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+)
+
+func TestRenderScoreLocalizesAnonymousPlayer(t *testing.T) {
+	scores := []game.PlayerScore{{UserID: 1, UserName: "@alice", Value: 2}, {UserID: 2, Value: 1}}
+
+	for _, tr := range i18n.Supported() {
+		text := RenderScore(tr, messages.GameScore, scores)
+		if !strings.Contains(text, "1. @alice - 2 🔥") {
+			t.Errorf("%s: known player missing in %q", tr, text)
+		}
+		if want := "2. " + tr.T(messages.AnonymousPlayer) + " - 1 🔥"; !strings.Contains(text, want) {
+			t.Errorf("%s: expected %q in %q", tr, want, text)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package bot
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/logging"
+
+	"gopkg.in/telebot.v3"
+)
+
+var logger = logging.Component("bot")
+
+const (
+	// SecretTokenHeader - заголовок, в котором Telegram присылает секрет вебхука
+	SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+	webhookShutdownTimeout = 5 * time.Second
+)
+
+// WebhookPoller - приём апдейтов через вебхук на собственном HTTP-листенере.
+// В отличие от telebot.Webhook отвечает Telegram кодами ошибок и корректно
+// останавливается вместе с ботом. Регистрация вебхука (setWebhook) делается отдельно.
+type WebhookPoller struct {
+	Listener    net.Listener
+	Path        string
+	SecretToken string // пусто - заголовок не проверяется
+	TLSCert     string // пути к сертификату и ключу, если TLS терминируется здесь
+	TLSKey      string
+}
+
+// Poll - реализация telebot.Poller
+func (w *WebhookPoller) Poll(b *telebot.Bot, dest chan telebot.Update, stop chan struct{}) {
+
+	mux := http.NewServeMux()
+	mux.Handle(w.Path, w.Handler(dest, stop))
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var err error
+		if w.TLSCert != "" {
+			err = srv.ServeTLS(w.Listener, w.TLSCert, w.TLSKey)
+		} else {
+			err = srv.Serve(w.Listener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Webhook listener остановился", "err", err)
+		}
+	}()
+
+	<-stop
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Warn("Webhook listener не остановился вовремя", "err", err)
+	}
+	<-done
+}
+
+// Handler - принимает апдейт от Telegram и передаёт его боту
+func (w *WebhookPoller) Handler(dest chan<- telebot.Update, stop <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+
+		if r.Method != http.MethodPost {
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if w.SecretToken != "" {
+			got := r.Header.Get(SecretTokenHeader)
+			if subtle.ConstantTimeCompare([]byte(got), []byte(w.SecretToken)) != 1 {
+				logger.Warn("Webhook: неверный секрет", "remote", r.RemoteAddr)
+				http.Error(rw, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		var update telebot.Update
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(rw, "bad update", http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case dest <- update:
+			rw.WriteHeader(http.StatusOK)
+		case <-stop:
+			// Telegram повторит доставку после рестарта
+			http.Error(rw, "shutting down", http.StatusServiceUnavailable)
+		case <-r.Context().Done():
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package bot
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/telebot.v3"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	w := &WebhookPoller{SecretToken: "s3cret"}
+	dest := make(chan telebot.Update, 1)
+	stop := make(chan struct{})
+	h := w.Handler(dest, stop)
+
+	cases := []struct {
+		name   string
+		method string
+		secret string
+		body   string
+		code   int
+	}{
+		{"get", http.MethodGet, "s3cret", "", http.StatusMethodNotAllowed},
+		{"no secret", http.MethodPost, "", `{"update_id":1}`, http.StatusUnauthorized},
+		{"wrong secret", http.MethodPost, "nope", `{"update_id":1}`, http.StatusUnauthorized},
+		{"bad json", http.MethodPost, "s3cret", `{`, http.StatusBadRequest},
+		{"ok", http.MethodPost, "s3cret", `{"update_id":7}`, http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/hook", strings.NewReader(tc.body))
+			if tc.secret != "" {
+				req.Header.Set(SecretTokenHeader, tc.secret)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tc.code {
+				t.Fatalf("expected %d, got %d", tc.code, rec.Code)
+			}
+		})
+	}
+
+	select {
+	case u := <-dest:
+		if u.ID != 7 {
+			t.Fatalf("unexpected update %+v", u)
+		}
+	default:
+		t.Fatal("accepted update was not passed to the bot")
+	}
+
+	// Во время остановки апдейт не теряется молча: Telegram получит 503 и повторит
+	close(stop)
+	dest <- telebot.Update{} // канал занят, бот апдейты уже не читает
+	req := httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(`{"update_id":8}`))
+	req.Header.Set(SecretTokenHeader, "s3cret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 on shutdown, got %d", rec.Code)
+	}
+}
+
+func TestWebhookPollStops(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &WebhookPoller{Listener: ln, Path: "/hook"}
+	dest := make(chan telebot.Update, 1)
+	stop := make(chan struct{})
+
+	done := make(chan struct{})
+	go func() {
+		w.Poll(nil, dest, stop)
+		close(done)
+	}()
+
+	resp, err := http.Post("http://"+ln.Addr().String()+"/hook", "application/json", strings.NewReader(`{"update_id":1}`))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || len(dest) != 1 {
+		t.Fatalf("update not delivered: %d", resp.StatusCode)
+	}
+
+	close(stop)
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Poll did not return after stop")
+	}
+
+	if _, err := net.Dial("tcp", ln.Addr().String()); err == nil {
+		t.Fatal("listener still open after stop")
+	}
+}
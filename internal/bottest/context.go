@@ -0,0 +1,284 @@
+package bottest
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/telebot.v3"
+)
+
+var _ telebot.Context = (*Context)(nil)
+
+var errNotSupported = errors.New("bottest: not supported by fake context")
+
+// lastMessageID - сквозная нумерация входящих сообщений в тестах
+var lastMessageID int64 = 1_000_000
+
+func nextMessageID() int {
+	return int(atomic.AddInt64(&lastMessageID, 1))
+}
+
+// GroupChat - групповой чат для сценариев
+func GroupChat(id int64) *telebot.Chat {
+	return &telebot.Chat{ID: id, Type: telebot.ChatSuperGroup, Title: "test group"}
+}
+
+// PrivateChat - личка с пользователем
+func PrivateChat(user *telebot.User) *telebot.Chat {
+	return &telebot.Chat{ID: user.ID, Type: telebot.ChatPrivate, Username: user.Username}
+}
+
+// User - пользователь с ником и именем
+func User(id int64, username string) *telebot.User {
+	return &telebot.User{ID: id, Username: username, FirstName: username}
+}
+
+// Text - входящее текстовое сообщение. Для команд заполняет Payload, как telebot.
+func Text(chat *telebot.Chat, from *telebot.User, text string) telebot.Update {
+	msg := &telebot.Message{
+		ID:       nextMessageID(),
+		Chat:     chat,
+		Sender:   from,
+		Text:     text,
+		Unixtime: time.Now().Unix(),
+	}
+	if strings.HasPrefix(text, "/") {
+		if _, payload, ok := strings.Cut(text, " "); ok {
+			msg.Payload = strings.TrimSpace(payload)
+		}
+	}
+	return telebot.Update{ID: msg.ID, Message: msg}
+}
+
+// Photo - входящее фото
+func Photo(chat *telebot.Chat, from *telebot.User, fileID string) telebot.Update {
+	msg := &telebot.Message{
+		ID:       nextMessageID(),
+		Chat:     chat,
+		Sender:   from,
+		Photo:    &telebot.Photo{File: telebot.File{FileID: fileID}},
+		Unixtime: time.Now().Unix(),
+	}
+	return telebot.Update{ID: msg.ID, Message: msg}
+}
+
+// Callback - нажатие инлайн-кнопки. Кнопку удобно брать из записанного Action.
+func Callback(chat *telebot.Chat, from *telebot.User, btn telebot.InlineButton) telebot.Update {
+	id := nextMessageID()
+	return telebot.Update{
+		ID: id,
+		Callback: &telebot.Callback{
+			ID:      strconv.Itoa(id),
+			Sender:  from,
+			Message: &telebot.Message{ID: id, Chat: chat},
+			Unique:  btn.Unique,
+			Data:    btn.Data,
+		},
+	}
+}
+
+// Context - telebot.Context поверх FakeBot. Логика чтения апдейта повторяет telebot.
+type Context struct {
+	bot *FakeBot
+	u   telebot.Update
+
+	mu    sync.RWMutex
+	store map[string]interface{}
+}
+
+func NewContext(bot *FakeBot, u telebot.Update) *Context {
+	return &Context{bot: bot, u: u}
+}
+
+// Bot - у фейкового контекста нет настоящего бота
+func (c *Context) Bot() *telebot.Bot { return nil }
+
+func (c *Context) Update() telebot.Update { return c.u }
+
+func (c *Context) Message() *telebot.Message {
+	switch {
+	case c.u.Message != nil:
+		return c.u.Message
+	case c.u.Callback != nil:
+		return c.u.Callback.Message
+	default:
+		return nil
+	}
+}
+
+func (c *Context) Callback() *telebot.Callback                 { return c.u.Callback }
+func (c *Context) Query() *telebot.Query                       { return c.u.Query }
+func (c *Context) InlineResult() *telebot.InlineResult         { return c.u.InlineResult }
+func (c *Context) ShippingQuery() *telebot.ShippingQuery       { return c.u.ShippingQuery }
+func (c *Context) PreCheckoutQuery() *telebot.PreCheckoutQuery { return c.u.PreCheckoutQuery }
+func (c *Context) Poll() *telebot.Poll                         { return c.u.Poll }
+func (c *Context) PollAnswer() *telebot.PollAnswer             { return c.u.PollAnswer }
+func (c *Context) ChatMember() *telebot.ChatMemberUpdate       { return c.u.ChatMember }
+func (c *Context) ChatJoinRequest() *telebot.ChatJoinRequest   { return c.u.ChatJoinRequest }
+func (c *Context) Topic() *telebot.Topic                       { return nil }
+func (c *Context) Boost() *telebot.BoostUpdated                { return c.u.Boost }
+func (c *Context) BoostRemoved() *telebot.BoostRemoved         { return c.u.BoostRemoved }
+
+func (c *Context) Migration() (int64, int64) {
+	if c.u.Message == nil {
+		return 0, 0
+	}
+	return c.u.Message.MigrateFrom, c.u.Message.MigrateTo
+}
+
+func (c *Context) Sender() *telebot.User {
+	switch {
+	case c.u.Callback != nil:
+		return c.u.Callback.Sender
+	case c.Message() != nil:
+		return c.Message().Sender
+	default:
+		return nil
+	}
+}
+
+func (c *Context) Chat() *telebot.Chat {
+	if m := c.Message(); m != nil {
+		return m.Chat
+	}
+	return nil
+}
+
+func (c *Context) Recipient() telebot.Recipient {
+	if chat := c.Chat(); chat != nil {
+		return chat
+	}
+	return c.Sender()
+}
+
+func (c *Context) Text() string {
+	m := c.Message()
+	if m == nil {
+		return ""
+	}
+	if m.Caption != "" {
+		return m.Caption
+	}
+	return m.Text
+}
+
+func (c *Context) Entities() telebot.Entities {
+	if m := c.Message(); m != nil {
+		return m.Entities
+	}
+	return nil
+}
+
+func (c *Context) Data() string {
+	switch {
+	case c.u.Message != nil:
+		return c.u.Message.Payload
+	case c.u.Callback != nil:
+		return c.u.Callback.Data
+	default:
+		return ""
+	}
+}
+
+func (c *Context) Args() []string {
+	switch {
+	case c.u.Message != nil:
+		if payload := strings.Trim(c.u.Message.Payload, " "); payload != "" {
+			return strings.Fields(payload)
+		}
+	case c.u.Callback != nil:
+		return strings.Split(c.u.Callback.Data, "|")
+	}
+	return nil
+}
+
+func (c *Context) Send(what interface{}, opts ...interface{}) error {
+	_, err := c.bot.Send(c.Recipient(), what, opts...)
+	return err
+}
+
+func (c *Context) Reply(what interface{}, opts ...interface{}) error {
+	if c.Message() == nil {
+		return telebot.ErrBadContext
+	}
+	_, err := c.bot.Send(c.Recipient(), what, opts...)
+	return err
+}
+
+func (c *Context) Edit(what interface{}, opts ...interface{}) error {
+	if c.u.Callback == nil || c.u.Callback.Message == nil {
+		return telebot.ErrBadContext
+	}
+	_, err := c.bot.Edit(c.u.Callback.Message, what, opts...)
+	return err
+}
+
+func (c *Context) EditOrSend(what interface{}, opts ...interface{}) error {
+	if err := c.Edit(what, opts...); err != telebot.ErrBadContext {
+		return err
+	}
+	return c.Send(what, opts...)
+}
+
+func (c *Context) EditOrReply(what interface{}, opts ...interface{}) error {
+	if err := c.Edit(what, opts...); err != telebot.ErrBadContext {
+		return err
+	}
+	return c.Reply(what, opts...)
+}
+
+func (c *Context) Delete() error {
+	msg := c.Message()
+	if msg == nil {
+		return telebot.ErrBadContext
+	}
+	return c.bot.Delete(msg)
+}
+
+func (c *Context) DeleteAfter(d time.Duration) *time.Timer {
+	return time.AfterFunc(d, func() { _ = c.Delete() })
+}
+
+func (c *Context) Respond(resp ...*telebot.CallbackResponse) error {
+	if c.u.Callback == nil {
+		return errors.New("telebot: context callback is nil")
+	}
+	return c.bot.Respond(c.u.Callback, resp...)
+}
+
+func (c *Context) RespondText(text string) error {
+	return c.Respond(&telebot.CallbackResponse{Text: text})
+}
+
+func (c *Context) RespondAlert(text string) error {
+	return c.Respond(&telebot.CallbackResponse{Text: text, ShowAlert: true})
+}
+
+func (c *Context) SendAlbum(telebot.Album, ...interface{}) error     { return errNotSupported }
+func (c *Context) Forward(telebot.Editable, ...interface{}) error    { return errNotSupported }
+func (c *Context) ForwardTo(telebot.Recipient, ...interface{}) error { return errNotSupported }
+func (c *Context) EditCaption(string, ...interface{}) error          { return errNotSupported }
+func (c *Context) Notify(telebot.ChatAction) error                   { return nil }
+func (c *Context) Ship(...interface{}) error                         { return errNotSupported }
+func (c *Context) Accept(...string) error                            { return errNotSupported }
+func (c *Context) Answer(*telebot.QueryResponse) error               { return errNotSupported }
+
+func (c *Context) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.store == nil {
+		c.store = make(map[string]interface{})
+	}
+	c.store[key] = value
+}
+
+func (c *Context) Get(key string) interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.store[key]
+}
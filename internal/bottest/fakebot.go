@@ -0,0 +1,304 @@
+// Package bottest - тестовая обвязка для хендлеров: фейковый бот, который
+// записывает всё, что бот отправил, и конструктор telebot.Context.
+package bottest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kiselevos/memento_game_bot/internal/botinterface"
+
+	"gopkg.in/telebot.v3"
+)
+
+var _ botinterface.BotInterface = (*FakeBot)(nil)
+
+type ActionKind string
+
+const (
+	ActionSend    ActionKind = "send"
+	ActionEdit    ActionKind = "edit"
+	ActionDelete  ActionKind = "delete"
+	ActionRespond ActionKind = "respond"
+)
+
+// Action - одно действие бота, как его увидел бы Telegram
+type Action struct {
+	Kind      ActionKind
+	ChatID    int64
+	MessageID int
+	Text      string         // текст сообщения или подпись к фото
+	Photo     *telebot.Photo // для отправленных фото
+	Markup    *telebot.ReplyMarkup
+	ParseMode telebot.ParseMode
+	Response  *telebot.CallbackResponse // для ответов на callback
+}
+
+// Buttons - все инлайн-кнопки действия одним списком
+func (a Action) Buttons() []telebot.InlineButton {
+	if a.Markup == nil {
+		return nil
+	}
+	var res []telebot.InlineButton
+	for _, row := range a.Markup.InlineKeyboard {
+		res = append(res, row...)
+	}
+	return res
+}
+
+// Button - кнопка с данным Unique или nil
+func (a Action) Button(unique string) *telebot.InlineButton {
+	for _, btn := range a.Buttons() {
+		if btn.Unique == unique {
+			return &btn
+		}
+	}
+	return nil
+}
+
+// FakeBot - реализация BotInterface без сети. Записывает действия
+// и умеет прогонять апдейты через зарегистрированные хендлеры.
+type FakeBot struct {
+	mu       sync.Mutex
+	actions  []Action
+	handlers map[string]telebot.HandlerFunc
+	roles    map[int64]map[int64]telebot.MemberStatus
+	nextID   int
+
+	// SendErr - если задан, Send возвращает эту ошибку
+	SendErr error
+}
+
+func NewFakeBot() *FakeBot {
+	return &FakeBot{
+		handlers: make(map[string]telebot.HandlerFunc),
+		roles:    make(map[int64]map[int64]telebot.MemberStatus),
+	}
+}
+
+// SetRole - роль пользователя в чате для ChatMemberOf (по умолчанию member)
+func (fb *FakeBot) SetRole(chatID, userID int64, role telebot.MemberStatus) {
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+
+	if fb.roles[chatID] == nil {
+		fb.roles[chatID] = make(map[int64]telebot.MemberStatus)
+	}
+	fb.roles[chatID][userID] = role
+}
+
+func (fb *FakeBot) record(a Action) {
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+	fb.actions = append(fb.actions, a)
+}
+
+func (fb *FakeBot) Send(to telebot.Recipient, what interface{}, options ...interface{}) (*telebot.Message, error) {
+	if fb.SendErr != nil {
+		return nil, fb.SendErr
+	}
+
+	chatID, err := strconv.ParseInt(to.Recipient(), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("bottest: bad recipient %q", to.Recipient())
+	}
+
+	fb.mu.Lock()
+	fb.nextID++
+	msgID := fb.nextID
+	fb.mu.Unlock()
+
+	a := Action{Kind: ActionSend, ChatID: chatID, MessageID: msgID}
+	applyOptions(&a, options)
+
+	msg := &telebot.Message{ID: msgID, Chat: &telebot.Chat{ID: chatID}}
+	switch v := what.(type) {
+	case string:
+		a.Text = v
+		msg.Text = v
+	case *telebot.Photo:
+		a.Photo = v
+		a.Text = v.Caption
+		msg.Photo = v
+		msg.Caption = v.Caption
+	default:
+		return nil, fmt.Errorf("bottest: unsupported content %T", what)
+	}
+	msg.ReplyMarkup = a.Markup
+
+	fb.record(a)
+	return msg, nil
+}
+
+func (fb *FakeBot) Edit(msg telebot.Editable, what interface{}, options ...interface{}) (*telebot.Message, error) {
+	msgID, chatID := msg.MessageSig()
+	id, _ := strconv.Atoi(msgID)
+
+	a := Action{Kind: ActionEdit, ChatID: chatID, MessageID: id}
+	applyOptions(&a, options)
+	switch v := what.(type) {
+	case string:
+		a.Text = v
+	case *telebot.ReplyMarkup:
+		a.Markup = v
+	default:
+		return nil, fmt.Errorf("bottest: unsupported edit %T", what)
+	}
+
+	fb.record(a)
+	return &telebot.Message{ID: id, Chat: &telebot.Chat{ID: chatID}, Text: a.Text}, nil
+}
+
+func (fb *FakeBot) Delete(msg telebot.Editable) error {
+	msgID, chatID := msg.MessageSig()
+	id, _ := strconv.Atoi(msgID)
+
+	fb.record(Action{Kind: ActionDelete, ChatID: chatID, MessageID: id})
+	return nil
+}
+
+func (fb *FakeBot) Respond(c *telebot.Callback, resp ...*telebot.CallbackResponse) error {
+	a := Action{Kind: ActionRespond, Response: &telebot.CallbackResponse{}}
+	if c.Message != nil && c.Message.Chat != nil {
+		a.ChatID = c.Message.Chat.ID
+	}
+	if len(resp) > 0 && resp[0] != nil {
+		a.Response = resp[0]
+		a.Text = resp[0].Text
+	}
+
+	fb.record(a)
+	return nil
+}
+
+func (fb *FakeBot) ChatMemberOf(chat telebot.Recipient, user telebot.Recipient) (*telebot.ChatMember, error) {
+	chatID, _ := strconv.ParseInt(chat.Recipient(), 10, 64)
+	userID, _ := strconv.ParseInt(user.Recipient(), 10, 64)
+
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+
+	role, ok := fb.roles[chatID][userID]
+	if !ok {
+		role = telebot.Member
+	}
+	return &telebot.ChatMember{User: &telebot.User{ID: userID}, Role: role}, nil
+}
+
+// Handle - регистрирует хендлер так же, как telebot: первый middleware - внешний
+func (fb *FakeBot) Handle(endpoint interface{}, handler telebot.HandlerFunc, m ...telebot.MiddlewareFunc) {
+	for i := len(m) - 1; i >= 0; i-- {
+		handler = m[i](handler)
+	}
+
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+	fb.handlers[endpointKey(endpoint)] = handler
+}
+
+func endpointKey(endpoint interface{}) string {
+	switch e := endpoint.(type) {
+	case string:
+		return e
+	case telebot.CallbackEndpoint:
+		return e.CallbackUnique()
+	default:
+		panic(fmt.Sprintf("bottest: unsupported endpoint %T", endpoint))
+	}
+}
+
+// Dispatch - прогоняет апдейт через зарегистрированный хендлер, как это делает telebot
+func (fb *FakeBot) Dispatch(u telebot.Update) error {
+	c := NewContext(fb, u)
+
+	fb.mu.Lock()
+	handler, ok := fb.handlers[route(u)]
+	if !ok && u.Message != nil && u.Message.Text != "" {
+		handler, ok = fb.handlers[telebot.OnText]
+	}
+	if !ok && u.Callback != nil {
+		handler, ok = fb.handlers[telebot.OnCallback]
+	}
+	fb.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return handler(c)
+}
+
+func route(u telebot.Update) string {
+	switch {
+	case u.Callback != nil:
+		return "\f" + u.Callback.Unique
+	case u.Message != nil && u.Message.Photo != nil:
+		return telebot.OnPhoto
+	case u.Message != nil && strings.HasPrefix(u.Message.Text, "/"):
+		command := strings.Fields(u.Message.Text)[0]
+		command, _, _ = strings.Cut(command, "@")
+		return command
+	}
+	return ""
+}
+
+func applyOptions(a *Action, options []interface{}) {
+	for _, opt := range options {
+		switch o := opt.(type) {
+		case *telebot.SendOptions:
+			if o.ReplyMarkup != nil {
+				a.Markup = o.ReplyMarkup
+			}
+			if o.ParseMode != "" {
+				a.ParseMode = o.ParseMode
+			}
+		case *telebot.ReplyMarkup:
+			a.Markup = o
+		case telebot.ParseMode:
+			a.ParseMode = o
+		}
+	}
+}
+
+// Actions - копия всех записанных действий
+func (fb *FakeBot) Actions() []Action {
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+
+	res := make([]Action, len(fb.actions))
+	copy(res, fb.actions)
+	return res
+}
+
+// Take - возвращает записанные действия и очищает журнал. Удобно между шагами сценария.
+func (fb *FakeBot) Take() []Action {
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+
+	res := fb.actions
+	fb.actions = nil
+	return res
+}
+
+// Filter - действия одного вида
+func Filter(actions []Action, kind ActionKind) []Action {
+	var res []Action
+	for _, a := range actions {
+		if a.Kind == kind {
+			res = append(res, a)
+		}
+	}
+	return res
+}
+
+// Texts - тексты отправленных сообщений по порядку
+func Texts(actions []Action) []string {
+	var res []string
+	for _, a := range Filter(actions, ActionSend) {
+		if a.Photo == nil {
+			res = append(res, a.Text)
+		}
+	}
+	return res
+}
@@ -0,0 +1,58 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/kiselevos/memento_game_bot/internal/logging"
+)
+
+var logger = logging.Component("events")
+
+// Handler - подписчик на события
+type Handler func(e Event)
+
+// Bus - шина доменных событий игры.
+// Подписчики вызываются синхронно в порядке подписки, как правило под блокировкой
+// сессии чата: они должны быть быстрыми и не обращаться обратно к GameManager.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		mu: sync.RWMutex{},
+	}
+}
+
+// Subscribe - добавляет подписчика на все события
+func (b *Bus) Subscribe(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// Publish - рассылает событие всем подписчикам. Паника подписчика не ломает игру.
+func (b *Bus) Publish(e Event) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		b.dispatch(h, e)
+	}
+}
+
+func (b *Bus) dispatch(h Handler, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Подписчик упал на событии", "event", e.EventName(), "panic", r)
+		}
+	}()
+	h(e)
+}
+
+// LogEvents - подписчик, который пишет все события в лог
+func LogEvents(e Event) {
+	logger.Info("Событие", "event", e.EventName(), "data", e)
+}
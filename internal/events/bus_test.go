@@ -0,0 +1,38 @@
+package events
+
+import "testing"
+
+func TestBusDeliversInOrder(t *testing.T) {
+	bus := NewBus()
+
+	var got []string
+	bus.Subscribe(func(e Event) { got = append(got, "first:"+e.EventName()) })
+	bus.Subscribe(func(e Event) { got = append(got, "second:"+e.EventName()) })
+
+	bus.Publish(GameStarted{ChatID: 1})
+	bus.Publish(GameEnded{ChatID: 1})
+
+	want := []string{"first:game_started", "second:game_started", "first:game_ended", "second:game_ended"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %s at %d, got %s", want[i], i, got[i])
+		}
+	}
+}
+
+func TestBusSurvivesPanickingSubscriber(t *testing.T) {
+	bus := NewBus()
+
+	delivered := false
+	bus.Subscribe(func(e Event) { panic("boom") })
+	bus.Subscribe(func(e Event) { delivered = true })
+
+	bus.Publish(VoteCast{ChatID: 1, VoterID: 2, TargetID: 3})
+
+	if !delivered {
+		t.Error("Subscriber after a panicking one must still receive the event")
+	}
+}
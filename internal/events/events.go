@@ -0,0 +1,64 @@
+package events
+
+// Event - доменное событие жизненного цикла игры
+type Event interface {
+	EventName() string
+}
+
+// GameStarted - в чате запущена (или перезапущена) игра
+type GameStarted struct {
+	ChatID int64
+}
+
+// RoundStarted - начался новый раунд. Task и PrevTask - ID заданий из паков,
+// PrevPhotos - сколько фото на него прислали (0 - задание пропустили).
+type RoundStarted struct {
+	ChatID     int64
+	RoundID    int64
+	Task       string
+	PrevTask   string
+	PrevPhotos int
+}
+
+// PhotoSubmitted - участник прислал фото. NewPlayer - первое фото участника в этой игре.
+type PhotoSubmitted struct {
+	ChatID    int64
+	RoundID   int64
+	UserID    int64
+	NewPlayer bool
+}
+
+// VotingStarted - открыто голосование за фото раунда
+type VotingStarted struct {
+	ChatID  int64
+	RoundID int64
+	Photos  int
+}
+
+// VoteCast - засчитан голос
+type VoteCast struct {
+	ChatID   int64
+	RoundID  int64
+	VoterID  int64
+	TargetID int64
+}
+
+// VotingFinished - голосование закрыто. Votes - сколько голосов получил каждый участник.
+type VotingFinished struct {
+	ChatID  int64
+	RoundID int64
+	Votes   map[int64]int
+}
+
+// GameEnded - игра в чате завершена
+type GameEnded struct {
+	ChatID int64
+}
+
+func (GameStarted) EventName() string    { return "game_started" }
+func (RoundStarted) EventName() string   { return "round_started" }
+func (PhotoSubmitted) EventName() string { return "photo_submitted" }
+func (VotingStarted) EventName() string  { return "voting_started" }
+func (VoteCast) EventName() string       { return "vote_cast" }
+func (VotingFinished) EventName() string { return "voting_finished" }
+func (GameEnded) EventName() string      { return "game_ended" }
@@ -0,0 +1,110 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gopkg.in/telebot.v3"
+)
+
+// params - параметры метода. telebot шлёт JSON со строковыми значениями,
+// а файлы с диска - multipart-формой; curl и браузер - форму. Содержимое
+// загруженного файла лежит в параметре с его именем.
+type params map[string]interface{}
+
+func readParams(r *http.Request) (params, error) {
+	res := params{}
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if r.ContentLength == 0 {
+			return res, nil
+		}
+		var raw interface{}
+		if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+			return nil, err
+		}
+		// getMe без параметров приходит как null
+		if m, ok := raw.(map[string]interface{}); ok {
+			res = m
+		}
+		return res, nil
+	}
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			return nil, err
+		}
+		for k, files := range r.MultipartForm.File {
+			f, err := files[0].Open()
+			if err != nil {
+				return nil, err
+			}
+			data, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				return nil, err
+			}
+			res[k] = string(data)
+		}
+	} else if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	for k := range r.Form {
+		res[k] = r.Form.Get(k)
+	}
+	return res, nil
+}
+
+func (p params) str(key string) string {
+	switch v := p[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (p params) int64(key string) (int64, error) {
+	raw := p.str(key)
+	if raw == "" {
+		return 0, fmt.Errorf("%s is empty", key)
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return v, nil
+}
+
+// markup - reply_markup приходит строкой с JSON
+func (p params) markup() (*telebot.ReplyMarkup, error) {
+	var data []byte
+	switch v := p["reply_markup"].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		data = []byte(v)
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+
+	var markup telebot.ReplyMarkup
+	if err := json.Unmarshal(data, &markup); err != nil {
+		return nil, fmt.Errorf("invalid reply_markup: %w", err)
+	}
+	return &markup, nil
+}
@@ -0,0 +1,366 @@
+// Package fakeapi - локальная замена Telegram Bot API для интеграционных тестов.
+// Сервер отвечает на методы, которыми пользуется бот, и даёт тестам
+// подкладывать апдейты от имени пользователей.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/telebot.v3"
+)
+
+// maxPollWait - верхняя граница ожидания в getUpdates, чтобы тесты не висели
+const maxPollWait = 5 * time.Second
+
+// Message - сообщение в чате: отправленное ботом или пришедшее от пользователя
+type Message struct {
+	ID        int
+	ChatID    int64
+	From      *telebot.User
+	Text      string
+	PhotoID   string
+	ParseMode string
+	Markup    *telebot.ReplyMarkup
+	Incoming  bool // от пользователя, а не от бота
+	Deleted   bool
+	Edits     int
+}
+
+// Buttons - инлайн-кнопки сообщения одним списком
+func (m Message) Buttons() []telebot.InlineButton {
+	if m.Markup == nil {
+		return nil
+	}
+	var res []telebot.InlineButton
+	for _, row := range m.Markup.InlineKeyboard {
+		res = append(res, row...)
+	}
+	return res
+}
+
+// Button - кнопка по Unique (в данных кнопки на проводе он идёт после \f)
+func (m Message) Button(unique string) (telebot.InlineButton, bool) {
+	for _, btn := range m.Buttons() {
+		data := strings.TrimPrefix(btn.Data, "\f")
+		if data == unique || strings.HasPrefix(data, unique+"|") {
+			return btn, true
+		}
+	}
+	return telebot.InlineButton{}, false
+}
+
+// Answer - ответ бота на нажатие кнопки
+type Answer struct {
+	CallbackID string
+	Text       string
+	ShowAlert  bool
+}
+
+type Server struct {
+	Token string
+	Me    telebot.User
+
+	mu            sync.Mutex
+	changed       chan struct{} // закрывается и пересоздаётся при любом изменении
+	updates       []map[string]interface{}
+	lastUpdateID  int
+	lastMessageID int
+	messages      []*Message
+	answers       []Answer
+	roles         map[int64]map[int64]telebot.MemberStatus
+	webhook       *webhook
+	deliveries    []Delivery
+}
+
+// New - сервер для бота с данным токеном
+func New(token string) *Server {
+	return &Server{
+		Token:   token,
+		Me:      telebot.User{ID: 7000000, IsBot: true, FirstName: "Memento", Username: "memento_test_bot"},
+		changed: make(chan struct{}),
+		roles:   make(map[int64]map[int64]telebot.MemberStatus),
+	}
+}
+
+// notify - будит ждущих getUpdates и WaitFor. Вызывается под s.mu.
+func (s *Server) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// SetRole - роль пользователя в чате для getChatMember (по умолчанию member)
+func (s *Server) SetRole(chatID, userID int64, role telebot.MemberStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.roles[chatID] == nil {
+		s.roles[chatID] = make(map[int64]telebot.MemberStatus)
+	}
+	s.roles[chatID][userID] = role
+}
+
+// ServeHTTP - маршрутизация /bot<token>/<method>
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	prefix := "/bot" + s.Token + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	method := strings.TrimPrefix(r.URL.Path, prefix)
+
+	params, err := readParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	var result interface{}
+	switch method {
+	case "getMe":
+		result = s.Me
+	case "getUpdates":
+		if s.WebhookURL() != "" {
+			writeError(w, http.StatusConflict, "Conflict: can't use getUpdates method while webhook is active")
+			return
+		}
+		result = s.getUpdates(r, params)
+	case "setWebhook":
+		result, err = s.setWebhook(params)
+	case "deleteWebhook":
+		result = s.deleteWebhook()
+	case "sendMessage":
+		result, err = s.send(params, params.str("text"), "")
+	case "sendPhoto":
+		result, err = s.send(params, params.str("caption"), params.str("photo"))
+	case "editMessageText":
+		result, err = s.editText(params)
+	case "deleteMessage":
+		result, err = s.deleteMessage(params)
+	case "answerCallbackQuery":
+		result = s.answerCallback(params)
+	case "getChatMember":
+		result, err = s.chatMember(params)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found: method "+method+" is not emulated")
+		return
+	}
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "result": result})
+}
+
+func writeError(w http.ResponseWriter, code int, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"ok":          false,
+		"error_code":  code,
+		"description": description,
+	})
+}
+
+// getUpdates - long polling: ждёт апдейты до timeout или пока клиент не отключится
+func (s *Server) getUpdates(r *http.Request, params params) []map[string]interface{} {
+	offset, _ := strconv.Atoi(params.str("offset"))
+	timeout, _ := strconv.Atoi(params.str("timeout"))
+
+	wait := time.Duration(timeout) * time.Second
+	if wait > maxPollWait {
+		wait = maxPollWait
+	}
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+
+	for {
+		s.mu.Lock()
+		var res []map[string]interface{}
+		for _, u := range s.updates {
+			if u["update_id"].(int) >= offset {
+				res = append(res, u)
+			}
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		if len(res) > 0 {
+			return res
+		}
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return []map[string]interface{}{}
+		case <-r.Context().Done():
+			return []map[string]interface{}{}
+		}
+	}
+}
+
+func (s *Server) send(params params, text, photoID string) (map[string]interface{}, error) {
+	chatID, err := params.int64("chat_id")
+	if err != nil {
+		return nil, err
+	}
+	markup, err := params.markup()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastMessageID++
+	msg := &Message{
+		ID:        s.lastMessageID,
+		ChatID:    chatID,
+		From:      &s.Me,
+		Text:      text,
+		PhotoID:   photoID,
+		ParseMode: params.str("parse_mode"),
+		Markup:    markup,
+	}
+	s.messages = append(s.messages, msg)
+	s.notify()
+
+	return messageJSON(msg), nil
+}
+
+func (s *Server) editText(params params) (map[string]interface{}, error) {
+	msg, err := s.lookup(params)
+	if err != nil {
+		return nil, err
+	}
+	markup, err := params.markup()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg.Text = params.str("text")
+	msg.ParseMode = params.str("parse_mode")
+	msg.Markup = markup
+	msg.Edits++
+	s.notify()
+
+	return messageJSON(msg), nil
+}
+
+func (s *Server) deleteMessage(params params) (bool, error) {
+	msg, err := s.lookup(params)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg.Deleted = true
+	s.notify()
+	return true, nil
+}
+
+func (s *Server) lookup(params params) (*Message, error) {
+	chatID, err := params.int64("chat_id")
+	if err != nil {
+		return nil, err
+	}
+	msgID, err := params.int64("message_id")
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, m := range s.messages {
+		if m.ChatID == chatID && int64(m.ID) == msgID && !m.Deleted {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("message to edit not found")
+}
+
+func (s *Server) answerCallback(params params) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.answers = append(s.answers, Answer{
+		CallbackID: params.str("callback_query_id"),
+		Text:       params.str("text"),
+		ShowAlert:  params.str("show_alert") == "true",
+	})
+	s.notify()
+	return true
+}
+
+func (s *Server) chatMember(params params) (map[string]interface{}, error) {
+	chatID, err := params.int64("chat_id")
+	if err != nil {
+		return nil, err
+	}
+	userID, err := params.int64("user_id")
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	role, ok := s.roles[chatID][userID]
+	if !ok {
+		role = telebot.Member
+	}
+	return map[string]interface{}{
+		"status": role,
+		"user":   telebot.User{ID: userID},
+	}, nil
+}
+
+func messageJSON(m *Message) map[string]interface{} {
+	res := map[string]interface{}{
+		"message_id": m.ID,
+		"date":       time.Now().Unix(),
+		"chat":       map[string]interface{}{"id": m.ChatID, "type": chatType(m.ChatID)},
+	}
+	if m.From != nil {
+		res["from"] = m.From
+	}
+	if m.PhotoID != "" {
+		res["photo"] = []map[string]interface{}{{
+			"file_id":        m.PhotoID,
+			"file_unique_id": m.PhotoID,
+			"width":          1280,
+			"height":         960,
+		}}
+		if m.Text != "" {
+			res["caption"] = m.Text
+		}
+	} else if m.Text != "" {
+		res["text"] = m.Text
+	}
+	if m.Markup != nil {
+		res["reply_markup"] = m.Markup
+	}
+	return res
+}
+
+// chatType - как в Telegram: у групп отрицательные ID
+func chatType(chatID int64) telebot.ChatType {
+	if chatID < 0 {
+		return telebot.ChatSuperGroup
+	}
+	return telebot.ChatPrivate
+}
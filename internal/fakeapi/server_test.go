@@ -0,0 +1,133 @@
+package fakeapi
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gopkg.in/telebot.v3"
+)
+
+const testToken = "123:test"
+
+func newTestBot(t *testing.T) (*Server, *telebot.Bot) {
+	t.Helper()
+
+	srv := New(testToken)
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+
+	b, err := telebot.NewBot(telebot.Settings{
+		Token:  testToken,
+		URL:    ts.URL,
+		Poller: &telebot.LongPoller{Timeout: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("new bot: %v", err)
+	}
+	return srv, b
+}
+
+func TestServerBotMethods(t *testing.T) {
+	srv, b := newTestBot(t)
+
+	if b.Me.Username != srv.Me.Username {
+		t.Fatalf("getMe: expected %s, got %s", srv.Me.Username, b.Me.Username)
+	}
+
+	chat := &telebot.Chat{ID: -1, Type: telebot.ChatSuperGroup}
+	btn := telebot.InlineButton{Unique: "vote", Text: "Голосовать", Data: "1|2"}
+	markup := &telebot.ReplyMarkup{InlineKeyboard: [][]telebot.InlineButton{{btn}}}
+
+	msg, err := b.Send(chat, "<b>привет</b>", telebot.ModeHTML, markup)
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if got, ok := srv.Messages(chat.ID)[0].Button("vote"); !ok || got.Data != "\fvote|1|2" {
+		t.Errorf("unexpected button: %+v", srv.Messages(chat.ID)[0].Buttons())
+	}
+	if _, err := b.Send(chat, &telebot.Photo{File: telebot.File{FileID: "ph1"}}); err != nil {
+		t.Fatalf("send photo: %v", err)
+	}
+
+	// Как и в Telegram, правка текста без разметки убирает клавиатуру
+	if _, err := b.Edit(msg, "пока"); err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+
+	msgs := srv.Messages(chat.ID)
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(msgs))
+	}
+	if msgs[0].Text != "пока" || msgs[0].Edits != 1 || msgs[0].Markup != nil {
+		t.Errorf("edit not applied: %+v", msgs[0])
+	}
+	if msgs[1].PhotoID != "ph1" {
+		t.Errorf("expected photo, got %+v", msgs[1])
+	}
+
+	if err := b.Delete(msg); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if m, _ := srv.Message(chat.ID, msg.ID); !m.Deleted {
+		t.Error("message must be deleted")
+	}
+	if _, err := b.Edit(msg, "снова"); err == nil {
+		t.Error("expected error editing deleted message")
+	}
+
+	srv.SetRole(chat.ID, 5, telebot.Creator)
+	member, err := b.ChatMemberOf(chat, &telebot.User{ID: 5})
+	if err != nil || member.Role != telebot.Creator {
+		t.Errorf("getChatMember: %v %+v", err, member)
+	}
+	member, _ = b.ChatMemberOf(chat, &telebot.User{ID: 6})
+	if member.Role != telebot.Member {
+		t.Errorf("expected member by default, got %s", member.Role)
+	}
+}
+
+func TestServerUpdates(t *testing.T) {
+	srv, b := newTestBot(t)
+
+	chat := &telebot.Chat{ID: -1, Type: telebot.ChatSuperGroup}
+	user := &telebot.User{ID: 5, FirstName: "Ann", Username: "ann"}
+
+	b.Handle("/ping", func(c telebot.Context) error {
+		return c.Send("pong " + c.Message().Payload)
+	})
+	b.Handle(telebot.OnPhoto, func(c telebot.Context) error {
+		return c.Bot().Delete(c.Message())
+	})
+	b.Handle(&telebot.InlineButton{Unique: "vote"}, func(c telebot.Context) error {
+		return c.Respond(&telebot.CallbackResponse{Text: "got " + c.Data(), ShowAlert: true})
+	})
+
+	go b.Start()
+	defer b.Stop()
+
+	srv.SendText(chat, user, "/ping 42")
+	reply, ok := srv.WaitMessage(2*time.Second, chat.ID, 0, func(m Message) bool { return m.Text != "" })
+	if !ok || reply.Text != "pong 42" {
+		t.Fatalf("expected pong, got %+v", reply)
+	}
+
+	photoID := srv.SendPhoto(chat, user, "ph")
+	deleted := srv.WaitFor(2*time.Second, func(s *Server) bool {
+		m, _ := s.Message(chat.ID, photoID)
+		return m.Deleted
+	})
+	if !deleted {
+		t.Error("photo must be deleted by the bot")
+	}
+
+	btn := telebot.InlineButton{Data: "\fvote|7"}
+	srv.Press(user, reply, btn)
+	answered := srv.WaitFor(2*time.Second, func(s *Server) bool { return len(s.Answers()) == 1 })
+	if !answered {
+		t.Fatal("callback not answered")
+	}
+	if a := srv.Answers()[0]; a.Text != "got 7" || !a.ShowAlert {
+		t.Errorf("unexpected answer %+v", a)
+	}
+}
@@ -0,0 +1,186 @@
+package fakeapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/telebot.v3"
+)
+
+// Методы ниже - действия симулированных пользователей и проверки для тестов.
+
+// pushUpdate - кладёт апдейт в очередь getUpdates или отправляет на вебхук
+func (s *Server) pushUpdate(kind string, payload map[string]interface{}) int {
+	s.lastUpdateID++
+	update := map[string]interface{}{
+		"update_id": s.lastUpdateID,
+		kind:        payload,
+	}
+
+	if s.webhook != nil {
+		s.webhook.queue <- update
+	} else {
+		s.updates = append(s.updates, update)
+	}
+	s.notify()
+	return s.lastUpdateID
+}
+
+func (s *Server) userMessage(chat *telebot.Chat, from *telebot.User, at time.Time) (*Message, map[string]interface{}) {
+	s.lastMessageID++
+	msg := &Message{ID: s.lastMessageID, ChatID: chat.ID, From: from, Incoming: true}
+	s.messages = append(s.messages, msg)
+
+	return msg, map[string]interface{}{
+		"message_id": msg.ID,
+		"date":       at.Unix(),
+		"chat":       chat,
+		"from":       from,
+	}
+}
+
+// SendText - пользователь пишет в чат. Возвращает ID сообщения.
+func (s *Server) SendText(chat *telebot.Chat, from *telebot.User, text string) int {
+	return s.SendTextAt(chat, from, text, time.Now())
+}
+
+// SendTextAt - сообщение с заданной датой, например пролежавшее в очереди, пока бот был выключен
+func (s *Server) SendTextAt(chat *telebot.Chat, from *telebot.User, text string, at time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, payload := s.userMessage(chat, from, at)
+	msg.Text = text
+	payload["text"] = text
+
+	// Команды Telegram помечает сущностью bot_command
+	if strings.HasPrefix(text, "/") {
+		length := len(text)
+		if i := strings.IndexByte(text, ' '); i > 0 {
+			length = i
+		}
+		payload["entities"] = []map[string]interface{}{{"type": "bot_command", "offset": 0, "length": length}}
+	}
+
+	s.pushUpdate("message", payload)
+	return msg.ID
+}
+
+// SendPhoto - пользователь присылает фото. Возвращает ID сообщения.
+func (s *Server) SendPhoto(chat *telebot.Chat, from *telebot.User, fileID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, payload := s.userMessage(chat, from, time.Now())
+	msg.PhotoID = fileID
+	payload["photo"] = []map[string]interface{}{{
+		"file_id":        fileID,
+		"file_unique_id": fileID,
+		"width":          1280,
+		"height":         960,
+	}}
+
+	s.pushUpdate("message", payload)
+	return msg.ID
+}
+
+// Press - пользователь нажимает кнопку под сообщением бота. Возвращает ID callback.
+func (s *Server) Press(from *telebot.User, msg Message, btn telebot.InlineButton) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := strconv.Itoa(s.lastUpdateID + 1)
+	s.pushUpdate("callback_query", map[string]interface{}{
+		"id":            id,
+		"from":          from,
+		"message":       messageJSON(&msg),
+		"chat_instance": fmt.Sprint(msg.ChatID),
+		"data":          btn.Data,
+	})
+	return id
+}
+
+// Messages - все сообщения бота в чате по порядку
+func (s *Server) Messages(chatID int64) []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []Message
+	for _, m := range s.messages {
+		if m.ChatID == chatID && !m.Incoming {
+			res = append(res, *m)
+		}
+	}
+	return res
+}
+
+// Message - сообщение по ID (в том числе пользовательское)
+func (s *Server) Message(chatID int64, id int) (Message, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, m := range s.messages {
+		if m.ChatID == chatID && m.ID == id {
+			return *m, true
+		}
+	}
+	return Message{}, false
+}
+
+// Answers - ответы бота на нажатия кнопок
+func (s *Server) Answers() []Answer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]Answer, len(s.answers))
+	copy(res, s.answers)
+	return res
+}
+
+// WaitFor - ждёт, пока условие над состоянием сервера станет истинным.
+// Бот обрабатывает апдейты асинхронно, поэтому тесты проверяют результат через ожидание.
+func (s *Server) WaitFor(timeout time.Duration, cond func(s *Server) bool) bool {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		s.mu.Lock()
+		changed := s.changed
+		s.mu.Unlock()
+
+		if cond(s) {
+			return true
+		}
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return cond(s)
+		}
+	}
+}
+
+// WaitMessage - ждёт сообщение бота в чате, подходящее под условие,
+// среди сообщений с ID больше after. Удобно шагать по сценарию.
+func (s *Server) WaitMessage(timeout time.Duration, chatID int64, after int, match func(Message) bool) (Message, bool) {
+	var found Message
+	ok := s.WaitFor(timeout, func(s *Server) bool {
+		for _, m := range s.Messages(chatID) {
+			if m.ID > after && match(m) {
+				found = m
+				return true
+			}
+		}
+		return false
+	})
+	return found, ok
+}
+
+// LastMessageID - курсор для WaitMessage
+func (s *Server) LastMessageID() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastMessageID
+}
@@ -0,0 +1,121 @@
+package fakeapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// webhookQueueSize - сколько апдейтов может ждать доставки на вебхук
+const webhookQueueSize = 1024
+
+// Delivery - результат доставки апдейта на вебхук бота
+type Delivery struct {
+	UpdateID   int
+	StatusCode int
+	Err        error
+}
+
+// webhook - зарегистрированный ботом вебхук. Апдейты доставляются по одному
+// в порядке поступления, как это делает Telegram.
+type webhook struct {
+	url    string
+	secret string
+	cert   string // загруженный самоподписанный сертификат
+	queue  chan map[string]interface{}
+}
+
+func (s *Server) setWebhook(params params) (bool, error) {
+	hook := &webhook{
+		url:    params.str("url"),
+		secret: params.str("secret_token"),
+		cert:   params.str("certificate"),
+		queue:  make(chan map[string]interface{}, webhookQueueSize),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.webhook != nil {
+		close(s.webhook.queue)
+	}
+	s.webhook = hook
+	go s.deliver(hook)
+
+	s.notify()
+	return true, nil
+}
+
+func (s *Server) deleteWebhook() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.webhook != nil {
+		close(s.webhook.queue)
+		s.webhook = nil
+	}
+	s.notify()
+	return true
+}
+
+// WebhookURL - адрес, который бот зарегистрировал через setWebhook
+func (s *Server) WebhookURL() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.webhook == nil {
+		return ""
+	}
+	return s.webhook.url
+}
+
+// WebhookCertificate - сертификат, загруженный вместе с вебхуком, пусто - без него
+func (s *Server) WebhookCertificate() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.webhook == nil {
+		return ""
+	}
+	return s.webhook.cert
+}
+
+// Deliveries - результаты всех доставок на вебхук
+func (s *Server) Deliveries() []Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]Delivery, len(s.deliveries))
+	copy(res, s.deliveries)
+	return res
+}
+
+func (s *Server) deliver(hook *webhook) {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	for update := range hook.queue {
+		body, _ := json.Marshal(update)
+
+		d := Delivery{UpdateID: update["update_id"].(int)}
+		req, err := http.NewRequest(http.MethodPost, hook.url, bytes.NewReader(body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+			if hook.secret != "" {
+				req.Header.Set("X-Telegram-Bot-Api-Secret-Token", hook.secret)
+			}
+
+			var resp *http.Response
+			if resp, err = client.Do(req); err == nil {
+				d.StatusCode = resp.StatusCode
+				resp.Body.Close()
+			}
+		}
+		d.Err = err
+
+		s.mu.Lock()
+		s.deliveries = append(s.deliveries, d)
+		s.notify()
+		s.mu.Unlock()
+	}
+}
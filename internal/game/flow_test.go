@@ -0,0 +1,167 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/events"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/internal/repositories/memory"
+	"github.com/kiselevos/memento_game_bot/internal/stats"
+	"github.com/kiselevos/memento_game_bot/internal/tasks"
+
+	"gopkg.in/telebot.v3"
+)
+
+// Полная партия на репозиториях в памяти: от старта игры до её завершения
+func TestFullGameFlow(t *testing.T) {
+	users := memory.NewUserRepo()
+	sessions := memory.NewSessionRepo()
+	taskRepo := memory.NewTaskRepo()
+	snapshots := memory.NewSnapshotRepo()
+
+	// Задания приходят в БД импортом каталога до начала игры
+	for _, id := range []string{"t1", "t2", "t3", "t4"} {
+		if _, err := taskRepo.Create(models.NewTask(id, testTask(id).TextFor(i18n.Default))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writer := stats.NewWriter(&stats.RepoStore{Users: users, Sessions: sessions, Tasks: taskRepo}, 100, time.Hour, nil)
+	bus := events.NewBus()
+	bus.Subscribe(stats.FromEvents(writer))
+
+	gm := NewGameManager(users, sessions, snapshots, bus, Settings{SubmitDuration: time.Hour})
+
+	const game = 5555
+	alice := &telebot.User{ID: 1, Username: "alice"}
+	bob := &telebot.User{ID: 2, FirstName: "Bob"}
+	carol := &telebot.User{ID: 3, Username: "carol"}
+
+	if !gm.CheckFirstGame(game) {
+		t.Fatal("Expected first game in a new chat")
+	}
+
+	session := gm.StartNewGameSession(game)
+	if gm.CheckFirstGame(game) {
+		t.Error("Chat already has a game")
+	}
+
+	// Раунд 1
+	if err := gm.StartNewRound(session, testTask("t1")); err != nil {
+		t.Fatalf("StartNewRound failed: %v", err)
+	}
+	for _, u := range []*telebot.User{alice, bob, carol} {
+		_, allIn, err := gm.TakePhoto(game, u, "photo_"+u.Recipient())
+		if err != nil {
+			t.Fatalf("TakePhoto failed: %v", err)
+		}
+		if allIn {
+			t.Error("First round has no known players, must wait for the deadline")
+		}
+	}
+	if _, _, err := gm.TakePhoto(game, alice, "again"); err == nil {
+		t.Error("Second photo from the same user must be rejected")
+	}
+
+	if err := gm.StartVoting(session, session.CurrentRoundID()); err != nil {
+		t.Fatalf("StartVoting failed: %v", err)
+	}
+	if _, _, err := gm.TakePhoto(game, &telebot.User{ID: 4}, "late"); err == nil {
+		t.Error("Photos must not be accepted during voting")
+	}
+
+	photoOf := make(map[int64]int)
+	for _, p := range session.VotePhotos() {
+		photoOf[p.UserID] = p.Index
+	}
+	roundID := session.CurrentRoundID()
+
+	for _, vote := range []struct {
+		voter  *telebot.User
+		target int64
+	}{{alice, bob.ID}, {carol, bob.ID}, {bob, alice.ID}} {
+		res, err := gm.RegisterVote(game, roundID, vote.voter, photoOf[vote.target])
+		if err != nil || res.IsCallback {
+			t.Fatalf("Vote %d -> %d rejected: %+v %v", vote.voter.ID, vote.target, res, err)
+		}
+	}
+
+	if !gm.FinishVoting(session) {
+		t.Fatal("FinishVoting failed")
+	}
+
+	round := session.RoundScore()
+	if round[0].UserID != bob.ID || round[0].Value != 2 || round[0].UserName != "Bob" {
+		t.Errorf("Unexpected round winner: %+v", round[0])
+	}
+
+	// Раунд 2: все участники известны, последний присланный снимок открывает голосование
+	if err := gm.StartNewRound(session, testTask("t2")); err != nil {
+		t.Fatalf("StartNewRound failed: %v", err)
+	}
+	allIn := false
+	for _, u := range []*telebot.User{alice, bob, carol} {
+		var err error
+		if _, allIn, err = gm.TakePhoto(game, u, "p"); err != nil {
+			t.Fatalf("TakePhoto failed: %v", err)
+		}
+	}
+	if !allIn {
+		t.Error("Expected all players submitted in the second round")
+	}
+
+	// Раунд 3 без фото - задание 2 засчитано, задание 3 будет пропущено
+	if err := gm.StartNewRound(session, testTask("t3")); err != nil {
+		t.Fatalf("New round during photo collection must be allowed: %v", err)
+	}
+	if err := gm.StartNewRound(session, testTask("t4")); err != nil {
+		t.Fatalf("StartNewRound failed: %v", err)
+	}
+
+	total := session.TotalScore()
+	if total[0].UserID != bob.ID || total[0].Value != 2 {
+		t.Errorf("Unexpected leader: %+v", total[0])
+	}
+
+	gm.EndGame(game)
+	writer.Close()
+
+	if _, exist := gm.GetSession(game); exist {
+		t.Error("Session must be removed after EndGame")
+	}
+	if all, _ := snapshots.GetAll(); len(all) != 0 {
+		t.Errorf("Snapshots must be removed after EndGame, got %d", len(all))
+	}
+
+	// Статистика дошла до репозиториев
+	u, err := users.GetUserByTGID(alice.ID)
+	if err != nil {
+		t.Fatalf("User not created: %v", err)
+	}
+	if u.GamesPlayed != 1 || u.PhotosSent != 2 || u.UsersVote != 1 {
+		t.Errorf("Unexpected user stats: games %d photos %d votes %d", u.GamesPlayed, u.PhotosSent, u.UsersVote)
+	}
+
+	history := sessions.Sessions(game)
+	if len(history) != 1 || history[0].IsActive || history[0].PhotosCount != 6 || len(history[0].Users) != 3 {
+		t.Errorf("Unexpected session record: %+v", history)
+	}
+
+	// Статистика копится по ID задания, текст хранится для людей
+	for id, want := range map[string][2]int{"t1": {1, 0}, "t2": {1, 0}, "t3": {0, 1}} {
+		task, err := taskRepo.GetTaskByCode(id)
+		if err != nil {
+			t.Fatalf("Task %q not stored: %v", id, err)
+		}
+		if task.Text != "Задание "+id || task.UseCount != want[0] || task.SkipCount != want[1] {
+			t.Errorf("Task %q: expected use/skip %v, got %+v", id, want, task)
+		}
+	}
+}
+
+// testTask - задание из пака с текстом на языке по умолчанию
+func testTask(id string) tasks.Task {
+	return tasks.Task{ID: id, Text: map[i18n.Lang]string{i18n.Default: "Задание " + id}}
+}
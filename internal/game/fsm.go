@@ -2,7 +2,6 @@ package game
 
 import (
 	"fmt"
-	"log"
 )
 
 type State string
@@ -51,7 +50,7 @@ func (f *FSM) Current() State {
 func (f *FSM) Trigger(event Event) error {
 	next, ok := f.transistions[f.current][event]
 	if !ok {
-		log.Printf("[FSM] %s --(%s)--> %s", f.current, event, next)
+		logger.Debug("FSM: нет перехода", "state", f.current, "event", event)
 		return fmt.Errorf("invalid transition: %s → (%s)", f.current, event)
 	}
 	f.current = next
@@ -62,8 +61,7 @@ func (f *FSM) Trigger(event Event) error {
 func SafeTrigger(fsm *FSM, event Event, context string) bool {
 	err := fsm.Trigger(event)
 	if err != nil {
-		log.Printf("[FSM][WARN] %s: переход не выполнен (%s → %s): %v",
-			context, fsm.Current(), event, err)
+		logger.Warn("FSM: переход не выполнен", "caller", context, "state", fsm.Current(), "event", event, "err", err)
 		return false
 	}
 	return true
@@ -3,56 +3,192 @@ package game
 import (
 	"errors"
 	"fmt"
-	"log"
+	"sort"
 	"sync"
+	"time"
 
 	messages "github.com/kiselevos/memento_game_bot/assets"
+	"github.com/kiselevos/memento_game_bot/internal/events"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
+	"github.com/kiselevos/memento_game_bot/internal/metrics"
 	"github.com/kiselevos/memento_game_bot/internal/models"
 	"github.com/kiselevos/memento_game_bot/internal/repositories"
+	"github.com/kiselevos/memento_game_bot/internal/tasks"
 
 	"gopkg.in/telebot.v3"
 	"gorm.io/gorm"
 )
 
-// GameManager - управляет активными игровыми сессиями
+var logger = logging.Component("game")
+
+// ErrSessionClosed - игра завершена или заменена новой, событие старой сессии отклонено
+var ErrSessionClosed = errors.New("игра уже завершена")
+
+// ErrStaleRound - событие относится к раунду, который уже сменился
+var ErrStaleRound = errors.New("раунд уже сменился")
+
+// Settings - настраиваемые параметры игры
+type Settings struct {
+	VoteDuration   time.Duration // Длительность голосования, 0 - завершать только вручную
+	SubmitDuration time.Duration // Время на отправку фото, 0 - голосование запускается вручную
+	BlitzDuration  time.Duration // Время на фото в блиц-раунде, 0 - блиц идёт как обычный раунд
+}
+
+// SubmitWarning - за сколько до конца приёма фото предупредить чат
+const SubmitWarning = time.Minute
+
+// BlitzBonusPlaces - сколько самых быстрых участников блица получают очко за скорость
+const BlitzBonusPlaces = 3
+
+// VoteCountdownTick - как часто обновляется сообщение с обратным отсчётом голосования
+const VoteCountdownTick = 10 * time.Second
+
+// GameManager - управляет активными игровыми сессиями.
+// mu защищает только мапу сессий: события одного чата сериализуются
+// блокировкой самой сессии, поэтому медленный запрос в БД не тормозит другие чаты.
 type GameManager struct {
 	sessions map[int64]*GameSession
-	mu       sync.Mutex
+	mu       sync.RWMutex
+
+	Settings Settings
+	Timers   *Timers
 
-	UserRepo    *repositories.UserRepository
-	SessionRepo repositories.SessionRepositoryInterface
-	TaskRepo    *repositories.TaskRepository
+	UserRepo     repositories.UserRepositoryInterface
+	SessionRepo  repositories.SessionRepositoryInterface
+	SnapshotRepo repositories.SnapshotRepositoryInterface
+
+	Events *events.Bus // Статистика, логи и интеграции подписываются на события игры
+
+	Metrics *metrics.Metrics // nil - без метрик
 }
 
-// NewGameManager создаёт и возвращает новый экземпляр GameManager
+// NewGameManager создаёт и возвращает новый экземпляр GameManager.
+// Активные игры, сохранённые до рестарта, восстанавливаются из БД.
 func NewGameManager(
-	userRepo *repositories.UserRepository,
-	sessionRepo *repositories.SessionRepository,
-	taskRepo *repositories.TaskRepository) *GameManager {
-	return &GameManager{
+	userRepo repositories.UserRepositoryInterface,
+	sessionRepo repositories.SessionRepositoryInterface,
+	snapshotRepo repositories.SnapshotRepositoryInterface,
+	bus *events.Bus,
+	settings Settings) *GameManager {
+	gm := &GameManager{
 		sessions: make(map[int64]*GameSession),
-		mu:       sync.Mutex{},
+		mu:       sync.RWMutex{},
+
+		Settings: settings,
+		Timers:   NewTimers(),
+
+		UserRepo:     userRepo,
+		SessionRepo:  sessionRepo,
+		SnapshotRepo: snapshotRepo,
+
+		Events: bus,
+	}
+
+	gm.restoreSessions()
+
+	return gm
+}
+
+// restoreSessions - поднимает из БД снимки игр, которые шли до рестарта
+func (gm *GameManager) restoreSessions() {
+	snapshots, err := gm.SnapshotRepo.GetAll()
+	if err != nil {
+		logger.Error("Не удалось загрузить сохранённые игры", "err", err)
+		gm.Metrics.DBError("load_snapshots")
+		return
+	}
+
+	for _, snapshot := range snapshots {
+		session, err := RestoreSession(snapshot)
+		if err != nil {
+			logger.Warn("Игра не восстановлена", "chat_id", snapshot.ChatID, "err", err)
+			continue
+		}
+		gm.sessions[session.ChatID] = session
+	}
+
+	logger.Info("Восстановлены игры после рестарта", "count", len(gm.sessions))
+}
+
+// persist - сохраняет текущее состояние сессии в БД. Вызывается после каждого изменения
+// под блокировкой сессии. Завершённые сессии не сохраняются, чтобы не воскресить их снимок.
+func (gm *GameManager) persist(session *GameSession) {
+	if session.closed {
+		return
+	}
+
+	snapshot, err := session.Snapshot()
+	if err != nil {
+		logger.Error("Не удалось сериализовать сессию", "chat_id", session.ChatID, "err", err)
+		return
+	}
+
+	if err := gm.SnapshotRepo.Save(snapshot); err != nil {
+		logger.Error("Не удалось сохранить состояние игры", "chat_id", session.ChatID, "err", err)
+		gm.Metrics.DBError("save_snapshot")
+	}
+}
+
+// ActiveSessions - список всех текущих игр
+func (gm *GameManager) ActiveSessions() []*GameSession {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+
+	sessions := make([]*GameSession, 0, len(gm.sessions))
+	for _, session := range gm.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+// Shutdown - останавливает таймеры и сохраняет все активные игры перед выключением бота.
+// Сессии закрываются, чтобы запоздавшие события не меняли их после сохранения.
+// Возвращает чаты, в которых шла игра.
+func (gm *GameManager) Shutdown() []int64 {
+	gm.Timers.StopAll()
+
+	var chats []int64
+	for _, session := range gm.ActiveSessions() {
+		session.mu.Lock()
+		if !session.closed {
+			gm.persist(session)
+			session.closed = true
+			chats = append(chats, session.ChatID)
+		}
+		session.mu.Unlock()
+	}
 
-		UserRepo:    userRepo,
-		SessionRepo: sessionRepo,
-		TaskRepo:    taskRepo,
+	sort.Slice(chats, func(i, j int) bool { return chats[i] < chats[j] })
+
+	logger.Info("Игры сохранены перед остановкой", "count", len(chats))
+	return chats
+}
+
+// SessionsByState - сколько активных игр в каждом состоянии FSM
+func (gm *GameManager) SessionsByState() map[string]int {
+	res := make(map[string]int)
+	for _, session := range gm.ActiveSessions() {
+		session.mu.Lock()
+		if !session.closed {
+			res[string(session.FSM.Current())]++
+		}
+		session.mu.Unlock()
 	}
+	return res
 }
 
 // GetSession возвращает GameSession по chatID и bool
 func (gm *GameManager) GetSession(chatID int64) (*GameSession, bool) {
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
 	session, ok := gm.sessions[chatID]
 	return session, ok
 }
 
 // StartNewGameSession - запускает/перезапускает игру. Все очки стираются.
 func (gm *GameManager) StartNewGameSession(chatID int64) *GameSession {
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
 
-	log.Printf("[GAME] Игра запущена в чате %d", chatID)
+	logger.Info("Игра запущена", "chat_id", chatID)
 
 	session := &GameSession{
 		ChatID: chatID,
@@ -65,14 +201,30 @@ func (gm *GameManager) StartNewGameSession(chatID int64) *GameSession {
 		mu: sync.Mutex{},
 	}
 
+	// Новая сессия блокируется до публикации, чтобы события чата ждали её инициализации
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	gm.mu.Lock()
+	prev := gm.sessions[chatID]
 	gm.sessions[chatID] = session
+	gm.mu.Unlock()
+
+	if prev != nil {
+		prev.close()
+	}
+	gm.Timers.Stop(chatID)
 
 	// Запись статистики в БД
 	_, err := gm.SessionRepo.Create(&models.Session{ChatID: chatID, IsActive: true})
 	if err != nil {
-		log.Printf("[DB ERROR] сессия %d не сохранена в базу данных %v", chatID, err)
+		logger.Error("Сессия не сохранена в базу данных", "chat_id", chatID, "err", err)
+		gm.Metrics.DBError("create_session")
 	}
 
+	gm.persist(session)
+	gm.Events.Publish(events.GameStarted{ChatID: chatID})
+
 	return session
 }
 
@@ -82,50 +234,50 @@ func (gm *GameManager) CheckFirstGame(chatID int64) bool {
 	return errors.Is(err, gorm.ErrRecordNotFound)
 }
 
-// Сбор статистики task
-func (gm *GameManager) saveTaskStats(session *GameSession) {
-	prevTask := session.CarrentTask
-	if prevTask == "" {
-		return
-	}
-	if len(session.UsersPhoto) > 0 {
-		err := gm.TaskRepo.AddUseCount(prevTask)
-		if err != nil {
-			log.Printf("[DB ERROR] Ошибка добавления use_count_task в базу данных %v", err)
-		}
-	} else {
-		err := gm.TaskRepo.AddSkipCount(prevTask)
-		if err != nil {
-			log.Printf("[DB ERROR] Ошибка добавления skip_count_task в базу данных %v", err)
-		}
-	}
-}
+// StartNewRound - запускает новый раунд в текущей сессии. Сессия хранит ID
+// задания: текст на языке чата показывает обработчик. Блиц-задание включает
+// блиц-механику, если для неё задано время.
+func (gm *GameManager) StartNewRound(session *GameSession, task tasks.Task) error {
+	session.mu.Lock()
+	defer session.mu.Unlock()
 
-// StartNewRound - запускает новый раунд в текущей сессии
-func (gm *GameManager) StartNewRound(session *GameSession, task string) error {
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
+	if session.closed {
+		return ErrSessionClosed
+	}
 
-	gm.saveTaskStats(session)
+	gm.Timers.StopOwned(session.ChatID, session)
 
-	log.Printf("[GAME] Новый раунд запущен в чате %d", session.ChatID)
+	logger.Info("Новый раунд запущен", "chat_id", session.ChatID)
 
 	if !SafeTrigger(session.FSM, EventStartRound, "StartNewRound") {
 		return fmt.Errorf("oшибка перехода FSM")
 	}
 
-	_, err := gm.TaskRepo.GetTaskByText(task)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		t := models.NewTask(task)
-		_, err = gm.TaskRepo.Create(t)
-		if err != nil {
-			log.Printf("[DB ERROR] Не удалось добавить task %s: %v", task, err)
-		}
+	started := events.RoundStarted{
+		ChatID:     session.ChatID,
+		Task:       task.ID,
+		PrevTask:   session.CarrentTask,
+		PrevPhotos: len(session.UsersPhoto),
 	}
 
-	session.CarrentTask = task
-	session.UsedTasks[task] = true
+	session.RoundID = time.Now().UnixNano()
+	session.CarrentTask = task.ID
+	session.UsedTasks[task.ID] = true
 	session.UsersPhoto = make(map[int64]string)
+	session.Blitz = task.Blitz && gm.Settings.BlitzDuration > 0
+	session.SubmitOrder = nil
+	session.SpeedBonus = nil
+	session.Deadline = deadlineAfter(gm.submitDuration(session.Blitz))
+
+	session.RoundPlayers = make(map[int64]bool)
+	for userID := range session.UserNames {
+		session.RoundPlayers[userID] = true
+	}
+
+	gm.persist(session)
+
+	started.RoundID = session.RoundID
+	gm.Events.Publish(started)
 
 	return nil
 }
@@ -143,81 +295,196 @@ func (gm *GameManager) addSessionUserIfNotExist(session *GameSession, user *tele
 		u = models.NewUser(userID, user.Username, user.FirstName)
 		_, err = gm.UserRepo.Create(u)
 		if err != nil {
-			log.Printf("[DB ERROR] Не удалось создать пользователя %d: %v", userID, err)
+			logger.Error("Не удалось создать пользователя", "chat_id", chatID, "user_id", userID, "err", err)
+			gm.Metrics.DBError("create_user")
 		}
 	}
 
 	s, err := gm.SessionRepo.GetSessionByID(chatID)
 	if err != nil {
-		log.Printf("[DB ERROR] Не удалось найти сессию в БД. Чат %d: %v", chatID, err)
+		logger.Error("Не удалось найти сессию в БД", "chat_id", chatID, "err", err)
+		gm.Metrics.DBError("get_session")
 		return
 	}
 
 	err = gm.SessionRepo.AddUserToSession(s, u)
 	if err != nil {
-		log.Printf("[DB ERROR] Не удалось привязать пользователя %d к сессии %d: %v", userID, chatID, err)
+		logger.Error("Не удалось привязать пользователя к сессии", "chat_id", chatID, "user_id", userID, "err", err)
+		gm.Metrics.DBError("add_session_user")
 	}
+}
 
-	err = gm.UserRepo.AddUserStatistic(user.ID, repositories.StatGame)
-	if err != nil {
-		log.Printf("[DB ERROR] Не удалось добавить игру участнику %d в сессии %d: %v", userID, chatID, err)
+// TakePhoto - принимает фото участника. Возвращает раунд, в который фото попало, и true,
+// если приём фото ограничен по времени и все участники раунда уже прислали фото -
+// можно открывать голосование этого раунда.
+func (gm *GameManager) TakePhoto(chatID int64, user *telebot.User, photoID string) (int64, bool, error) {
+
+	session, exist := gm.GetSession(chatID)
+	if !exist {
+		return 0, false, fmt.Errorf("фото не принимаются в чате %d", chatID)
 	}
 
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if session.closed || session.FSM.Current() != RoundStartState {
+		return 0, false, fmt.Errorf("фото не принимаются в чате %d", chatID)
+	}
+	if _, sent := session.UsersPhoto[user.ID]; sent {
+		return 0, false, fmt.Errorf("участник %d уже прислал фото в чате %d", user.ID, chatID)
+	}
+
+	_, known := session.UserNames[user.ID]
+
+	gm.addSessionUserIfNotExist(session, user)
+
+	session.TakePhoto(user, photoID)
+
+	gm.persist(session)
+	gm.Events.Publish(events.PhotoSubmitted{
+		ChatID:    chatID,
+		RoundID:   session.RoundID,
+		UserID:    user.ID,
+		NewPlayer: !known,
+	})
+
+	return session.RoundID, gm.submitDuration(session.Blitz) > 0 && session.AllPlayersSubmitted(), nil
 }
 
-func (gm *GameManager) TakePhoto(chatID int64, user *telebot.User, photoID string) {
+// SubmitDuration - сколько времени на фото в текущем раунде сессии, 0 - без ограничения
+func (gm *GameManager) SubmitDuration(session *GameSession) time.Duration {
+	return gm.submitDuration(session.IsBlitz())
+}
 
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
+func (gm *GameManager) submitDuration(blitz bool) time.Duration {
+	if blitz {
+		return gm.Settings.BlitzDuration
+	}
+	return gm.Settings.SubmitDuration
+}
 
-	session := gm.sessions[chatID]
+// StartSubmitTimer - ограничивает время приёма фото, если это включено в настройках.
+// За минуту до конца вызывается onWarn (в блице - нет), по истечении времени - onExpire.
+// Таймер идёт до сохранённого в сессии срока, поэтому после рестарта его можно
+// запустить снова: прошедший срок сработает сразу.
+func (gm *GameManager) StartSubmitTimer(session *GameSession, onWarn func(), onExpire func()) bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	blitz := session.Blitz
+	duration := gm.submitDuration(blitz)
+	if session.closed || duration <= 0 {
+		return false
+	}
 
-	gm.addSessionUserIfNotExist(session, user)
+	left := gm.armDeadline(session, duration)
+	logger.Info("Таймер приёма фото запущен", "chat_id", session.ChatID, "left", left, "blitz", blitz)
 
-	err := gm.SessionRepo.AddPhotosCount(chatID)
-	if err != nil {
-		log.Printf("[DB ERROR] Не удалось увеличить PhotosCount %d: %v", chatID, err)
+	hooks := TimerHooks{OnExpire: onExpire}
+	if !blitz {
+		hooks.Warn = SubmitWarning
+		hooks.OnWarn = onWarn
 	}
+	gm.Timers.Start(session.ChatID, session, left, hooks)
+	return true
+}
 
-	err = gm.UserRepo.AddUserStatistic(user.ID, repositories.StatPhoto)
-	if err != nil {
-		log.Printf("[DB ERROR] Не удалось добавить фото участнику %d в сессии %d: %v", user.ID, chatID, err)
+// armDeadline - срок таймера текущей фазы: сохранённый или через duration от сейчас.
+// Возвращает, сколько осталось. Вызывается под блокировкой сессии.
+func (gm *GameManager) armDeadline(session *GameSession, duration time.Duration) time.Duration {
+	if session.Deadline.IsZero() {
+		session.Deadline = time.Now().Add(duration)
+		gm.persist(session)
 	}
+	return time.Until(session.Deadline)
+}
 
-	session.TakePhoto(user, photoID)
+// deadlineAfter - срок через duration, нулевой - если таймера нет
+func deadlineAfter(duration time.Duration) time.Time {
+	if duration <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(duration)
 }
 
-func (gm *GameManager) StartVoting(session *GameSession) error {
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
+// StartVoting - открывает голосование в раунде roundID. Если раунд уже сменился
+// (например, админ поменял задание, пока срабатывал таймер), возвращает ErrStaleRound.
+func (gm *GameManager) StartVoting(session *GameSession, roundID int64) error {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if session.closed {
+		return ErrSessionClosed
+	}
+	if session.RoundID != roundID {
+		return ErrStaleRound
+	}
 
-	log.Printf("[GAME] Голосование запущено в чате %d", session.ChatID)
+	logger.Info("Голосование запущено", "chat_id", session.ChatID)
 
 	if !SafeTrigger(session.FSM, EventStartVote, "StartVoting") {
 		return fmt.Errorf("oшибка перехода FSM")
 	}
 
+	gm.Timers.StopOwned(session.ChatID, session)
+
 	session.Votes = make(map[int64]int64)
+	session.Deadline = deadlineAfter(gm.Settings.VoteDuration)
+
+	// Нумерация фото для голосования. Порядок обхода мапы случайный - фото перемешаны.
+	session.IndexPhotoToUser = make(map[int]int64)
+	index := 1
+	for userID := range session.UsersPhoto {
+		session.IndexPhotoToUser[index] = userID
+		index++
+	}
+
+	if session.Blitz {
+		session.SpeedBonus = session.fastest(BlitzBonusPlaces)
+		for _, userID := range session.SpeedBonus {
+			session.Score[userID]++
+		}
+	}
+
+	gm.persist(session)
+	gm.Events.Publish(events.VotingStarted{ChatID: session.ChatID, RoundID: session.RoundID, Photos: len(session.IndexPhotoToUser)})
+
 	return nil
 }
 
-// VoteResult спец тип для ответов или CallBack или Messages
+// VoteResult спец тип для ответов или CallBack или Messages.
+// Текст собирает обработчик на языке чата: Message с аргументами Args.
 type VoteResult struct {
-	Message    string
+	Message    messages.Key
+	Args       []interface{}
 	IsCallback bool
+	IsAlert    bool // Показать callback как всплывающее окно
 	IsError    bool
 }
 
-func (gm *GameManager) RegisterVote(chatID int64, voter *telebot.User, photoNum int) (*VoteResult, error) {
+// RegisterVote - голос за фото photoNum в раунде roundID. Голоса за кнопки
+// из прошлых раундов или закончившихся игр отклоняются.
+func (gm *GameManager) RegisterVote(chatID, roundID int64, voter *telebot.User, photoNum int) (*VoteResult, error) {
 
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
+	session, exist := gm.GetSession(chatID)
+	if exist {
+		session.mu.Lock()
+		defer session.mu.Unlock()
+	}
 
-	session, exist := gm.sessions[chatID]
-	if !exist || session.FSM.Current() != VoteState {
+	if !exist || session.closed || session.FSM.Current() != VoteState {
 		return &VoteResult{
 			Message:    messages.VotedEarler,
 			IsCallback: true,
+			IsAlert:    true,
+		}, nil
+	}
+
+	if session.RoundID != roundID {
+		return &VoteResult{
+			Message:    messages.VoteStale,
+			IsCallback: true,
+			IsAlert:    true,
 		}, nil
 	}
 
@@ -230,7 +497,7 @@ func (gm *GameManager) RegisterVote(chatID int64, voter *telebot.User, photoNum
 
 	targetUserID, ok := session.IndexPhotoToUser[photoNum]
 	if !ok {
-		log.Printf("[ERROR] Неизвестный номер фото %d в чате %d", photoNum, chatID)
+		logger.Error("Неизвестный номер фото", "chat_id", chatID, "photo", photoNum)
 		return &VoteResult{
 			Message:    messages.ErrorMessagesForUser,
 			IsCallback: true,
@@ -249,28 +516,95 @@ func (gm *GameManager) RegisterVote(chatID int64, voter *telebot.User, photoNum
 	session.Votes[voter.ID] = targetUserID
 	session.Score[targetUserID]++
 
-	// Запись статистики
-	err := gm.UserRepo.AddUserStatistic(voter.ID, repositories.StatVote)
-	if err != nil {
-		log.Printf("[DB ERROR] Не удалось добавить голос для %d: %v", voter.ID, err)
-	}
+	gm.persist(session)
+	gm.Events.Publish(events.VoteCast{
+		ChatID:   chatID,
+		RoundID:  roundID,
+		VoterID:  voter.ID,
+		TargetID: targetUserID,
+	})
 
 	return &VoteResult{
-		Message:    fmt.Sprintf("%s проголосовал(а)", session.GetUserName(voter.ID)),
+		Message:    messages.VoteCast,
+		Args:       []interface{}{session.userName(voter.ID)},
 		IsCallback: false,
 	}, nil
 }
 
-func (gm *GameManager) FinishVoting(session *GameSession) {
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
+// StartVoteTimer - запускает таймер голосования, если он включён в настройках.
+// По истечении времени вызывается onExpire, ручное завершение, новый раунд или конец игры
+// таймер отменяют - тогда вызывается onCancel. Как и таймер приёма фото, идёт до
+// сохранённого в сессии срока.
+func (gm *GameManager) StartVoteTimer(session *GameSession, onTick func(left time.Duration), onExpire, onCancel func()) bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if session.closed || gm.Settings.VoteDuration <= 0 {
+		return false
+	}
+
+	left := gm.armDeadline(session, gm.Settings.VoteDuration)
+	logger.Info("Таймер голосования запущен", "chat_id", session.ChatID, "left", left)
 
-	SafeTrigger(session.FSM, EventFinishVote, "FinishVoting")
+	gm.Timers.Start(session.ChatID, session, left, TimerHooks{
+		Tick:     VoteCountdownTick,
+		OnTick:   onTick,
+		OnExpire: onExpire,
+		OnCancel: onCancel,
+	})
+	return true
+}
+
+// FinishVoting - завершает голосование. Возвращает false, если голосование уже было
+// завершено или игра закончилась.
+func (gm *GameManager) FinishVoting(session *GameSession) bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if session.closed {
+		return false
+	}
+
+	if !SafeTrigger(session.FSM, EventFinishVote, "FinishVoting") {
+		return false
+	}
+
+	gm.Timers.StopOwned(session.ChatID, session)
+	session.Deadline = time.Time{}
+
+	gm.persist(session)
+
+	votes := make(map[int64]int)
+	for _, target := range session.Votes {
+		votes[target]++
+	}
+	gm.Events.Publish(events.VotingFinished{ChatID: session.ChatID, RoundID: session.RoundID, Votes: votes})
+
+	return true
 }
 
 func (gm *GameManager) EndGame(chatID int64) {
 	gm.mu.Lock()
-	defer gm.mu.Unlock()
-
+	session, exist := gm.sessions[chatID]
 	delete(gm.sessions, chatID)
+	gm.mu.Unlock()
+
+	if exist {
+		session.close()
+	}
+	gm.Timers.Stop(chatID)
+
+	if err := gm.SnapshotRepo.Delete(chatID); err != nil {
+		logger.Error("Не удалось удалить сохранённую игру", "chat_id", chatID, "err", err)
+		gm.Metrics.DBError("delete_snapshot")
+	}
+
+	if err := gm.SessionRepo.ChangeIsActive(chatID); err != nil {
+		logger.Error("Не удалось закрыть сессию", "chat_id", chatID, "err", err)
+		gm.Metrics.DBError("close_session")
+	}
+
+	if exist {
+		gm.Events.Publish(events.GameEnded{ChatID: chatID})
+	}
 }
@@ -0,0 +1,192 @@
+package game
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/tasks"
+
+	"gopkg.in/telebot.v3"
+)
+
+// Тесты для go test -race: много чатов играют параллельно,
+// а обработчики одновременно читают состояние сессий.
+
+const raceChats = 50
+
+func TestRaceParallelChats(t *testing.T) {
+	gm := newTestGameManager()
+
+	var wg sync.WaitGroup
+	for i := 0; i < raceChats; i++ {
+		wg.Add(1)
+		go func(chatID int64) {
+			defer wg.Done()
+
+			s := gm.StartNewGameSession(chatID)
+
+			if err := gm.StartNewRound(s, testTask("task")); err != nil {
+				t.Errorf("chat %d: StartNewRound failed: %v", chatID, err)
+				return
+			}
+
+			var photos sync.WaitGroup
+			for userID := int64(1); userID <= 3; userID++ {
+				photos.Add(1)
+				go func(userID int64) {
+					defer photos.Done()
+					if _, _, err := gm.TakePhoto(chatID, &telebot.User{ID: userID, FirstName: "player"}, "photo"); err != nil {
+						t.Errorf("chat %d: TakePhoto failed: %v", chatID, err)
+					}
+				}(userID)
+			}
+			photos.Wait()
+			roundID := s.CurrentRoundID()
+
+			if err := gm.StartVoting(s, s.CurrentRoundID()); err != nil {
+				t.Errorf("chat %d: StartVoting failed: %v", chatID, err)
+				return
+			}
+
+			// Читатели из "обработчиков" параллельно с событиями
+			var readers sync.WaitGroup
+			for r := 0; r < 5; r++ {
+				readers.Add(1)
+				go func() {
+					defer readers.Done()
+					_ = s.State()
+					_ = s.VotePhotos()
+					_ = s.TotalScore()
+					_ = s.RoundScore()
+					_ = s.PhotosCount()
+					_ = s.UsedTaskSet()
+					_, _ = gm.GetSession(chatID)
+				}()
+			}
+
+			// Голоса текущего раунда, устаревшие кнопки и нажатия после закрытия голосования
+			for v := int64(0); v < 10; v++ {
+				readers.Add(2)
+				go func(voterID int64) {
+					defer readers.Done()
+					_, _ = gm.RegisterVote(chatID, roundID, &telebot.User{ID: voterID}, 1)
+				}(100 + v)
+				go func(voterID int64) {
+					defer readers.Done()
+					_, _ = gm.RegisterVote(chatID, roundID+1, &telebot.User{ID: voterID}, 2)
+				}(100 + v)
+			}
+
+			gm.FinishVoting(s)
+			readers.Wait()
+
+			if s.State() != WaitingState {
+				t.Errorf("chat %d: expected WaitingState, got %s", chatID, s.State())
+			}
+		}(int64(10_000 + i))
+	}
+
+	wg.Wait()
+
+	for i := 0; i < raceChats; i++ {
+		if _, ok := gm.GetSession(int64(10_000 + i)); !ok {
+			t.Errorf("chat %d: session is missing", 10_000+i)
+		}
+	}
+}
+
+func TestRaceRestartAndEndGame(t *testing.T) {
+	gm := newTestGameManager()
+
+	var wg sync.WaitGroup
+	for i := 0; i < raceChats; i++ {
+		wg.Add(3)
+		chatID := int64(20_000 + i%5) // несколько горутин на один и тот же чат
+
+		go func() {
+			defer wg.Done()
+			gm.StartNewGameSession(chatID)
+		}()
+		go func() {
+			defer wg.Done()
+			gm.EndGame(chatID)
+		}()
+		go func() {
+			defer wg.Done()
+			if s, ok := gm.GetSession(chatID); ok {
+				_ = gm.StartVoting(s, s.CurrentRoundID())
+				_ = s.TotalScore()
+				gm.FinishVoting(s)
+			}
+			_ = gm.ActiveSessions()
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestClosedSessionIsNotPersisted(t *testing.T) {
+	gm := newTestGameManager()
+	old := gm.sessions[chatID]
+
+	gm.StartNewGameSession(chatID)
+
+	old.mu.Lock()
+	old.FSM.current = RoundStartState
+	old.mu.Unlock()
+
+	if err := gm.StartVoting(old, old.CurrentRoundID()); !errors.Is(err, ErrSessionClosed) {
+		t.Fatalf("Expected ErrSessionClosed for a replaced session, got %v", err)
+	}
+	if old.State() != RoundStartState {
+		t.Errorf("Replaced session must not move to voting, got %s", old.State())
+	}
+
+	snapshots, _ := gm.SnapshotRepo.GetAll()
+	for _, snap := range snapshots {
+		if snap.ChatID == chatID && snap.State != string(WaitingState) {
+			t.Errorf("Replaced session overwrote the snapshot of the new game: %s", snap.State)
+		}
+	}
+}
+
+func TestClosedSessionRejectsLateEvents(t *testing.T) {
+	gm := newTestGameManager()
+	gm.Settings.VoteDuration = time.Hour
+	old := gm.sessions[chatID]
+	old.FSM.current = VoteState
+
+	fresh := gm.StartNewGameSession(chatID)
+	if err := gm.StartNewRound(fresh, tasks.Task{ID: "t1"}); err != nil {
+		t.Fatalf("StartNewRound failed: %v", err)
+	}
+	gm.Settings.SubmitDuration = time.Hour
+	if !gm.StartSubmitTimer(fresh, nil, func() {}) {
+		t.Fatal("Expected submit timer of the new game")
+	}
+
+	// Запоздавшие события старой игры ничего не меняют и не трогают таймер новой
+	if gm.FinishVoting(old) {
+		t.Error("FinishVoting must reject a replaced session")
+	}
+	if err := gm.StartNewRound(old, tasks.Task{ID: "t2"}); !errors.Is(err, ErrSessionClosed) {
+		t.Errorf("Expected ErrSessionClosed, got %v", err)
+	}
+	if gm.StartVoteTimer(old, nil, func() {}, nil) {
+		t.Error("Replaced session must not start timers")
+	}
+	if !gm.Timers.Active(chatID) {
+		t.Error("Late events of the old game cancelled the timer of the new one")
+	}
+
+	// После /endgame голосование не открывается
+	gm.EndGame(chatID)
+	if err := gm.StartVoting(fresh, fresh.CurrentRoundID()); !errors.Is(err, ErrSessionClosed) {
+		t.Errorf("Expected ErrSessionClosed after EndGame, got %v", err)
+	}
+	if fresh.State() != RoundStartState {
+		t.Errorf("Ended game must stay in its state, got %s", fresh.State())
+	}
+}
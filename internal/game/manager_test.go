@@ -1,10 +1,19 @@
 package game
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
 	"sync"
 	"testing"
+	"time"
 
-	"github.com/kiselevos/memento_game_bot/internal/repositories/mock"
+	messages "github.com/kiselevos/memento_game_bot/assets"
+	"github.com/kiselevos/memento_game_bot/internal/events"
+	"github.com/kiselevos/memento_game_bot/internal/repositories"
+	"github.com/kiselevos/memento_game_bot/internal/repositories/memory"
+
+	"gopkg.in/telebot.v3"
 )
 
 const (
@@ -14,9 +23,13 @@ const (
 
 func newTestGameManager() *GameManager {
 	return &GameManager{
-		sessions:    map[int64]*GameSession{chatID: newTestGameSession()},
-		SessionRepo: &mock.FakeSessionRepo{},
-		mu:          sync.Mutex{},
+		sessions:     map[int64]*GameSession{chatID: newTestGameSession()},
+		UserRepo:     memory.NewUserRepo(),
+		SessionRepo:  memory.NewSessionRepo(),
+		SnapshotRepo: memory.NewSnapshotRepo(),
+		Timers:       NewTimers(),
+		Events:       events.NewBus(),
+		mu:           sync.RWMutex{},
 	}
 }
 
@@ -91,7 +104,7 @@ func TestStartVoting(t *testing.T) {
 	s := gm.sessions[chatID]
 	s.FSM.current = RoundStartState
 
-	err := gm.StartVoting(s)
+	err := gm.StartVoting(s, s.CurrentRoundID())
 	if err != nil {
 		t.Fatalf("Expected StartVoting to succeed, got error: %v", err)
 	}
@@ -109,10 +122,422 @@ func TestFinishVoting(t *testing.T) {
 	gm := newTestGameManager()
 	s := gm.sessions[chatID]
 
-	_ = gm.StartVoting(s)
+	_ = gm.StartVoting(s, s.CurrentRoundID())
 	gm.FinishVoting(s)
 
 	if s.FSM.Current() != WaitingState {
 		t.Errorf("Expected FSM to be in WaitingState, got %s", s.FSM.Current())
 	}
 }
+
+func TestRestoreSessionsOnStart(t *testing.T) {
+	gm := newTestGameManager()
+	s := gm.sessions[chatID]
+	s.FSM.current = RoundStartState
+
+	_ = gm.StartVoting(s, s.CurrentRoundID())
+
+	repo := gm.SnapshotRepo
+	if !hasSnapshot(repo, chatID) {
+		t.Fatal("Expected snapshot to be saved after StartVoting")
+	}
+
+	restarted := NewGameManager(memory.NewUserRepo(), memory.NewSessionRepo(), repo, events.NewBus(), Settings{})
+
+	restored, exist := restarted.GetSession(chatID)
+	if !exist {
+		t.Fatal("Expected session to be restored after restart")
+	}
+	if restored.FSM.Current() != VoteState {
+		t.Errorf("Expected restored FSM in VoteState, got %s", restored.FSM.Current())
+	}
+	if restored.Score[userID_2] != 5 {
+		t.Errorf("Expected restored score 5, got %d", restored.Score[userID_2])
+	}
+
+	restarted.EndGame(chatID)
+	if hasSnapshot(repo, chatID) {
+		t.Error("Expected snapshot to be deleted after EndGame")
+	}
+}
+
+func TestRestoredSessionResumesTimer(t *testing.T) {
+	gm := newTestGameManager()
+	gm.Settings.VoteDuration = time.Hour
+
+	s := gm.sessions[chatID]
+	s.FSM.current = RoundStartState
+	_ = gm.StartVoting(s, s.CurrentRoundID())
+	gm.Shutdown()
+
+	t.Run("Deadline ahead", func(t *testing.T) {
+		restarted := NewGameManager(memory.NewUserRepo(), memory.NewSessionRepo(), gm.SnapshotRepo, events.NewBus(), gm.Settings)
+		restored, _ := restarted.GetSession(chatID)
+		defer restarted.Shutdown()
+
+		if !restored.Deadline.Equal(s.Deadline) {
+			t.Fatalf("Expected deadline %v to be restored, got %v", s.Deadline, restored.Deadline)
+		}
+		if !restarted.StartVoteTimer(restored, nil, func() {}, nil) || !restarted.Timers.Active(chatID) {
+			t.Fatal("Expected vote timer to be re-armed")
+		}
+		if left := restored.TimeLeft(); left <= 0 || left > time.Hour {
+			t.Errorf("Expected timer to continue from the saved deadline, %v left", left)
+		}
+	})
+
+	t.Run("Deadline passed", func(t *testing.T) {
+		restarted := NewGameManager(memory.NewUserRepo(), memory.NewSessionRepo(), gm.SnapshotRepo, events.NewBus(), gm.Settings)
+		restored, _ := restarted.GetSession(chatID)
+		defer restarted.Shutdown()
+		restored.Deadline = time.Now().Add(-time.Minute)
+
+		fired := make(chan struct{}, 1)
+		restarted.StartVoteTimer(restored, nil, func() { fired <- struct{}{} }, nil)
+
+		select {
+		case <-fired:
+		case <-time.After(time.Second):
+			t.Fatal("Expected expired vote timer to fire right away")
+		}
+	})
+}
+
+func hasSnapshot(repo repositories.SnapshotRepositoryInterface, chatID int64) bool {
+	snapshots, _ := repo.GetAll()
+	for _, s := range snapshots {
+		if s.ChatID == chatID {
+			return true
+		}
+	}
+	return false
+}
+
+func TestShutdownPersistsAndClosesSessions(t *testing.T) {
+	gm := newTestGameManager()
+	gm.Settings.VoteDuration = 30 * time.Millisecond
+
+	s := gm.sessions[chatID]
+	s.FSM.current = RoundStartState
+	_ = gm.StartVoting(s, s.CurrentRoundID())
+
+	fired := make(chan struct{}, 1)
+	gm.StartVoteTimer(s, nil, func() { fired <- struct{}{} }, nil)
+
+	// Снимок мог устареть - Shutdown должен сохранить текущее состояние
+	s.Score[userID_2] = 42
+	_ = gm.SnapshotRepo.Delete(chatID)
+
+	chats := gm.Shutdown()
+	if !reflect.DeepEqual(chats, []int64{chatID}) {
+		t.Fatalf("Expected active chats [%d], got %v", chatID, chats)
+	}
+
+	restarted := NewGameManager(memory.NewUserRepo(), memory.NewSessionRepo(), gm.SnapshotRepo, events.NewBus(), Settings{})
+	restored, exist := restarted.GetSession(chatID)
+	if !exist || restored.Score[userID_2] != 42 {
+		t.Fatalf("Expected session with score 42 restored after shutdown, got %+v", restored)
+	}
+
+	// Закрытая сессия больше не принимает голоса и не перезаписывает снимок
+	res, _ := gm.RegisterVote(chatID, s.RoundID, &telebot.User{ID: userID_1}, 1)
+	if res.Message != messages.VotedEarler {
+		t.Errorf("Expected vote after shutdown to be rejected, got %q", res.Message)
+	}
+
+	select {
+	case <-fired:
+		t.Error("Timers must be stopped on shutdown")
+	case <-time.After(80 * time.Millisecond):
+	}
+
+	if again := gm.Shutdown(); len(again) != 0 {
+		t.Errorf("Second shutdown must not report closed sessions, got %v", again)
+	}
+}
+
+func TestVoteTimerFinishesVoting(t *testing.T) {
+	gm := newTestGameManager()
+	gm.Settings.VoteDuration = 20 * time.Millisecond
+
+	s := gm.sessions[chatID]
+	s.FSM.current = RoundStartState
+	_ = gm.StartVoting(s, s.CurrentRoundID())
+
+	done := make(chan bool, 1)
+	started := gm.StartVoteTimer(s, nil, func() {
+		done <- gm.FinishVoting(s)
+	}, nil)
+	if !started {
+		t.Fatal("Expected vote timer to start")
+	}
+
+	select {
+	case finished := <-done:
+		if !finished {
+			t.Error("Expected timer to finish voting")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Vote timer did not fire")
+	}
+
+	if s.FSM.Current() != WaitingState {
+		t.Errorf("Expected FSM in WaitingState, got %s", s.FSM.Current())
+	}
+}
+
+func TestVoteTimerCancelledByManualFinish(t *testing.T) {
+	gm := newTestGameManager()
+	gm.Settings.VoteDuration = 30 * time.Millisecond
+
+	s := gm.sessions[chatID]
+	s.FSM.current = RoundStartState
+	_ = gm.StartVoting(s, s.CurrentRoundID())
+
+	fired := make(chan struct{}, 1)
+	gm.StartVoteTimer(s, nil, func() { fired <- struct{}{} }, nil)
+
+	if !gm.FinishVoting(s) {
+		t.Fatal("Expected manual FinishVoting to succeed")
+	}
+	if gm.FinishVoting(s) {
+		t.Error("Second FinishVoting must report that voting is already finished")
+	}
+
+	select {
+	case <-fired:
+		t.Error("Timer must be cancelled by manual finish")
+	case <-time.After(80 * time.Millisecond):
+	}
+}
+
+func TestVoteTimerDisabled(t *testing.T) {
+	gm := newTestGameManager()
+
+	if gm.StartVoteTimer(gm.sessions[chatID], nil, func() {}, nil) {
+		t.Error("Timer must not start when VoteDuration is 0")
+	}
+}
+
+func TestStartVotingStopsSubmitTimer(t *testing.T) {
+	gm := newTestGameManager()
+	gm.Settings.SubmitDuration = 30 * time.Millisecond
+
+	s := gm.sessions[chatID]
+	s.FSM.current = RoundStartState
+
+	fired := make(chan struct{}, 1)
+	if !gm.StartSubmitTimer(s, nil, func() { fired <- struct{}{} }) {
+		t.Fatal("Expected submit timer to start")
+	}
+
+	if err := gm.StartVoting(s, s.CurrentRoundID()); err != nil {
+		t.Fatalf("StartVoting failed: %v", err)
+	}
+
+	select {
+	case <-fired:
+		t.Error("Submit timer must be cancelled when voting opens")
+	case <-time.After(80 * time.Millisecond):
+	}
+}
+
+func TestRegisterVoteRejectsStaleRound(t *testing.T) {
+	gm := newTestGameManager()
+	s := gm.sessions[chatID]
+	s.FSM.current = RoundStartState
+	s.RoundID = 42
+	s.UsersPhoto[userID_1] = "photo_1"
+	_ = gm.StartVoting(s, s.CurrentRoundID())
+
+	voter := &telebot.User{ID: userID_2}
+
+	t.Run("Button from previous round", func(t *testing.T) {
+		res, err := gm.RegisterVote(chatID, 41, voter, 1)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !res.IsCallback || !res.IsAlert || res.Message != messages.VoteStale {
+			t.Errorf("Expected stale alert, got %+v", res)
+		}
+		if len(s.Votes) != 0 {
+			t.Error("Stale vote must not be counted")
+		}
+	})
+
+	t.Run("Button from finished game", func(t *testing.T) {
+		res, _ := gm.RegisterVote(NewGameID, 42, voter, 1)
+		if !res.IsCallback || res.Message != messages.VotedEarler {
+			t.Errorf("Expected 'voting not active' callback, got %+v", res)
+		}
+	})
+}
+
+func TestRegisterVotePublishesEvent(t *testing.T) {
+	gm := newTestGameManager()
+
+	var mu sync.Mutex
+	var published []events.Event
+	gm.Events.Subscribe(func(e events.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		published = append(published, e)
+	})
+
+	s := gm.sessions[chatID]
+	s.FSM.current = RoundStartState
+	s.RoundID = 7
+	s.UsersPhoto[userID_1] = "photo_1"
+	_ = gm.StartVoting(s, s.CurrentRoundID())
+
+	res, err := gm.RegisterVote(chatID, 7, &telebot.User{ID: userID_2}, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res.IsCallback {
+		t.Errorf("Expected vote to be accepted, got %+v", res)
+	}
+	if s.Score[userID_1] != 3 {
+		t.Errorf("Expected score 3, got %d", s.Score[userID_1])
+	}
+
+	res, _ = gm.RegisterVote(chatID, 7, &telebot.User{ID: userID_2}, 1)
+	if res.Message != messages.VotedAlready {
+		t.Errorf("Expected repeated vote to be rejected, got %+v", res)
+	}
+
+	gm.FinishVoting(s)
+	gm.EndGame(chatID)
+
+	want := []events.Event{
+		events.VotingStarted{ChatID: chatID, RoundID: 7, Photos: 1},
+		events.VoteCast{ChatID: chatID, RoundID: 7, VoterID: userID_2, TargetID: userID_1},
+		events.VotingFinished{ChatID: chatID, RoundID: 7, Votes: map[int64]int{userID_1: 1}},
+		events.GameEnded{ChatID: chatID},
+	}
+	if !reflect.DeepEqual(published, want) {
+		t.Errorf("Expected events %+v, got %+v", want, published)
+	}
+}
+
+func TestBlitzRoundSpeedBonus(t *testing.T) {
+	gm := NewGameManager(memory.NewUserRepo(), memory.NewSessionRepo(),
+		memory.NewSnapshotRepo(), events.NewBus(), Settings{SubmitDuration: time.Hour, BlitzDuration: time.Minute})
+
+	const game = 4242
+	session := gm.StartNewGameSession(game)
+	blitz := testTask("b1")
+	blitz.Blitz = true
+
+	if err := gm.StartNewRound(session, blitz); err != nil {
+		t.Fatalf("StartNewRound failed: %v", err)
+	}
+	if !session.IsBlitz() || gm.SubmitDuration(session) != time.Minute {
+		t.Fatalf("Expected blitz round with its own timer, got blitz=%v %v", session.IsBlitz(), gm.SubmitDuration(session))
+	}
+
+	// Бонус - первым трём, опоздавший четвёртый остаётся без него
+	for id := int64(1); id <= 4; id++ {
+		if _, _, err := gm.TakePhoto(game, &telebot.User{ID: id, Username: fmt.Sprintf("p%d", id)}, "photo"); err != nil {
+			t.Fatalf("TakePhoto failed: %v", err)
+		}
+	}
+	if err := gm.StartVoting(session, session.CurrentRoundID()); err != nil {
+		t.Fatalf("StartVoting failed: %v", err)
+	}
+	if got := session.TotalScore(); len(got) != 3 || session.Score[4] != 0 {
+		t.Errorf("Expected bonus for the first three, got %+v", got)
+	}
+	if names := session.SpeedBonusNames(); !reflect.DeepEqual(names, []string{"@p1", "@p2", "@p3"}) {
+		t.Errorf("Unexpected bonus names: %v", names)
+	}
+
+	// Обычный раунд бонуса не даёт, а в одиночку очко за скорость не получить
+	gm.FinishVoting(session)
+	if err := gm.StartNewRound(session, testTask("t1")); err != nil {
+		t.Fatalf("StartNewRound failed: %v", err)
+	}
+	if session.IsBlitz() || gm.SubmitDuration(session) != time.Hour {
+		t.Error("Regular task must not start a blitz round")
+	}
+	gm.TakePhoto(game, &telebot.User{ID: 4}, "photo")
+	gm.StartVoting(session, session.CurrentRoundID())
+	if session.Score[4] != 0 || len(session.SpeedBonusNames()) != 0 {
+		t.Errorf("Unexpected bonus in a regular round: %v", session.Score)
+	}
+
+	gm.FinishVoting(session)
+	gm.StartNewRound(session, blitz)
+	gm.TakePhoto(game, &telebot.User{ID: 4}, "photo")
+	gm.StartVoting(session, session.CurrentRoundID())
+	if session.Score[4] != 0 {
+		t.Errorf("Single photo must not get the speed bonus: %v", session.Score)
+	}
+
+	// Без времени на блиц блиц-задание идёт как обычное
+	gm.Settings.BlitzDuration = 0
+	gm.FinishVoting(session)
+	gm.StartNewRound(session, blitz)
+	if session.IsBlitz() {
+		t.Error("Blitz mechanics must be off without BlitzDuration")
+	}
+	gm.EndGame(game)
+}
+
+func TestStartVotingRejectsStaleRound(t *testing.T) {
+	gm := newTestGameManager()
+	s := gm.sessions[chatID]
+
+	if err := gm.StartNewRound(s, testTask("a")); err != nil {
+		t.Fatalf("StartNewRound failed: %v", err)
+	}
+	roundA := s.CurrentRoundID()
+	s.UsersPhoto[userID_1] = "photo_1"
+
+	// Админ поменял задание, а таймер раунда A сработал уже после этого
+	time.Sleep(time.Millisecond)
+	if err := gm.StartNewRound(s, testTask("b")); err != nil {
+		t.Fatalf("StartNewRound failed: %v", err)
+	}
+	if err := gm.StartVoting(s, roundA); !errors.Is(err, ErrStaleRound) {
+		t.Fatalf("Expected ErrStaleRound, got %v", err)
+	}
+	if s.State() != RoundStartState {
+		t.Errorf("Stale timer must not open voting, got %s", s.State())
+	}
+	if err := gm.StartVoting(s, s.CurrentRoundID()); err != nil {
+		t.Errorf("Current round must open voting: %v", err)
+	}
+}
+
+func TestTakePhotoReportsItsRound(t *testing.T) {
+	gm := NewGameManager(memory.NewUserRepo(), memory.NewSessionRepo(),
+		memory.NewSnapshotRepo(), events.NewBus(), Settings{SubmitDuration: time.Hour})
+
+	const game = 4343
+	session := gm.StartNewGameSession(game)
+	gm.StartNewRound(session, testTask("t1"))
+	gm.TakePhoto(game, &telebot.User{ID: 1}, "photo")
+	gm.TakePhoto(game, &telebot.User{ID: 2}, "photo")
+	gm.StartVoting(session, session.CurrentRoundID())
+	gm.FinishVoting(session)
+
+	// Хендлер видел первый раунд, а фото пришло уже после смены задания
+	gm.StartNewRound(session, testTask("t2"))
+	seen := session.CurrentRoundID()
+	if err := gm.StartNewRound(session, testTask("t3")); err != nil {
+		t.Fatalf("StartNewRound failed: %v", err)
+	}
+	gm.TakePhoto(game, &telebot.User{ID: 1}, "photo")
+
+	roundID, allIn, err := gm.TakePhoto(game, &telebot.User{ID: 2}, "photo")
+	if err != nil || !allIn {
+		t.Fatalf("Expected the last photo to complete the round, got %v %v", allIn, err)
+	}
+	if roundID == seen || roundID != session.CurrentRoundID() {
+		t.Fatalf("Expected photo round %d, got %d", session.CurrentRoundID(), roundID)
+	}
+	if err := gm.StartVoting(session, roundID); err != nil {
+		t.Errorf("Voting of the photo's round must open: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package game
 import (
 	"sort"
 	"sync"
+	"time"
 
 	"gopkg.in/telebot.v3"
 )
@@ -13,18 +14,25 @@ type GameSession struct {
 	// Постоянные
 	ChatID    int64            // Номер чата, где идет игра
 	Score     map[int64]int    // Мапа с очками юзеров
-	UsedTasks map[string]bool  // Для отслеживаания используемых вопросов
+	UsedTasks map[string]bool  // ID использованных заданий
 	UserNames map[int64]string //Список участников раунда
 
 	// Обнуляющиеся при новом раунде
 
 	FSM              *FSM             // Машина состояний
+	RoundID          int64            // Идентификатор текущего раунда, зашит в кнопки голосования
 	Votes            map[int64]int64  // Кто кому отдал свой голос в раунде
 	UsersPhoto       map[int64]string // Хранение фотографий, отпрвленных юзером
-	CarrentTask      string           // Текущее задание
+	CarrentTask      string           // ID текущего задания
 	IndexPhotoToUser map[int]int64    // Мапа для голосования(Индекс очердности фото к игроку)
-
-	mu sync.Mutex
+	RoundPlayers     map[int64]bool   // Участники игры на момент старта раунда - от них ждём фото
+	Blitz            bool             // Блиц-раунд: короткий таймер, фото принимаются молча
+	SubmitOrder      []int64          // Кто в каком порядке прислал фото
+	SpeedBonus       []int64          // Получили очко за скорость в блиц-раунде
+	Deadline         time.Time        // Срок таймера приёма фото или голосования, нулевой - без таймера
+
+	mu     sync.Mutex // Сериализует события сессии. Мапы выше меняются только под ним
+	closed bool       // Игра завершена или заменена новой - события больше не принимаются
 }
 
 type PlayerScore struct {
@@ -33,19 +41,31 @@ type PlayerScore struct {
 	Value    int
 }
 
-// GetUserName - возвращает имя или ник пользователя
+// GetUserName - возвращает имя или ник пользователя. Для незнакомого игрока - пустая
+// строка: подпись на языке чата подставляют хендлеры.
 func (s *GameSession) GetUserName(userID int64) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.userName(userID)
+}
+
+func (s *GameSession) userName(userID int64) string {
 	if name, ok := s.UserNames[userID]; ok {
 		return name
 	}
-	return "Анонимный Осётр"
+	return ""
 }
 
 func (s *GameSession) TotalScore() []PlayerScore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.scoreFromMap(s.Score)
 }
 
 func (s *GameSession) RoundScore() []PlayerScore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	voteCount := make(map[int64]int)
 	for _, votedFor := range s.Votes {
 		voteCount[votedFor]++
@@ -59,7 +79,7 @@ func (s *GameSession) scoreFromMap(data map[int64]int) []PlayerScore {
 	for userID, val := range data {
 		result = append(result, PlayerScore{
 			UserID:   userID,
-			UserName: s.GetUserName(userID),
+			UserName: s.userName(userID),
 			Value:    val,
 		})
 	}
@@ -71,9 +91,11 @@ func (s *GameSession) scoreFromMap(data map[int64]int) []PlayerScore {
 	return result
 }
 
+// TakePhoto - сохраняет фото участника. Вызывается GameManager под блокировкой сессии.
 func (s *GameSession) TakePhoto(user *telebot.User, photoID string) {
 
 	s.UsersPhoto[user.ID] = photoID
+	s.SubmitOrder = append(s.SubmitOrder, user.ID)
 
 	// TODO: Собрать фидбэк по поводу имен. Как лучше?
 
@@ -85,3 +107,127 @@ func (s *GameSession) TakePhoto(user *telebot.User, photoID string) {
 		}
 	}
 }
+
+// AllPlayersSubmitted - все участники, игравшие до начала раунда, уже прислали фото.
+// В первом раунде участники ещё неизвестны, поэтому всегда false. Вызывается под блокировкой.
+func (s *GameSession) AllPlayersSubmitted() bool {
+	if len(s.RoundPlayers) < 2 {
+		return false
+	}
+	for userID := range s.RoundPlayers {
+		if _, ok := s.UsersPhoto[userID]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// fastest - первые places приславших фото. Последний прислать успел, но бонус
+// не получает: иначе в маленьком чате очко за скорость достаётся всем.
+func (s *GameSession) fastest(places int) []int64 {
+	if limit := len(s.SubmitOrder) - 1; places > limit {
+		places = limit
+	}
+	if places <= 0 {
+		return nil
+	}
+	return append([]int64(nil), s.SubmitOrder[:places]...)
+}
+
+// VotePhoto - фото в голосовании: номер кнопки, автор и file_id
+type VotePhoto struct {
+	Index   int
+	UserID  int64
+	PhotoID string
+}
+
+// State - текущее состояние FSM сессии
+func (s *GameSession) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.FSM.Current()
+}
+
+// CurrentRoundID - идентификатор текущего раунда
+func (s *GameSession) CurrentRoundID() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.RoundID
+}
+
+// TimeLeft - сколько осталось до срока текущей фазы, 0 - срока нет или он прошёл
+func (s *GameSession) TimeLeft() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Deadline.IsZero() {
+		return 0
+	}
+	return max(time.Until(s.Deadline), 0)
+}
+
+// IsBlitz - идёт ли блиц-раунд
+func (s *GameSession) IsBlitz() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Blitz
+}
+
+// SpeedBonusNames - кто получил очко за скорость в блиц-раунде, в порядке отправки фото
+func (s *GameSession) SpeedBonusNames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.SpeedBonus))
+	for _, userID := range s.SpeedBonus {
+		names = append(names, s.userName(userID))
+	}
+	return names
+}
+
+// HasPhoto - прислал ли участник фото в текущем раунде
+func (s *GameSession) HasPhoto(userID int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.UsersPhoto[userID]
+	return ok
+}
+
+// PhotosCount - сколько фото прислано в текущем раунде
+func (s *GameSession) PhotosCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.UsersPhoto)
+}
+
+// UsedTaskSet - копия использованных заданий, безопасная для чтения вне блокировки
+func (s *GameSession) UsedTaskSet() map[string]bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	used := make(map[string]bool, len(s.UsedTasks))
+	for task := range s.UsedTasks {
+		used[task] = true
+	}
+	return used
+}
+
+// VotePhotos - фото текущего голосования в порядке кнопок
+func (s *GameSession) VotePhotos() []VotePhoto {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	photos := make([]VotePhoto, 0, len(s.IndexPhotoToUser))
+	for index := 1; index <= len(s.IndexPhotoToUser); index++ {
+		userID := s.IndexPhotoToUser[index]
+		photos = append(photos, VotePhoto{Index: index, UserID: userID, PhotoID: s.UsersPhoto[userID]})
+	}
+	return photos
+}
+
+// close - помечает сессию завершённой
+func (s *GameSession) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
@@ -44,8 +44,8 @@ func TestGetUserNameSucces(t *testing.T) {
 	})
 
 	t.Run("Unknown user", func(t *testing.T) {
-		if got := s.GetUserName(999); got != "Анонимный Осётр" {
-			t.Errorf("Expected 'Анонимный Осётр', got %s", got)
+		if got := s.GetUserName(999); got != "" {
+			t.Errorf("Expected empty name for the handlers to localize, got %s", got)
 		}
 	})
 }
@@ -121,3 +121,31 @@ func TestTakePhotoWithoutUsername(t *testing.T) {
 		t.Errorf("Expected %s, got %s", userName_2, name)
 	}
 }
+
+func TestAllPlayersSubmitted(t *testing.T) {
+	s := newTestGameSession()
+
+	t.Run("First round without known players", func(t *testing.T) {
+		s.RoundPlayers = map[int64]bool{}
+		s.UsersPhoto = map[int64]string{userID_1: "p1"}
+		if s.AllPlayersSubmitted() {
+			t.Error("First round must wait for the deadline")
+		}
+	})
+
+	s.RoundPlayers = map[int64]bool{userID_1: true, userID_2: true}
+
+	t.Run("Someone is missing", func(t *testing.T) {
+		s.UsersPhoto = map[int64]string{userID_1: "p1"}
+		if s.AllPlayersSubmitted() {
+			t.Error("Expected false while userID_2 has no photo")
+		}
+	})
+
+	t.Run("Everyone submitted", func(t *testing.T) {
+		s.UsersPhoto = map[int64]string{userID_1: "p1", userID_2: "p2"}
+		if !s.AllPlayersSubmitted() {
+			t.Error("Expected true when all round players sent photos")
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/models"
+)
+
+// sessionSnapshot - сериализуемое представление GameSession
+type sessionSnapshot struct {
+	ChatID    int64            `json:"chat_id"`
+	State     State            `json:"state"`
+	Score     map[int64]int    `json:"score"`
+	UsedTasks map[string]bool  `json:"used_tasks"`
+	UserNames map[int64]string `json:"user_names"`
+
+	RoundID          int64            `json:"round_id"`
+	Votes            map[int64]int64  `json:"votes"`
+	UsersPhoto       map[int64]string `json:"users_photo"`
+	CarrentTask      string           `json:"current_task"`
+	IndexPhotoToUser map[int]int64    `json:"index_photo_to_user"`
+	RoundPlayers     map[int64]bool   `json:"round_players"`
+	Blitz            bool             `json:"blitz,omitempty"`
+	SubmitOrder      []int64          `json:"submit_order,omitempty"`
+	SpeedBonus       []int64          `json:"speed_bonus,omitempty"`
+	Deadline         time.Time        `json:"deadline"`
+}
+
+// Snapshot - снимок сессии для сохранения в БД. Вызывается под блокировкой сессии.
+func (s *GameSession) Snapshot() (*models.GameSnapshot, error) {
+	data, err := json.Marshal(sessionSnapshot{
+		ChatID:    s.ChatID,
+		State:     s.FSM.Current(),
+		Score:     s.Score,
+		UsedTasks: s.UsedTasks,
+		UserNames: s.UserNames,
+
+		RoundID:          s.RoundID,
+		Votes:            s.Votes,
+		UsersPhoto:       s.UsersPhoto,
+		CarrentTask:      s.CarrentTask,
+		IndexPhotoToUser: s.IndexPhotoToUser,
+		RoundPlayers:     s.RoundPlayers,
+		Blitz:            s.Blitz,
+		SubmitOrder:      s.SubmitOrder,
+		SpeedBonus:       s.SpeedBonus,
+		Deadline:         s.Deadline,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewGameSnapshot(s.ChatID, string(s.FSM.Current()), string(data)), nil
+}
+
+// RestoreSession - восстанавливает GameSession из снимка
+func RestoreSession(snapshot *models.GameSnapshot) (*GameSession, error) {
+	var data sessionSnapshot
+	if err := json.Unmarshal([]byte(snapshot.Data), &data); err != nil {
+		return nil, fmt.Errorf("повреждённый снимок чата %d: %w", snapshot.ChatID, err)
+	}
+
+	fsm := NewFSM()
+	if _, ok := fsm.transistions[data.State]; !ok {
+		return nil, fmt.Errorf("неизвестное состояние %q в снимке чата %d", data.State, snapshot.ChatID)
+	}
+	fsm.current = data.State
+
+	session := &GameSession{
+		ChatID:    snapshot.ChatID,
+		FSM:       fsm,
+		Score:     data.Score,
+		UsedTasks: data.UsedTasks,
+		UserNames: data.UserNames,
+
+		RoundID:          data.RoundID,
+		Votes:            data.Votes,
+		UsersPhoto:       data.UsersPhoto,
+		CarrentTask:      data.CarrentTask,
+		IndexPhotoToUser: data.IndexPhotoToUser,
+		RoundPlayers:     data.RoundPlayers,
+		Blitz:            data.Blitz,
+		SubmitOrder:      data.SubmitOrder,
+		SpeedBonus:       data.SpeedBonus,
+		Deadline:         data.Deadline,
+	}
+
+	// nil-мапы после JSON заменяем пустыми, чтобы запись в них не паниковала
+	if session.Score == nil {
+		session.Score = make(map[int64]int)
+	}
+	if session.UsedTasks == nil {
+		session.UsedTasks = make(map[string]bool)
+	}
+	if session.UserNames == nil {
+		session.UserNames = make(map[int64]string)
+	}
+	if session.Votes == nil {
+		session.Votes = make(map[int64]int64)
+	}
+	if session.UsersPhoto == nil {
+		session.UsersPhoto = make(map[int64]string)
+	}
+	if session.IndexPhotoToUser == nil {
+		session.IndexPhotoToUser = make(map[int]int64)
+	}
+	if session.RoundPlayers == nil {
+		session.RoundPlayers = make(map[int64]bool)
+	}
+
+	return session, nil
+}
@@ -0,0 +1,93 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/models"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	s := newTestGameSession()
+	s.FSM.current = VoteState
+	s.RoundID = 1700000000000000000
+	s.UsedTasks["Задание"] = true
+	s.UsersPhoto[userID_1] = "photo_1"
+	s.UsersPhoto[userID_2] = "photo_2"
+	s.IndexPhotoToUser = map[int]int64{1: userID_2, 2: userID_1}
+	s.Votes[userID_3] = userID_1
+	s.Blitz = true
+	s.SubmitOrder = []int64{userID_2, userID_1}
+	s.SpeedBonus = []int64{userID_2}
+	s.Deadline = time.Now().Add(time.Minute).Round(0)
+
+	snapshot, err := s.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if snapshot.ChatID != s.ChatID || snapshot.State != string(VoteState) {
+		t.Errorf("Unexpected snapshot header: %+v", snapshot)
+	}
+
+	restored, err := RestoreSession(snapshot)
+	if err != nil {
+		t.Fatalf("RestoreSession failed: %v", err)
+	}
+
+	if restored.FSM.Current() != VoteState {
+		t.Errorf("Expected state %s, got %s", VoteState, restored.FSM.Current())
+	}
+	if !reflect.DeepEqual(restored.Score, s.Score) {
+		t.Errorf("Score mismatch: %v vs %v", restored.Score, s.Score)
+	}
+	if !reflect.DeepEqual(restored.UserNames, s.UserNames) {
+		t.Errorf("UserNames mismatch: %v vs %v", restored.UserNames, s.UserNames)
+	}
+	if !reflect.DeepEqual(restored.UsersPhoto, s.UsersPhoto) {
+		t.Errorf("UsersPhoto mismatch: %v vs %v", restored.UsersPhoto, s.UsersPhoto)
+	}
+	if !reflect.DeepEqual(restored.IndexPhotoToUser, s.IndexPhotoToUser) {
+		t.Errorf("IndexPhotoToUser mismatch: %v vs %v", restored.IndexPhotoToUser, s.IndexPhotoToUser)
+	}
+	if !reflect.DeepEqual(restored.Votes, s.Votes) {
+		t.Errorf("Votes mismatch: %v vs %v", restored.Votes, s.Votes)
+	}
+	if !restored.Blitz || !reflect.DeepEqual(restored.SubmitOrder, s.SubmitOrder) || !reflect.DeepEqual(restored.SpeedBonus, s.SpeedBonus) {
+		t.Errorf("Blitz round mismatch: %v %v %v", restored.Blitz, restored.SubmitOrder, restored.SpeedBonus)
+	}
+	if !restored.Deadline.Equal(s.Deadline) {
+		t.Errorf("Expected deadline %v, got %v", s.Deadline, restored.Deadline)
+	}
+	if restored.RoundID != s.RoundID {
+		t.Errorf("Expected RoundID %d, got %d", s.RoundID, restored.RoundID)
+	}
+	if restored.CarrentTask != s.CarrentTask || !restored.UsedTasks["Задание"] {
+		t.Errorf("Task state not restored: %q %v", restored.CarrentTask, restored.UsedTasks)
+	}
+}
+
+func TestRestoreSessionInvalid(t *testing.T) {
+	t.Run("Broken JSON", func(t *testing.T) {
+		_, err := RestoreSession(models.NewGameSnapshot(chat, string(WaitingState), "{broken"))
+		if err == nil {
+			t.Error("Expected error for broken snapshot")
+		}
+	})
+
+	t.Run("Unknown state", func(t *testing.T) {
+		_, err := RestoreSession(models.NewGameSnapshot(chat, "unknown", `{"state":"unknown"}`))
+		if err == nil {
+			t.Error("Expected error for unknown state")
+		}
+	})
+
+	t.Run("Empty maps", func(t *testing.T) {
+		s, err := RestoreSession(models.NewGameSnapshot(chat, string(WaitingState), `{"state":"waiting"}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		s.Score[userID_1]++
+		s.UsedTasks["task"] = true
+	})
+}
@@ -0,0 +1,158 @@
+package game
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TimerHooks - колбэки таймера. Вызываются из отдельной горутины.
+type TimerHooks struct {
+	Tick     time.Duration            // Как часто вызывать OnTick, 0 - без обратного отсчёта
+	OnTick   func(left time.Duration) // Обратный отсчёт, left - сколько осталось
+	Warn     time.Duration            // За сколько до конца предупредить, 0 - без предупреждения
+	OnWarn   func()                   // Предупреждение о скором окончании
+	OnExpire func()                   // Время вышло, таймер не был отменён
+	OnCancel func()                   // Таймер отменён ходом игры (Stop, StopOwned), но не остановкой бота
+}
+
+// Timers - по одному таймеру на чат. Новый таймер в чате отменяет предыдущий.
+// Таймер помнит сессию, которая его запустила: запоздавший вызов старой игры
+// не отменит таймер новой.
+type Timers struct {
+	mu     sync.Mutex
+	timers map[int64]*chatTimer
+}
+
+type chatTimer struct {
+	cancel  context.CancelFunc
+	owner   *GameSession
+	stopped bool // Отменён через Stop или StopOwned - вызвать OnCancel
+}
+
+func NewTimers() *Timers {
+	return &Timers{
+		timers: make(map[int64]*chatTimer),
+		mu:     sync.Mutex{},
+	}
+}
+
+// Start - запускает таймер на duration для чата. owner - сессия, для которой он идёт.
+func (t *Timers) Start(chatID int64, owner *GameSession, duration time.Duration, hooks TimerHooks) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if prev, ok := t.timers[chatID]; ok {
+		prev.cancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	timer := &chatTimer{cancel: cancel, owner: owner}
+	t.timers[chatID] = timer
+
+	go t.run(ctx, chatID, timer, time.Now().Add(duration), hooks)
+}
+
+// Stop - отменяет таймер чата, если он есть
+func (t *Timers) Stop(chatID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if timer, ok := t.timers[chatID]; ok {
+		timer.stopped = true
+		timer.cancel()
+		delete(t.timers, chatID)
+	}
+}
+
+// StopOwned - отменяет таймер чата, только если его запустила эта сессия
+func (t *Timers) StopOwned(chatID int64, owner *GameSession) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if timer, ok := t.timers[chatID]; ok && timer.owner == owner {
+		timer.stopped = true
+		timer.cancel()
+		delete(t.timers, chatID)
+	}
+}
+
+// StopAll - отменяет таймеры во всех чатах. OnCancel не вызывается: таймеры
+// остановленного бота продолжатся после рестарта.
+func (t *Timers) StopAll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for chatID, timer := range t.timers {
+		timer.cancel()
+		delete(t.timers, chatID)
+	}
+}
+
+// Active - запущен ли таймер в чате
+func (t *Timers) Active(chatID int64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, ok := t.timers[chatID]
+	return ok
+}
+
+func (t *Timers) run(ctx context.Context, chatID int64, timer *chatTimer, deadline time.Time, hooks TimerHooks) {
+	expire := time.NewTimer(time.Until(deadline))
+	defer expire.Stop()
+
+	var warn <-chan time.Time
+	if hooks.Warn > 0 && hooks.OnWarn != nil {
+		if untilWarn := time.Until(deadline.Add(-hooks.Warn)); untilWarn > 0 {
+			warnTimer := time.NewTimer(untilWarn)
+			defer warnTimer.Stop()
+			warn = warnTimer.C
+		}
+	}
+
+	var tick <-chan time.Time
+	if hooks.Tick > 0 && hooks.OnTick != nil {
+		ticker := time.NewTicker(hooks.Tick)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// OnCancel вызывается из этой же горутины - после последнего OnTick
+			t.mu.Lock()
+			stopped := timer.stopped
+			t.mu.Unlock()
+
+			if stopped && hooks.OnCancel != nil {
+				hooks.OnCancel()
+			}
+			return
+		case <-tick:
+			if left := time.Until(deadline); left > 0 {
+				hooks.OnTick(left)
+			}
+		case <-warn:
+			hooks.OnWarn()
+		case <-expire.C:
+			// Таймер мог быть отменён или заменён в момент срабатывания
+			t.mu.Lock()
+			current, ok := t.timers[chatID]
+			if !ok || current != timer {
+				t.mu.Unlock()
+				return
+			}
+			delete(t.timers, chatID)
+			t.mu.Unlock()
+
+			timer.cancel()
+
+			if hooks.OnExpire != nil {
+				hooks.OnExpire()
+			}
+			return
+		}
+	}
+}
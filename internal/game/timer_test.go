@@ -0,0 +1,125 @@
+package game
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTimersExpire(t *testing.T) {
+	timers := NewTimers()
+
+	var ticks atomic.Int32
+	done := make(chan struct{})
+
+	timers.Start(chatID, nil, 60*time.Millisecond, TimerHooks{
+		Tick:     10 * time.Millisecond,
+		OnTick:   func(left time.Duration) { ticks.Add(1) },
+		OnExpire: func() { close(done) },
+	})
+
+	if !timers.Active(chatID) {
+		t.Error("Expected timer to be active")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Timer did not expire")
+	}
+
+	if ticks.Load() == 0 {
+		t.Error("Expected at least one countdown tick")
+	}
+	if timers.Active(chatID) {
+		t.Error("Expired timer must be removed")
+	}
+}
+
+func TestTimersStop(t *testing.T) {
+	timers := NewTimers()
+	fired := make(chan struct{}, 1)
+
+	timers.Start(chatID, nil, 20*time.Millisecond, TimerHooks{OnExpire: func() { fired <- struct{}{} }})
+	timers.Stop(chatID)
+
+	select {
+	case <-fired:
+		t.Error("Stopped timer must not fire")
+	case <-time.After(60 * time.Millisecond):
+	}
+}
+
+func TestTimersCancelHook(t *testing.T) {
+	timers := NewTimers()
+	cancelled := make(chan int64, 3)
+	hooks := func(chatID int64) TimerHooks {
+		return TimerHooks{OnCancel: func() { cancelled <- chatID }}
+	}
+
+	timers.Start(1, nil, time.Hour, hooks(1))
+	timers.Start(2, nil, time.Hour, hooks(2))
+	timers.Start(3, nil, 10*time.Millisecond, hooks(3))
+	timers.Stop(1)
+
+	select {
+	case got := <-cancelled:
+		if got != 1 {
+			t.Errorf("Expected OnCancel of the stopped timer, got chat %d", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stopped timer must call OnCancel")
+	}
+
+	// Ни остановка бота, ни срабатывание таймера отменой не считаются
+	time.Sleep(30 * time.Millisecond)
+	timers.StopAll()
+	select {
+	case got := <-cancelled:
+		t.Errorf("Unexpected OnCancel in chat %d", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestTimersRestartReplacesPrevious(t *testing.T) {
+	timers := NewTimers()
+	first := make(chan struct{}, 1)
+	second := make(chan struct{}, 1)
+
+	timers.Start(chatID, nil, 20*time.Millisecond, TimerHooks{OnExpire: func() { first <- struct{}{} }})
+	timers.Start(chatID, nil, 40*time.Millisecond, TimerHooks{OnExpire: func() { second <- struct{}{} }})
+
+	select {
+	case <-second:
+	case <-time.After(time.Second):
+		t.Fatal("Second timer did not fire")
+	}
+
+	select {
+	case <-first:
+		t.Error("Replaced timer must not fire")
+	default:
+	}
+}
+
+func TestTimersWarnBeforeExpire(t *testing.T) {
+	timers := NewTimers()
+	events := make(chan string, 2)
+
+	timers.Start(chatID, nil, 60*time.Millisecond, TimerHooks{
+		Warn:     40 * time.Millisecond,
+		OnWarn:   func() { events <- "warn" },
+		OnExpire: func() { events <- "expire" },
+	})
+
+	for _, want := range []string{"warn", "expire"} {
+		select {
+		case got := <-events:
+			if got != want {
+				t.Fatalf("Expected %s, got %s", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for %s", want)
+		}
+	}
+}
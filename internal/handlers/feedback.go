@@ -2,11 +2,12 @@ package handlers
 
 import (
 	"fmt"
-	"log"
 
 	messages "github.com/kiselevos/memento_game_bot/assets"
 	"github.com/kiselevos/memento_game_bot/internal/botinterface"
 	"github.com/kiselevos/memento_game_bot/internal/feedback"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
 
 	"gopkg.in/telebot.v3"
 )
@@ -16,26 +17,31 @@ type FeedbackHandlers struct {
 	FeedbackManager *feedback.FeedbackManager
 	AdminsID        []int64
 	BotUsername     string
+	Locales         *i18n.Locales
 
 	FeedbackBtn telebot.InlineButton
 }
 
-func NewFeedbackHandler(bot botinterface.BotInterface, fm *feedback.FeedbackManager, adminsID []int64, botName string) *FeedbackHandlers {
+func NewFeedbackHandler(bot botinterface.BotInterface, fm *feedback.FeedbackManager, adminsID []int64, botName string, loc *i18n.Locales) *FeedbackHandlers {
 	h := &FeedbackHandlers{
 		Bot:             bot,
 		FeedbackManager: fm,
 		AdminsID:        adminsID,
 		BotUsername:     botName,
+		Locales:         loc,
 	}
 
 	h.FeedbackBtn = telebot.InlineButton{
-		Text: "Оставить отзыв",
-		URL:  fmt.Sprintf("https://t.me/%s?start=feedback", h.BotUsername),
+		URL: fmt.Sprintf("https://t.me/%s?start=feedback", h.BotUsername),
 	}
 
 	return h
 }
 
+func (fh *FeedbackHandlers) feedbackBtn(tr i18n.Lang) telebot.InlineButton {
+	return localized(fh.FeedbackBtn, tr.T(messages.BtnFeedback))
+}
+
 func (fh *FeedbackHandlers) Register() {
 	fh.Bot.Handle("/feedback", fh.HandleStartFeedback)
 
@@ -51,10 +57,12 @@ func (fh *FeedbackHandlers) HandleStartFeedback(c telebot.Context) error {
 		return fh.SendFeedbackInstructions(c)
 	}
 
+	tr := fh.Locales.For(c)
+
 	inline := &telebot.ReplyMarkup{}
-	inline.InlineKeyboard = [][]telebot.InlineButton{{fh.FeedbackBtn}}
+	inline.InlineKeyboard = [][]telebot.InlineButton{{fh.feedbackBtn(tr)}}
 
-	return c.Send(messages.StartFeedbackMessage, &telebot.SendOptions{ParseMode: telebot.ModeHTML}, inline)
+	return c.Send(tr.T(messages.StartFeedbackMessage), &telebot.SendOptions{ParseMode: telebot.ModeHTML}, inline)
 }
 
 func (fh *FeedbackHandlers) SendFeedbackInstructions(c telebot.Context) error {
@@ -63,11 +71,13 @@ func (fh *FeedbackHandlers) SendFeedbackInstructions(c telebot.Context) error {
 
 	fh.FeedbackManager.StartFeedback(userID)
 
-	cancelBtn := telebot.InlineButton{Text: "Отменить отзыв", Unique: "cancel_feedback"}
+	tr := fh.Locales.For(c)
+
+	cancelBtn := telebot.InlineButton{Text: tr.T(messages.BtnCancelFeedback), Unique: "cancel_feedback"}
 	inline := &telebot.ReplyMarkup{}
 	inline.InlineKeyboard = [][]telebot.InlineButton{{cancelBtn}}
 
-	return c.Send(messages.AboutFeedback, &telebot.SendOptions{ParseMode: telebot.ModeHTML}, inline)
+	return c.Send(tr.T(messages.AboutFeedback), &telebot.SendOptions{ParseMode: telebot.ModeHTML}, inline)
 }
 
 func (fh *FeedbackHandlers) HandelCancelFeedback(c telebot.Context) error {
@@ -75,13 +85,15 @@ func (fh *FeedbackHandlers) HandelCancelFeedback(c telebot.Context) error {
 
 	fh.FeedbackManager.CancelFeedback(userID)
 
+	tr := fh.Locales.For(c)
+
 	if err := c.Respond(&telebot.CallbackResponse{
-		Text: "Отзыв отменён.",
+		Text: tr.T(messages.FeedbackCancelled),
 	}); err != nil {
-		log.Println("[ERROR] Не удалось отправить callback response:", err)
+		logging.From(c).Error("Не удалось отправить callback response", "err", err)
 	}
 
-	return c.Edit("Отправка отзыва отменена.")
+	return c.Edit(tr.T(messages.FeedbackCancelledMessage))
 }
 
 func (fh *FeedbackHandlers) HandelFeedbackText(c telebot.Context) error {
@@ -94,15 +106,15 @@ func (fh *FeedbackHandlers) HandelFeedbackText(c telebot.Context) error {
 
 	fh.FeedbackManager.CancelFeedback(userID)
 
-	if err := c.Send(messages.ThanksFeedbackMessage, &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
-		log.Println("[ERROR] Проблема с отравкой ообщения после отзыва:", err)
+	if err := c.Send(fh.Locales.For(c).T(messages.ThanksFeedbackMessage), &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+		logging.From(c).Error("Не удалось поблагодарить за отзыв", "err", err)
 	}
 
 	for _, adminID := range fh.AdminsID {
-		adminMsg := fmt.Sprintf("📬 Новый отзыв от @%s (%d):\n\n%s", c.Sender().Username, userID, c.Text())
-		log.Println("[INFO]" + adminMsg)
+		adminMsg := fh.Locales.ForChat(adminID).T(messages.FeedbackForAdmin, c.Sender().Username, userID, c.Text())
+		logging.From(c).Info("Новый отзыв", "username", c.Sender().Username, "text", c.Text())
 		if _, err := fh.Bot.Send(&telebot.User{ID: adminID}, adminMsg); err != nil {
-			log.Println("[ERROR] Проблема с отравкой ообщения после отзыва:", err)
+			logging.From(c).Error("Не удалось переслать отзыв администратору", "admin_id", adminID, "err", err)
 		}
 	}
 
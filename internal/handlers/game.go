@@ -1,13 +1,15 @@
 package handlers
 
 import (
-	"log"
+	"time"
 
 	messages "github.com/kiselevos/memento_game_bot/assets"
 	"github.com/kiselevos/memento_game_bot/internal/bot"
 	"github.com/kiselevos/memento_game_bot/internal/bot/middleware"
 	"github.com/kiselevos/memento_game_bot/internal/botinterface"
 	"github.com/kiselevos/memento_game_bot/internal/game"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
 
 	"gopkg.in/telebot.v3"
 )
@@ -16,34 +18,46 @@ type GameHandlers struct {
 	Bot         botinterface.BotInterface
 	GameManager *game.GameManager
 	BotInfo     *telebot.User
+	Locales     *i18n.Locales
 
 	FeedbackHandlers *FeedbackHandlers
 	RoundHandlers    *RoundHandlers
 
+	// AnimationFrames и AnimationStep - анимация перед первой игрой в чате, 0 кадров - без неё
+	AnimationFrames int
+	AnimationStep   time.Duration
+
 	StartGameBtn telebot.InlineButton
 }
 
-func NewGameHandlers(bot botinterface.BotInterface, gm *game.GameManager, botInfo *telebot.User) *GameHandlers {
+func NewGameHandlers(bot botinterface.BotInterface, gm *game.GameManager, botInfo *telebot.User, loc *i18n.Locales) *GameHandlers {
 
 	h := &GameHandlers{
 		Bot:         bot,
 		GameManager: gm,
 		BotInfo:     botInfo,
+		Locales:     loc,
+
+		AnimationFrames: 5,
+		AnimationStep:   time.Second,
 	}
 	h.StartGameBtn = telebot.InlineButton{
 		Unique: "start_game",
-		Text:   "Новая игра",
 	}
 	return h
 }
 
+func (gh *GameHandlers) startGameBtn(tr i18n.Lang) telebot.InlineButton {
+	return localized(gh.StartGameBtn, tr.T(messages.BtnStartGame))
+}
+
 func (gh *GameHandlers) Register() {
 
 	gh.Bot.Handle("/start", gh.Start, middleware.PrivateOnly(gh.Bot))
-	gh.Bot.Handle("/startgame", gh.StartGame, middleware.OnlyAdmins(gh.Bot))
-	gh.Bot.Handle("/endgame", gh.HandleEndGame, middleware.OnlyAdmins(gh.Bot))
+	gh.Bot.Handle("/startgame", gh.StartGame, middleware.OnlyAdmins(gh.Bot, gh.Locales))
+	gh.Bot.Handle("/endgame", gh.HandleEndGame, middleware.OnlyAdmins(gh.Bot, gh.Locales))
 
-	gh.Bot.Handle(&gh.StartGameBtn, gh.StartGame, middleware.OnlyAdmins(gh.Bot))
+	gh.Bot.Handle(&gh.StartGameBtn, gh.StartGame, middleware.OnlyAdmins(gh.Bot, gh.Locales))
 
 	// Для прод версии
 	// h.Bot.Handle("/startgame", GroupOnly(h.StartGame))
@@ -57,20 +71,22 @@ func (gh *GameHandlers) Start(c telebot.Context) error {
 	if len(args) > 0 && args[0] == "feedback" {
 		return gh.FeedbackHandlers.SendFeedbackInstructions(c)
 	}
-	return c.Send(messages.WelcomeSingleMessage, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	return c.Send(gh.Locales.For(c).T(messages.WelcomeSingleMessage), &telebot.SendOptions{ParseMode: telebot.ModeHTML})
 }
 
 // StartGame - работает из любого места, начинает новую сессию, заканчивая старую
 func (gh *GameHandlers) StartGame(c telebot.Context) error {
 
-	log.Printf("Callback from: %s", c.Sender().Username)
+	logging.From(c).Debug("Запуск игры", "username", c.Sender().Username)
 	err := c.Respond()
 	if err != nil {
-		log.Printf("Respond error: %v", err)
+		logging.From(c).Debug("Respond не выполнен", "err", err)
 	}
 
-	if err := middleware.CheckBotAdminRights(c, gh.BotInfo, gh.Bot); err != nil {
-		log.Println("[ERROR] Запуск игры без прав админа", err)
+	tr := gh.Locales.For(c)
+
+	if err := middleware.CheckBotAdminRights(c, gh.BotInfo, gh.Bot, tr); err != nil {
+		logging.From(c).Warn("Запуск игры без прав админа", "err", err)
 		return err
 	}
 
@@ -78,36 +94,39 @@ func (gh *GameHandlers) StartGame(c telebot.Context) error {
 
 	if gh.GameManager.CheckFirstGame(chatID) {
 		if gh.Bot != nil {
-			gh.Bot.Send(&telebot.Chat{ID: chatID}, messages.WelcomeGroupMessage, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
-			bot.WaitingAnimation(c, gh.Bot, 5)
+			gh.Bot.Send(&telebot.Chat{ID: chatID}, tr.T(messages.WelcomeGroupMessage), &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+			if gh.AnimationFrames > 0 {
+				bot.WaitingAnimation(c, gh.Bot, tr, gh.AnimationFrames, gh.AnimationStep)
+			}
 		}
 	}
 
 	markup := &telebot.ReplyMarkup{}
-	markup.InlineKeyboard = [][]telebot.InlineButton{{gh.RoundHandlers.StartRoundBtn}}
+	markup.InlineKeyboard = [][]telebot.InlineButton{{gh.RoundHandlers.startRoundBtn(tr)}}
 
 	gh.GameManager.StartNewGameSession(chatID)
 
-	return c.Send(messages.GameRulesText, &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup)
+	return c.Send(tr.T(messages.GameRulesText), &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup)
 }
 
 // HandleEndGame - завершение игры, подсчет результатов сесссии
 func (gh *GameHandlers) HandleEndGame(c telebot.Context) error {
 	chatID := c.Chat().ID
+	tr := gh.Locales.For(c)
 
 	markup := &telebot.ReplyMarkup{}
-	markup.InlineKeyboard = [][]telebot.InlineButton{{gh.StartGameBtn}}
+	markup.InlineKeyboard = [][]telebot.InlineButton{{gh.startGameBtn(tr)}}
 
 	session, exist := gh.GameManager.GetSession(chatID)
 	if !exist {
-		return c.Send(messages.GameNotStarted, &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup)
+		return c.Send(tr.T(messages.GameNotStarted), &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup)
 	}
 
-	markup.InlineKeyboard = append(markup.InlineKeyboard, []telebot.InlineButton{gh.FeedbackHandlers.FeedbackBtn})
+	markup.InlineKeyboard = append(markup.InlineKeyboard, []telebot.InlineButton{gh.FeedbackHandlers.feedbackBtn(tr)})
 
-	result := bot.RenderScore(bot.FinalScore, session.TotalScore())
+	result := bot.RenderScore(tr, messages.FinalScore, session.TotalScore())
 
 	gh.GameManager.EndGame(chatID)
 
-	return c.Send(result+"\n"+messages.FinishGameMassage, &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup)
+	return c.Send(result+"\n"+tr.T(messages.FinishGameMassage), &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup)
 }
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"time"
+
+	messages "github.com/kiselevos/memento_game_bot/assets"
+	"github.com/kiselevos/memento_game_bot/internal/bot/middleware"
+	"github.com/kiselevos/memento_game_bot/internal/botinterface"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
+	"github.com/kiselevos/memento_game_bot/internal/tasks"
+
+	"gopkg.in/telebot.v3"
+)
+
+// HistoryHandlers - задания, сыгранные в чате во всех играх
+type HistoryHandlers struct {
+	Bot     botinterface.BotInterface
+	History tasks.History
+	Locales *i18n.Locales
+}
+
+func NewHistoryHandlers(bot botinterface.BotInterface, history tasks.History, loc *i18n.Locales) *HistoryHandlers {
+	return &HistoryHandlers{
+		Bot:     bot,
+		History: history,
+		Locales: loc,
+	}
+}
+
+func (hh *HistoryHandlers) Register() {
+	hh.Bot.Handle("/resethistory", hh.HandleResetHistory, middleware.OnlyAdmins(hh.Bot, hh.Locales))
+}
+
+// HandleResetHistory - чат снова может получить любые задания
+func (hh *HistoryHandlers) HandleResetHistory(c telebot.Context) error {
+	tr := hh.Locales.For(c)
+
+	n, err := hh.History.ResetHistory(c.Chat().ID)
+	if err != nil {
+		logging.From(c).Error("Не удалось сбросить историю заданий", "err", err)
+		return c.Send(tr.T(messages.ErrorMessagesForUser))
+	}
+
+	logging.From(c).Info("История заданий чата сброшена", "tasks", n)
+	return c.Send(tr.T(messages.HistoryReset, n))
+}
+
+// Played - сыгранные в чате задания. При ошибке выбор идёт без истории.
+func (hh *HistoryHandlers) Played(c telebot.Context) map[string]time.Time {
+	played, err := hh.History.Played(c.Chat().ID)
+	if err != nil {
+		logging.From(c).Error("Не удалось получить историю заданий", "err", err)
+		return nil
+	}
+	return played
+}
+
+// MarkPlayed - запоминает задание раунда. Ошибка не мешает игре.
+func (hh *HistoryHandlers) MarkPlayed(c telebot.Context, task tasks.Task) {
+	if err := hh.History.MarkPlayed(c.Chat().ID, task.ID, time.Now()); err != nil {
+		logging.From(c).Error("Не удалось записать историю заданий", "task", task.ID, "err", err)
+	}
+}
@@ -4,11 +4,16 @@ import (
 	"github.com/kiselevos/memento_game_bot/internal/botinterface"
 	"github.com/kiselevos/memento_game_bot/internal/feedback"
 	"github.com/kiselevos/memento_game_bot/internal/game"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
 	"github.com/kiselevos/memento_game_bot/internal/tasks"
 
 	"gopkg.in/telebot.v3"
 )
 
+// logger - для кода вне апдейта (таймеры). В обработчиках - logging.From(c).
+var logger = logging.Component("handlers")
+
 type Handlers struct {
 	Game     *GameHandlers
 	Vote     *VoteHandlers
@@ -16,6 +21,12 @@ type Handlers struct {
 	Feedback *FeedbackHandlers
 	Round    *RoundHandlers
 	Photo    *PhotoHandlers
+	Language *LanguageHandlers
+	Packs    *PacksHandlers
+	Tasks    *TaskAdminHandlers
+	History  *HistoryHandlers
+	Suggest  *SuggestHandlers
+	Submit   *SubmissionHandlers
 }
 
 func NewHandlers(
@@ -24,19 +35,36 @@ func NewHandlers(
 	adminsID []int64,
 	botInfo *telebot.User,
 	gm *game.GameManager,
-	tl *tasks.TasksList,
+	catalog *tasks.Catalog,
+	loc *i18n.Locales,
+	packs tasks.PackSettings,
+	history tasks.History,
+	chatTasks tasks.ChatTasks,
+	submissions tasks.Submissions,
 ) *Handlers {
 
+	tl := catalog.List()
+
 	h := &Handlers{
-		Game:     NewGameHandlers(bot, gm, botInfo),
-		Round:    NewRoundHandlers(bot, gm, tl),
-		Vote:     NewVoteHandlers(bot, gm),
-		Score:    NewScoreHandlers(bot, gm),
-		Feedback: NewFeedbackHandler(bot, fm, adminsID, botInfo.Username),
-		Photo:    NewPhotoHandlers(bot, gm),
+		Game:     NewGameHandlers(bot, gm, botInfo, loc),
+		Round:    NewRoundHandlers(bot, gm, tl, loc),
+		Vote:     NewVoteHandlers(bot, gm, loc),
+		Score:    NewScoreHandlers(bot, gm, loc),
+		Feedback: NewFeedbackHandler(bot, fm, adminsID, botInfo.Username, loc),
+		Photo:    NewPhotoHandlers(bot, gm, loc),
+		Language: NewLanguageHandlers(bot, loc),
+		Packs:    NewPacksHandlers(bot, tl, packs, loc),
+		Tasks:    NewTaskAdminHandlers(bot, catalog, adminsID, loc),
+		History:  NewHistoryHandlers(bot, history, loc),
+		Suggest:  NewSuggestHandlers(bot, chatTasks, loc),
+		Submit:   NewSubmissionHandlers(bot, submissions, catalog, adminsID, loc),
 	}
 
 	h.Round.GameHandlers = h.Game
+	h.Round.VoteHandlers = h.Vote
+	h.Round.PacksHandlers = h.Packs
+	h.Round.HistoryHandlers = h.History
+	h.Round.SuggestHandlers = h.Suggest
 	h.Game.FeedbackHandlers = h.Feedback
 	h.Game.RoundHandlers = h.Round
 	h.Photo.VoteHandlers = h.Vote
@@ -54,4 +82,32 @@ func (h *Handlers) RegisterAll() {
 	h.Feedback.Register()
 	h.Round.Register()
 	h.Photo.Register()
+	h.Language.Register()
+	h.Packs.Register()
+	h.Tasks.Register()
+	h.History.Register()
+	h.Suggest.Register()
+	h.Submit.Register()
+}
+
+// ResumeTimers - снова запускает таймеры приёма фото и голосования в играх,
+// восстановленных после рестарта. Истёкшие сроки срабатывают сразу.
+func (h *Handlers) ResumeTimers() {
+	for _, session := range h.Game.GameManager.ActiveSessions() {
+		chat := &telebot.Chat{ID: session.ChatID}
+
+		switch session.State() {
+		case game.RoundStartState:
+			h.Round.startSubmitTimer(chat, session)
+		case game.VoteState:
+			h.Vote.startVoteTimer(chat, session)
+		}
+	}
+}
+
+// localized - кнопка с текстом на языке чата. В полях хендлеров хранится
+// только Unique, по которому telebot находит обработчик.
+func localized(btn telebot.InlineButton, text string) telebot.InlineButton {
+	btn.Text = text
+	return btn
 }
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"strings"
+
+	messages "github.com/kiselevos/memento_game_bot/assets"
+	"github.com/kiselevos/memento_game_bot/internal/bot/middleware"
+	"github.com/kiselevos/memento_game_bot/internal/botinterface"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
+
+	"gopkg.in/telebot.v3"
+)
+
+type LanguageHandlers struct {
+	Bot     botinterface.BotInterface
+	Locales *i18n.Locales
+
+	LanguageBtn telebot.InlineButton
+}
+
+func NewLanguageHandlers(bot botinterface.BotInterface, loc *i18n.Locales) *LanguageHandlers {
+	h := &LanguageHandlers{
+		Bot:     bot,
+		Locales: loc,
+	}
+
+	h.LanguageBtn = telebot.InlineButton{
+		Unique: "set_language",
+	}
+
+	return h
+}
+
+func (lh *LanguageHandlers) Register() {
+	lh.Bot.Handle("/language", lh.HandleLanguage, middleware.OnlyAdmins(lh.Bot, lh.Locales))
+	lh.Bot.Handle(&lh.LanguageBtn, lh.HandleLanguageBtn, middleware.OnlyAdmins(lh.Bot, lh.Locales))
+}
+
+// HandleLanguage - /language показывает выбор, /language en меняет язык сразу
+func (lh *LanguageHandlers) HandleLanguage(c telebot.Context) error {
+	tr := lh.Locales.For(c)
+
+	code := strings.TrimSpace(c.Message().Payload)
+	if code == "" {
+		markup := &telebot.ReplyMarkup{}
+		row := make([]telebot.InlineButton, 0, len(i18n.Supported()))
+		for _, lang := range i18n.Supported() {
+			btn := localized(lh.LanguageBtn, lang.Name())
+			btn.Data = string(lang)
+			row = append(row, btn)
+		}
+		markup.InlineKeyboard = [][]telebot.InlineButton{row}
+
+		return c.Send(tr.T(messages.LanguageChoose, tr.Name()), &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup)
+	}
+
+	lang, ok := i18n.Parse(code)
+	if !ok {
+		return c.Send(tr.T(messages.LanguageUnknown, supportedCodes()))
+	}
+
+	if err := lh.Locales.Set(c.Chat().ID, lang); err != nil {
+		logging.From(c).Error("Не удалось сохранить язык чата", "lang", lang, "err", err)
+		return c.Send(tr.T(messages.ErrorMessagesForUser))
+	}
+
+	logging.From(c).Info("Язык чата изменён", "lang", lang)
+	return c.Send(lang.T(messages.LanguageChanged))
+}
+
+// HandleLanguageBtn - выбор языка кнопкой, сообщение с выбором заменяется подтверждением
+func (lh *LanguageHandlers) HandleLanguageBtn(c telebot.Context) error {
+	tr := lh.Locales.For(c)
+
+	lang, ok := i18n.Parse(c.Data())
+	if !ok {
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.LanguageUnknown, supportedCodes())})
+	}
+
+	if err := lh.Locales.Set(c.Chat().ID, lang); err != nil {
+		logging.From(c).Error("Не удалось сохранить язык чата", "lang", lang, "err", err)
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.ErrorMessagesForUser)})
+	}
+
+	logging.From(c).Info("Язык чата изменён", "lang", lang)
+	_ = c.Respond()
+	return c.Edit(lang.T(messages.LanguageChanged))
+}
+
+func supportedCodes() string {
+	codes := make([]string, 0, len(i18n.Supported()))
+	for _, lang := range i18n.Supported() {
+		codes = append(codes, string(lang))
+	}
+	return strings.Join(codes, ", ")
+}
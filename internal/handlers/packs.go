@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	messages "github.com/kiselevos/memento_game_bot/assets"
+	"github.com/kiselevos/memento_game_bot/internal/bot/middleware"
+	"github.com/kiselevos/memento_game_bot/internal/botinterface"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
+	"github.com/kiselevos/memento_game_bot/internal/tasks"
+
+	"gopkg.in/telebot.v3"
+)
+
+type PacksHandlers struct {
+	Bot       botinterface.BotInterface
+	TasksList *tasks.TasksList
+	Settings  tasks.PackSettings
+	Locales   *i18n.Locales
+
+	TogglePackBtn telebot.InlineButton
+}
+
+func NewPacksHandlers(bot botinterface.BotInterface, tl *tasks.TasksList, settings tasks.PackSettings, loc *i18n.Locales) *PacksHandlers {
+	h := &PacksHandlers{
+		Bot:       bot,
+		TasksList: tl,
+		Settings:  settings,
+		Locales:   loc,
+	}
+
+	h.TogglePackBtn = telebot.InlineButton{
+		Unique: "toggle_pack",
+	}
+
+	return h
+}
+
+func (ph *PacksHandlers) Register() {
+	ph.Bot.Handle("/packs", ph.HandlePacks, middleware.OnlyAdmins(ph.Bot, ph.Locales))
+	ph.Bot.Handle(&ph.TogglePackBtn, ph.HandleTogglePack, middleware.OnlyAdmins(ph.Bot, ph.Locales))
+}
+
+// HandlePacks - список паков с кнопками включения
+func (ph *PacksHandlers) HandlePacks(c telebot.Context) error {
+	tr := ph.Locales.For(c)
+
+	disabled, err := ph.Settings.DisabledPacks(c.Chat().ID)
+	if err != nil {
+		logging.From(c).Error("Не удалось получить паки чата", "err", err)
+		return c.Send(tr.T(messages.ErrorMessagesForUser))
+	}
+
+	return c.Send(tr.T(messages.PacksChoose), ph.packsMarkup(tr, toSet(disabled)))
+}
+
+// HandleTogglePack - включает или выключает пак, последний включённый выключить нельзя
+func (ph *PacksHandlers) HandleTogglePack(c telebot.Context) error {
+	tr := ph.Locales.For(c)
+	chatID := c.Chat().ID
+
+	var pack *tasks.Pack
+	for _, p := range ph.TasksList.Packs() {
+		if p.ID == c.Data() {
+			pack = p
+		}
+	}
+	if pack == nil {
+		// Пак убрали из файлов после того, как показали кнопки
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.ErrorMessagesForUser)})
+	}
+
+	stored, err := ph.Settings.DisabledPacks(chatID)
+	if err != nil {
+		logging.From(c).Error("Не удалось получить паки чата", "err", err)
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.ErrorMessagesForUser)})
+	}
+	disabled := toSet(stored)
+
+	response := messages.PackEnabled
+	if disabled[pack.ID] {
+		delete(disabled, pack.ID)
+	} else {
+		disabled[pack.ID] = true
+		response = messages.PackDisabled
+		if ph.enabledCount(disabled) == 0 {
+			return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.PacksLastEnabled), ShowAlert: true})
+		}
+	}
+
+	// Сохраняем только существующие паки: удалённые из файлов не копятся
+	var list []string
+	for _, p := range ph.TasksList.Packs() {
+		if disabled[p.ID] {
+			list = append(list, p.ID)
+		}
+	}
+	if err := ph.Settings.SetDisabledPacks(chatID, list); err != nil {
+		logging.From(c).Error("Не удалось сохранить паки чата", "err", err)
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.ErrorMessagesForUser)})
+	}
+
+	logging.From(c).Info("Паки чата изменены", "pack", pack.ID, "disabled", list)
+	_ = c.Respond(&telebot.CallbackResponse{Text: tr.T(response, pack.TitleFor(tr))})
+	return c.Edit(tr.T(messages.PacksChoose), ph.packsMarkup(tr, disabled))
+}
+
+// Disabled - выключенные паки чата. При ошибке хранилища играем всеми паками.
+func (ph *PacksHandlers) Disabled(c telebot.Context) map[string]bool {
+	disabled, err := ph.Settings.DisabledPacks(c.Chat().ID)
+	if err != nil {
+		logging.From(c).Error("Не удалось получить паки чата", "err", err)
+		return nil
+	}
+	return toSet(disabled)
+}
+
+func (ph *PacksHandlers) packsMarkup(tr i18n.Lang, disabled map[string]bool) *telebot.ReplyMarkup {
+	markup := &telebot.ReplyMarkup{}
+	for _, pack := range ph.TasksList.Packs() {
+		key := messages.BtnPackOn
+		if disabled[pack.ID] {
+			key = messages.BtnPackOff
+		}
+		btn := localized(ph.TogglePackBtn, tr.T(key, pack.TitleFor(tr), len(pack.Tasks)))
+		btn.Data = pack.ID
+		markup.InlineKeyboard = append(markup.InlineKeyboard, []telebot.InlineButton{btn})
+	}
+	return markup
+}
+
+func (ph *PacksHandlers) enabledCount(disabled map[string]bool) int {
+	count := 0
+	for _, pack := range ph.TasksList.Packs() {
+		if !disabled[pack.ID] {
+			count++
+		}
+	}
+	return count
+}
+
+func toSet(list []string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, item := range list {
+		set[item] = true
+	}
+	return set
+}
@@ -4,8 +4,11 @@ import (
 	"fmt"
 
 	messages "github.com/kiselevos/memento_game_bot/assets"
+	"github.com/kiselevos/memento_game_bot/internal/bot"
 	"github.com/kiselevos/memento_game_bot/internal/botinterface"
 	"github.com/kiselevos/memento_game_bot/internal/game"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
 
 	"gopkg.in/telebot.v3"
 )
@@ -13,15 +16,17 @@ import (
 type PhotoHandlers struct {
 	Bot         botinterface.BotInterface
 	GameManager *game.GameManager
+	Locales     *i18n.Locales
 
 	VoteHandlers *VoteHandlers
 }
 
-func NewPhotoHandlers(bot botinterface.BotInterface, gm *game.GameManager) *PhotoHandlers {
+func NewPhotoHandlers(bot botinterface.BotInterface, gm *game.GameManager, loc *i18n.Locales) *PhotoHandlers {
 
 	h := &PhotoHandlers{
 		Bot:         bot,
 		GameManager: gm,
+		Locales:     loc,
 	}
 
 	return h
@@ -42,7 +47,7 @@ func (ph *PhotoHandlers) TakeUserPhoto(c telebot.Context) error {
 	user := c.Sender()
 
 	session, exist := ph.GameManager.GetSession(chat.ID)
-	if !exist || session.FSM.Current() != game.RoundStartState {
+	if !exist || session.State() != game.RoundStartState {
 		return nil
 	}
 
@@ -53,24 +58,48 @@ func (ph *PhotoHandlers) TakeUserPhoto(c telebot.Context) error {
 
 	fileID := photo.File.FileID
 
-	_, exist = session.UsersPhoto[user.ID]
-
-	if exist {
+	if session.HasPhoto(user.ID) {
 		//TODO: Подумать о функционале, возможно заменять фото???
 		return nil
 	}
 
+	tr := ph.Locales.For(c)
+
 	markup := &telebot.ReplyMarkup{}
-	markup.InlineKeyboard = [][]telebot.InlineButton{{ph.VoteHandlers.StartVoteBtn}}
+	markup.InlineKeyboard = [][]telebot.InlineButton{{ph.VoteHandlers.startVoteBtn(tr)}}
 
 	// Принимаем и удаялем фото
 	_ = ph.Bot.Delete(c.Message())
 
-	ph.GameManager.TakePhoto(chat.ID, user, fileID)
+	roundID, allIn, err := ph.GameManager.TakePhoto(chat.ID, user, fileID)
+	if err != nil {
+		logging.From(c).Info("Фото не принято", "err", err)
+		return nil
+	}
+
+	// В блице чат не засоряется: подтверждение уходит в личку, фото откроются по таймеру
+	if session.IsBlitz() {
+		if _, err := ph.Bot.Send(user, tr.T(messages.BlitsPhotoReceived), &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+			// Участник мог ни разу не писать боту в личку
+			logging.From(c).Debug("Подтверждение блиц-фото не отправлено", "err", err)
+		}
+		if !allIn {
+			return nil
+		}
+		_, _ = ph.Bot.Send(chat, tr.T(messages.AllPhotosReceived))
+		return ph.VoteHandlers.OpenVoting(chat, session, roundID)
+	}
 
-	return c.Send(
-		fmt.Sprintf("<b>%s</b>, %s", session.GetUserName(user.ID), messages.PhotoReceived),
+	err = c.Send(
+		fmt.Sprintf("<b>%s</b>, %s", bot.PlayerName(tr, session.GetUserName(user.ID)), tr.T(messages.PhotoReceived)),
 		&telebot.SendOptions{ParseMode: telebot.ModeHTML},
 		markup,
 	)
+	if err != nil || !allIn {
+		return err
+	}
+
+	// Все участники раунда прислали фото - не ждём окончания таймера
+	_, _ = ph.Bot.Send(chat, tr.T(messages.AllPhotosReceived))
+	return ph.VoteHandlers.OpenVoting(chat, session, roundID)
 }
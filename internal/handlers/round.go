@@ -1,12 +1,14 @@
 package handlers
 
 import (
-	"log"
+	"html"
 
 	messages "github.com/kiselevos/memento_game_bot/assets"
 	"github.com/kiselevos/memento_game_bot/internal/bot/middleware"
 	"github.com/kiselevos/memento_game_bot/internal/botinterface"
 	"github.com/kiselevos/memento_game_bot/internal/game"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
 	"github.com/kiselevos/memento_game_bot/internal/tasks"
 
 	"gopkg.in/telebot.v3"
@@ -16,30 +18,39 @@ type RoundHandlers struct {
 	Bot         botinterface.BotInterface
 	GameManager *game.GameManager
 	TasksList   *tasks.TasksList
+	Locales     *i18n.Locales
 
-	GameHandlers *GameHandlers
+	GameHandlers    *GameHandlers
+	VoteHandlers    *VoteHandlers
+	PacksHandlers   *PacksHandlers
+	HistoryHandlers *HistoryHandlers
+	SuggestHandlers *SuggestHandlers
 
 	StartRoundBtn telebot.InlineButton
 }
 
-func NewRoundHandlers(bot botinterface.BotInterface, gm *game.GameManager, tl *tasks.TasksList) *RoundHandlers {
+func NewRoundHandlers(bot botinterface.BotInterface, gm *game.GameManager, tl *tasks.TasksList, loc *i18n.Locales) *RoundHandlers {
 
 	h := &RoundHandlers{
 		Bot:         bot,
 		GameManager: gm,
 		TasksList:   tl,
+		Locales:     loc,
 	}
 	h.StartRoundBtn = telebot.InlineButton{
 		Unique: "start_round",
-		Text:   "Начать раунд",
 	}
 	return h
 }
 
+func (rh *RoundHandlers) startRoundBtn(tr i18n.Lang) telebot.InlineButton {
+	return localized(rh.StartRoundBtn, tr.T(messages.BtnStartRound))
+}
+
 func (rh *RoundHandlers) Register() {
 
-	rh.Bot.Handle(&rh.StartRoundBtn, rh.HandleStartRound, middleware.OnlyAdmins(rh.Bot))
-	rh.Bot.Handle("/newround", rh.HandleStartRound, middleware.OnlyAdmins(rh.Bot))
+	rh.Bot.Handle(&rh.StartRoundBtn, rh.HandleStartRound, middleware.OnlyAdmins(rh.Bot, rh.Locales))
+	rh.Bot.Handle("/newround", rh.HandleStartRound, middleware.OnlyAdmins(rh.Bot, rh.Locales))
 
 	// Для прод версии
 	// h.Bot.Handle(&h.startRoundBtn, GroupOnly(h.HandleStartRound))
@@ -48,46 +59,104 @@ func (rh *RoundHandlers) Register() {
 
 func (rh *RoundHandlers) HandleStartRound(c telebot.Context) error {
 
-	log.Printf("Callback from: %s", c.Sender().Username)
+	logging.From(c).Debug("Запуск раунда", "username", c.Sender().Username)
 	err := c.Respond()
 	if err != nil {
-		log.Printf("Respond error: %v", err)
+		logging.From(c).Debug("Respond не выполнен", "err", err)
 	}
 	//Убираем анимацию мерцания кнопки
 	if c.Callback() != nil {
 		_ = c.Respond(&telebot.CallbackResponse{})
 	}
 
+	tr := rh.Locales.For(c)
+
 	markup := &telebot.ReplyMarkup{}
-	markup.InlineKeyboard = [][]telebot.InlineButton{{rh.GameHandlers.StartGameBtn}}
+	markup.InlineKeyboard = [][]telebot.InlineButton{{rh.GameHandlers.startGameBtn(tr)}}
 
 	chatID := c.Chat().ID
 
 	session, exist := rh.GameManager.GetSession(chatID)
 	if !exist {
-		log.Printf("[INFO] Попытка запуска раунда без начала новой игры в чате %d", chatID)
-		return c.Send(messages.GameNotStarted, &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup)
+		logging.From(c).Info("Попытка запуска раунда без начала новой игры")
+		return c.Send(tr.T(messages.GameNotStarted), &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup)
 	}
 
-	task, err := rh.TasksList.GetRandomTask(session.UsedTasks)
+	task, err := rh.TasksList.GetRandomTask(session.UsedTaskSet(), rh.PacksHandlers.Disabled(c), rh.HistoryHandlers.Played(c), rh.SuggestHandlers.Tasks(c))
 	if err != nil {
-		log.Printf("[INFO] Все вопросы в чате %d закончены", chatID)
+		logging.From(c).Info("Все задания в чате закончены")
 		rh.GameHandlers.HandleEndGame(c) // автоматический финал
 		return nil
 	}
 
 	err = rh.GameManager.StartNewRound(session, task)
 	if err != nil {
-		log.Printf("[ERROR] Ошибка начала нового раунда %d, %v", chatID, err)
-		return c.Send(messages.ErrorMessagesForUser, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+		logging.From(c).Error("Ошибка начала нового раунда", "err", err)
+		return c.Send(tr.T(messages.ErrorMessagesForUser), &telebot.SendOptions{ParseMode: telebot.ModeHTML})
 	}
+	rh.HistoryHandlers.MarkPlayed(c, task)
 
-	text := messages.RoundStartedMessage + "\n<b>" + task + "</b>"
+	text := tr.T(messages.RoundStartedMessage) + "\n<b>" + taskText(tr, task) + "</b>"
+	if task.Author != "" {
+		text += "\n<i>" + tr.T(messages.TaskAuthorCredit, html.EscapeString(task.Author)) + "</i>"
+	}
 
-	btn := rh.StartRoundBtn
-	btn.Text = "🔁 Поменять задание"
+	duration := rh.GameManager.SubmitDuration(session)
+	switch {
+	case session.IsBlitz():
+		text += "\n\n" + tr.T(messages.BlitzRules, formatLeft(duration), game.BlitzBonusPlaces)
+	case duration > 0:
+		text += "\n\n" + tr.T(messages.SubmitDeadlineMessage, formatLeft(duration))
+	}
+
+	btn := localized(rh.StartRoundBtn, tr.T(messages.BtnChangeTask))
 
 	markup.InlineKeyboard = [][]telebot.InlineButton{{btn}}
 
-	return c.Send(text, &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup)
+	if err := c.Send(text, &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup); err != nil {
+		return err
+	}
+
+	rh.startSubmitTimer(c.Chat(), session)
+	return nil
+}
+
+// startSubmitTimer - по истечении времени на фото сам открывает голосование.
+// Таймер помнит свой раунд: после смены задания запоздавший колбэк ничего не делает.
+func (rh *RoundHandlers) startSubmitTimer(chat *telebot.Chat, session *game.GameSession) {
+	roundID := session.CurrentRoundID()
+
+	onWarn := func() {
+		_, _ = rh.Bot.Send(chat, rh.Locales.ForChat(chat.ID).T(messages.SubmitOneMinuteLeft))
+	}
+
+	onExpire := func() {
+		logger.Info("Время на отправку фото вышло", "chat_id", chat.ID)
+
+		if session.CurrentRoundID() != roundID || session.State() != game.RoundStartState {
+			return
+		}
+		tr := rh.Locales.ForChat(chat.ID)
+		if session.PhotosCount() == 0 {
+			_, _ = rh.Bot.Send(chat, tr.T(messages.NoPhotosByDeadline))
+			return
+		}
+
+		_, _ = rh.Bot.Send(chat, tr.T(messages.SubmitTimeIsUp))
+		if err := rh.VoteHandlers.OpenVoting(chat, session, roundID); err != nil {
+			logger.Error("Не удалось открыть голосование по таймеру", "chat_id", chat.ID, "err", err)
+		}
+	}
+
+	rh.GameManager.StartSubmitTimer(session, onWarn, onExpire)
+}
+
+// taskText - текст задания на языке чата, блиц помечается
+func taskText(tr i18n.Lang, task tasks.Task) string {
+	// Задания пишут админы и участники чатов, а сообщение раунда - в HTML
+	text := html.EscapeString(task.TextFor(tr))
+	if task.Blitz {
+		text = tr.T(messages.BlitzTaskLabel) + " " + text
+	}
+	return text
 }
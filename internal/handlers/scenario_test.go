@@ -0,0 +1,785 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	messages "github.com/kiselevos/memento_game_bot/assets"
+	"github.com/kiselevos/memento_game_bot/internal/bottest"
+	"github.com/kiselevos/memento_game_bot/internal/events"
+	"github.com/kiselevos/memento_game_bot/internal/feedback"
+	"github.com/kiselevos/memento_game_bot/internal/game"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/repositories/memory"
+	"github.com/kiselevos/memento_game_bot/internal/tasks"
+
+	"gopkg.in/telebot.v3"
+)
+
+// harness - бот с настоящими хендлерами и GameManager на in-memory репозиториях
+type harness struct {
+	t  *testing.T
+	fb *bottest.FakeBot
+	h  *Handlers
+	gm *game.GameManager
+
+	chat    *telebot.Chat
+	admin   *telebot.User
+	owner   *telebot.User // владелец бота из AdminsID
+	players []*telebot.User
+}
+
+func newHarness(t *testing.T, settings game.Settings) *harness {
+	t.Helper()
+	tl := tasks.NewTasksListForTest([]string{"Сфотографируй кота", "Сфотографируй небо"})
+	return newHarnessWithTasks(t, settings, tl.Packs())
+}
+
+func newHarnessWithTasks(t *testing.T, settings game.Settings, packs []*tasks.Pack) *harness {
+	t.Helper()
+
+	fb := bottest.NewFakeBot()
+	botInfo := bottest.User(999, "memento_bot")
+	chat := bottest.GroupChat(-100)
+	admin := bottest.User(1, "admin")
+	owner := bottest.User(42, "owner")
+
+	fb.SetRole(chat.ID, botInfo.ID, telebot.Administrator)
+	fb.SetRole(chat.ID, admin.ID, telebot.Creator)
+
+	taskRepo := memory.NewTaskRepo()
+	catalog, err := tasks.NewCatalog(taskRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := catalog.Import(packs); err != nil {
+		t.Fatal(err)
+	}
+
+	gm := game.NewGameManager(memory.NewUserRepo(), memory.NewSessionRepo(),
+		memory.NewSnapshotRepo(), events.NewBus(), settings)
+	chatSettings := memory.NewChatSettingsRepo()
+	loc := i18n.NewLocales(chatSettings, i18n.RU)
+
+	h := NewHandlers(fb, feedback.NewFeedbackManager(time.Minute), []int64{owner.ID}, botInfo, gm, catalog, loc, chatSettings, memory.NewChatHistoryRepo(), memory.NewChatTaskRepo(), memory.NewTaskSubmissionRepo())
+	h.Game.AnimationStep = time.Millisecond
+	h.Vote.RevealDelay = 0
+	h.RegisterAll()
+
+	t.Cleanup(func() { gm.EndGame(chat.ID) })
+
+	return &harness{
+		t:     t,
+		fb:    fb,
+		h:     h,
+		gm:    gm,
+		chat:  chat,
+		admin: admin,
+		owner: owner,
+		players: []*telebot.User{
+			admin,
+			bottest.User(2, "bob"),
+			bottest.User(3, "carol"),
+		},
+	}
+}
+
+// do - прогоняет апдейт и возвращает всё, что бот сделал в ответ
+func (hs *harness) do(u telebot.Update) []bottest.Action {
+	hs.t.Helper()
+
+	if err := hs.fb.Dispatch(u); err != nil {
+		hs.t.Fatalf("handler error: %v", err)
+	}
+	return hs.fb.Take()
+}
+
+func (hs *harness) command(from *telebot.User, text string) []bottest.Action {
+	return hs.do(bottest.Text(hs.chat, from, text))
+}
+
+func (hs *harness) press(from *telebot.User, btn *telebot.InlineButton) []bottest.Action {
+	hs.t.Helper()
+
+	if btn == nil {
+		hs.t.Fatal("button not found")
+	}
+	return hs.do(bottest.Callback(hs.chat, from, *btn))
+}
+
+// lastSent - последнее отправленное сообщение (не фото)
+func lastSent(t *testing.T, actions []bottest.Action) bottest.Action {
+	t.Helper()
+
+	sent := bottest.Filter(actions, bottest.ActionSend)
+	for i := len(sent) - 1; i >= 0; i-- {
+		if sent[i].Photo == nil {
+			return sent[i]
+		}
+	}
+	t.Fatalf("no message sent, actions: %+v", actions)
+	return bottest.Action{}
+}
+
+func TestScenarioFullRound(t *testing.T) {
+	hs := newHarness(t, game.Settings{})
+
+	// /startgame: приветствие первой игры, анимация и правила с кнопкой раунда
+	actions := hs.command(hs.admin, "/startgame")
+	texts := bottest.Texts(actions)
+	if len(texts) < 3 || texts[0] != i18n.RU.T(messages.WelcomeGroupMessage) {
+		t.Fatalf("expected welcome first, got %q", texts)
+	}
+	if len(bottest.Filter(actions, bottest.ActionDelete)) != 1 {
+		t.Error("waiting animation must be deleted")
+	}
+	rules := lastSent(t, actions)
+	if rules.Text != i18n.RU.T(messages.GameRulesText) || rules.ChatID != hs.chat.ID {
+		t.Fatalf("expected rules, got %q", rules.Text)
+	}
+
+	// Кнопка "Начать раунд" из правил
+	actions = hs.press(hs.admin, rules.Button(hs.h.Round.StartRoundBtn.Unique))
+	round := lastSent(t, actions)
+	if !strings.HasPrefix(round.Text, i18n.RU.T(messages.RoundStartedMessage)) {
+		t.Fatalf("expected round message, got %q", round.Text)
+	}
+	if btn := round.Button(hs.h.Round.StartRoundBtn.Unique); btn == nil || btn.Text != "🔁 Поменять задание" {
+		t.Errorf("expected change task button, got %+v", round.Buttons())
+	}
+
+	// Три игрока присылают фото: фото удаляется, в чат пишется подтверждение
+	for i, p := range hs.players {
+		u := bottest.Photo(hs.chat, p, "photo-"+p.Username)
+		actions = hs.do(u)
+
+		deleted := bottest.Filter(actions, bottest.ActionDelete)
+		if len(deleted) != 1 || deleted[0].MessageID != u.Message.ID {
+			t.Fatalf("player %d: expected photo message deleted, got %+v", i, deleted)
+		}
+		confirm := lastSent(t, actions)
+		if !strings.Contains(confirm.Text, p.Username) || confirm.Button(hs.h.Vote.StartVoteBtn.Unique) == nil {
+			t.Errorf("player %d: unexpected confirmation %q", i, confirm.Text)
+		}
+	}
+
+	// Повторное фото игнорируется
+	if actions = hs.do(bottest.Photo(hs.chat, hs.players[1], "again")); len(actions) != 0 {
+		t.Errorf("second photo must be ignored, got %+v", actions)
+	}
+
+	// /vote: объявление, фото с кнопками, сообщение с кнопкой завершения
+	actions = hs.command(hs.admin, "/vote")
+	texts = bottest.Texts(actions)
+	if len(texts) != 2 || texts[0] != i18n.RU.T(messages.VotingStartedMessage) || texts[1] != i18n.RU.T(messages.VoitingMessage) {
+		t.Fatalf("unexpected voting texts: %q", texts)
+	}
+
+	voteButtons := make(map[string]*telebot.InlineButton) // владелец фото -> кнопка
+	for _, a := range bottest.Filter(actions, bottest.ActionSend) {
+		if a.Photo == nil {
+			continue
+		}
+		owner := strings.TrimPrefix(a.Photo.FileID, "photo-")
+		voteButtons[owner] = a.Button(hs.h.Vote.VoteBtn.Unique)
+	}
+	if len(voteButtons) != len(hs.players) {
+		t.Fatalf("expected %d photos with vote buttons, got %d", len(hs.players), len(voteButtons))
+	}
+	finish := lastSent(t, actions).Button(hs.h.Vote.FinishVoteBtn.Unique)
+
+	// За себя нельзя
+	actions = hs.press(hs.players[0], voteButtons[hs.players[0].Username])
+	if len(actions) != 1 || actions[0].Kind != bottest.ActionRespond || actions[0].Text != i18n.RU.T(messages.VotedForSelf) {
+		t.Errorf("expected self vote rejection, got %+v", actions)
+	}
+
+	// Каждый голосует за следующего по кругу
+	for i, p := range hs.players {
+		target := hs.players[(i+1)%len(hs.players)]
+		actions = hs.press(p, voteButtons[target.Username])
+
+		responds := bottest.Filter(actions, bottest.ActionRespond)
+		if len(responds) != 1 || responds[0].Text != i18n.RU.T(messages.VotedReceived) {
+			t.Errorf("%s: expected vote accepted, got %+v", p.Username, actions)
+		}
+		if msg := lastSent(t, actions); !strings.HasPrefix(msg.Text, "@"+p.Username) {
+			t.Errorf("%s: unexpected vote message %q", p.Username, msg.Text)
+		}
+	}
+
+	// Повторный голос
+	actions = hs.press(hs.players[1], voteButtons[hs.players[2].Username])
+	if len(actions) != 1 || actions[0].Text != i18n.RU.T(messages.VotedAlready) {
+		t.Errorf("expected already voted, got %+v", actions)
+	}
+
+	// Кнопка завершения голосования: у каждого по одному огоньку
+	actions = hs.press(hs.admin, finish)
+	result := lastSent(t, actions)
+	if !strings.HasPrefix(result.Text, i18n.RU.T(messages.RoundScore)) || strings.Count(result.Text, "🔥") != 3 {
+		t.Errorf("unexpected round result %q", result.Text)
+	}
+	if result.Button(hs.h.Round.StartRoundBtn.Unique) == nil {
+		t.Error("round result must offer next round")
+	}
+
+	// Голос после завершения
+	actions = hs.press(hs.players[0], voteButtons[hs.players[1].Username])
+	if len(actions) != 1 || !actions[0].Response.ShowAlert {
+		t.Errorf("expected alert for late vote, got %+v", actions)
+	}
+
+	// /score и /endgame
+	score := lastSent(t, hs.command(hs.players[2], "/score"))
+	if !strings.HasPrefix(score.Text, i18n.RU.T(messages.GameScore)) {
+		t.Errorf("unexpected score %q", score.Text)
+	}
+
+	final := lastSent(t, hs.command(hs.admin, "/endgame"))
+	if !strings.HasPrefix(final.Text, i18n.RU.T(messages.FinalScore)) || final.Button(hs.h.Game.StartGameBtn.Unique) == nil {
+		t.Errorf("unexpected final %q", final.Text)
+	}
+	if _, ok := hs.gm.GetSession(hs.chat.ID); ok {
+		t.Error("session must be closed after /endgame")
+	}
+}
+
+func TestScenarioAdminOnlyCommands(t *testing.T) {
+	hs := newHarness(t, game.Settings{})
+	player := hs.players[1]
+
+	for _, cmd := range []string{"/startgame", "/newround", "/vote", "/finishvote", "/endgame", "/language", "/packs", "/resethistory"} {
+		msg := lastSent(t, hs.command(player, cmd))
+		if !strings.HasPrefix(msg.Text, "🚫") {
+			t.Errorf("%s: expected admin-only refusal, got %q", cmd, msg.Text)
+		}
+	}
+	if _, ok := hs.gm.GetSession(hs.chat.ID); ok {
+		t.Error("non-admin must not start a game")
+	}
+
+	// Кнопка тоже закрыта, ответ - во всплывающем уведомлении
+	actions := hs.press(player, &hs.h.Game.StartGameBtn)
+	if len(actions) != 1 || actions[0].Kind != bottest.ActionRespond {
+		t.Errorf("expected callback refusal, got %+v", actions)
+	}
+}
+
+func TestScenarioNoGame(t *testing.T) {
+	hs := newHarness(t, game.Settings{})
+
+	for _, cmd := range []string{"/newround", "/score", "/endgame"} {
+		msg := lastSent(t, hs.command(hs.admin, cmd))
+		if msg.Text != i18n.RU.T(messages.GameNotStarted) {
+			t.Errorf("%s: expected game not started, got %q", cmd, msg.Text)
+		}
+	}
+
+	// Фото вне раунда молча игнорируются
+	if actions := hs.do(bottest.Photo(hs.chat, hs.admin, "x")); len(actions) != 0 {
+		t.Errorf("photo outside round must be ignored, got %+v", actions)
+	}
+}
+
+func TestScenarioAllPhotosOpenVoting(t *testing.T) {
+	hs := newHarness(t, game.Settings{SubmitDuration: time.Hour})
+
+	// Первый раунд знакомит бота с игроками
+	hs.command(hs.admin, "/startgame")
+	round := lastSent(t, hs.command(hs.admin, "/newround"))
+	if !strings.Contains(round.Text, "60:00") {
+		t.Errorf("expected deadline in round message, got %q", round.Text)
+	}
+	hs.do(bottest.Photo(hs.chat, hs.players[0], "photo-a"))
+	hs.do(bottest.Photo(hs.chat, hs.players[1], "photo-b"))
+	hs.command(hs.admin, "/vote")
+	hs.command(hs.admin, "/finishvote")
+
+	// Во втором раунде голосование открывается, как только прислали все участники
+	hs.command(hs.admin, "/newround")
+	hs.do(bottest.Photo(hs.chat, hs.players[0], "photo-c"))
+	actions := hs.do(bottest.Photo(hs.chat, hs.players[1], "photo-d"))
+
+	texts := bottest.Texts(actions)
+	if len(texts) < 3 || texts[1] != i18n.RU.T(messages.AllPhotosReceived) || texts[2] != i18n.RU.T(messages.VotingStartedMessage) {
+		t.Fatalf("expected automatic voting, got %q", texts)
+	}
+	session, _ := hs.gm.GetSession(hs.chat.ID)
+	if session.State() != game.VoteState {
+		t.Fatalf("expected voting state, got %s", session.State())
+	}
+}
+
+func TestScenarioStaleVoteButton(t *testing.T) {
+	hs := newHarness(t, game.Settings{})
+
+	hs.command(hs.admin, "/startgame")
+	hs.command(hs.admin, "/newround")
+	hs.do(bottest.Photo(hs.chat, hs.players[0], "photo-a"))
+	hs.do(bottest.Photo(hs.chat, hs.players[1], "photo-b"))
+
+	var oldBtn *telebot.InlineButton
+	for _, a := range hs.command(hs.admin, "/vote") {
+		if a.Photo != nil {
+			oldBtn = a.Button(hs.h.Vote.VoteBtn.Unique)
+		}
+	}
+	hs.command(hs.admin, "/finishvote")
+
+	// Новый раунд и голосование - старая кнопка уже не действует
+	hs.command(hs.admin, "/newround")
+	hs.do(bottest.Photo(hs.chat, hs.players[0], "photo-c"))
+	hs.do(bottest.Photo(hs.chat, hs.players[1], "photo-d"))
+	hs.command(hs.admin, "/vote")
+
+	actions := hs.press(hs.players[2], oldBtn)
+	if len(actions) != 1 || actions[0].Text != i18n.RU.T(messages.VoteStale) || !actions[0].Response.ShowAlert {
+		t.Errorf("expected stale alert, got %+v", actions)
+	}
+
+	// Кнопка из другого чата
+	foreign := *oldBtn
+	actions = hs.do(bottest.Callback(bottest.GroupChat(-200), hs.players[2], foreign))
+	if len(actions) != 1 || actions[0].Text != i18n.RU.T(messages.VoteStale) {
+		t.Errorf("expected stale alert for foreign chat, got %+v", actions)
+	}
+}
+
+func TestScenarioLanguage(t *testing.T) {
+	hs := newHarness(t, game.Settings{})
+
+	// Выбор кнопкой: сообщение с выбором заменяется подтверждением на новом языке
+	choose := lastSent(t, hs.command(hs.admin, "/language"))
+	if len(choose.Buttons()) != len(i18n.Supported()) {
+		t.Fatalf("expected a button per language, got %+v", choose.Buttons())
+	}
+	var enBtn *telebot.InlineButton
+	for _, btn := range choose.Buttons() {
+		if btn.Data == string(i18n.EN) {
+			enBtn = &btn
+		}
+	}
+	actions := hs.press(hs.admin, enBtn)
+	edits := bottest.Filter(actions, bottest.ActionEdit)
+	if len(edits) != 1 || edits[0].Text != i18n.EN.T(messages.LanguageChanged) {
+		t.Fatalf("expected confirmation in English, got %+v", actions)
+	}
+
+	// Дальше бот отвечает в чате по-английски, включая кнопки
+	msg := lastSent(t, hs.command(hs.admin, "/score"))
+	if msg.Text != i18n.EN.T(messages.GameNotStarted) {
+		t.Errorf("expected English reply, got %q", msg.Text)
+	}
+
+	// Команда с кодом языка меняет его сразу, неизвестный код - подсказка
+	if msg := lastSent(t, hs.command(hs.admin, "/language xx")); msg.Text != i18n.EN.T(messages.LanguageUnknown, "ru, en") {
+		t.Errorf("unknown language: got %q", msg.Text)
+	}
+	if msg := lastSent(t, hs.command(hs.admin, "/language ru")); msg.Text != i18n.RU.T(messages.LanguageChanged) {
+		t.Errorf("expected confirmation in Russian, got %q", msg.Text)
+	}
+
+	// В личке без выбора - язык Telegram пользователя
+	user := bottest.User(50, "dave")
+	user.LanguageCode = "en-GB"
+	private := bottest.PrivateChat(user)
+	msg = lastSent(t, hs.do(bottest.Text(private, user, "/start")))
+	if msg.Text != i18n.EN.T(messages.WelcomeSingleMessage) {
+		t.Errorf("expected English welcome in private chat, got %q", msg.Text)
+	}
+	if got := hs.h.Language.Locales.ForChat(private.ID); got != i18n.EN {
+		t.Errorf("private chat language must be remembered for timers, got %q", got)
+	}
+}
+
+func TestScenarioPacks(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"classic.json": `{"id": "classic", "title": {"ru": "Классика"}, "tasks": [{"id": "classic-cat", "text": {"ru": "Кот"}}]}`,
+		"blitz.json":   `{"id": "blitz", "title": {"ru": "Блиц"}, "tasks": [{"id": "blitz-sink", "text": {"ru": "Раковина", "en": "Sink"}, "blitz": true}]}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	packs, err := tasks.LoadPacks(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs := newHarnessWithTasks(t, game.Settings{}, packs)
+
+	list := lastSent(t, hs.command(hs.admin, "/packs"))
+	if list.Text != i18n.RU.T(messages.PacksChoose) || len(list.Buttons()) != 2 {
+		t.Fatalf("expected a button per pack, got %+v", list)
+	}
+	for _, btn := range list.Buttons() {
+		if !strings.HasPrefix(btn.Text, "✅") {
+			t.Errorf("new chat must have all packs on, got %q", btn.Text)
+		}
+	}
+
+	// Выключаем классику: кнопка меняется, раунды идут только из блица
+	actions := hs.press(hs.admin, packButton(list, "classic"))
+	edit := bottest.Filter(actions, bottest.ActionEdit)
+	if len(edit) != 1 || !strings.HasPrefix(packButton(edit[0], "classic").Text, "▫️") {
+		t.Fatalf("expected updated keyboard, got %+v", actions)
+	}
+
+	hs.command(hs.admin, "/startgame")
+	round := lastSent(t, hs.command(hs.admin, "/newround"))
+	if !strings.Contains(round.Text, i18n.RU.T(messages.BlitzTaskLabel)+" Раковина") {
+		t.Errorf("expected blitz task from the enabled pack, got %q", round.Text)
+	}
+
+	// Последний включённый пак выключить нельзя
+	actions = hs.press(hs.admin, packButton(edit[0], "blitz"))
+	if len(actions) != 1 || actions[0].Text != i18n.RU.T(messages.PacksLastEnabled) {
+		t.Errorf("expected refusal to disable the last pack, got %+v", actions)
+	}
+	if disabled := hs.h.Packs.Disabled(bottest.NewContext(hs.fb, bottest.Text(hs.chat, hs.admin, ""))); !disabled["classic"] || disabled["blitz"] {
+		t.Errorf("unexpected disabled packs: %v", disabled)
+	}
+}
+
+func packButton(a bottest.Action, pack string) *telebot.InlineButton {
+	for _, btn := range a.Buttons() {
+		if btn.Data == pack {
+			return &btn
+		}
+	}
+	return nil
+}
+
+func TestScenarioTaskAdmin(t *testing.T) {
+	hs := newHarness(t, game.Settings{})
+	private := bottest.PrivateChat(hs.owner)
+	owner := func(text string) bottest.Action {
+		return lastSent(t, hs.do(bottest.Text(private, hs.owner, text)))
+	}
+
+	// Посторонним команды каталога не видны ни в личке, ни в группе
+	stranger := hs.players[1]
+	if actions := hs.do(bottest.Text(bottest.PrivateChat(stranger), stranger, "/tasks")); len(actions) != 0 {
+		t.Errorf("stranger must be ignored, got %+v", actions)
+	}
+	if actions := hs.do(bottest.Text(hs.chat, hs.owner, "/disabletask test-1")); len(actions) != 0 {
+		t.Errorf("catalogue commands must not work in groups, got %+v", actions)
+	}
+
+	if msg := owner("/addtask party Фото <с> вечеринки"); msg.Text != i18n.RU.T(messages.TaskAdded, "party-1", "party") {
+		t.Errorf("unexpected add reply: %q", msg.Text)
+	}
+	if msg := owner("/edittask party-1 blitz on"); msg.Text != i18n.RU.T(messages.TaskUpdated, "party-1") {
+		t.Errorf("unexpected edit reply: %q", msg.Text)
+	}
+	if msg := owner("/edittask party-1 color red"); !strings.HasPrefix(msg.Text, "❓") {
+		t.Errorf("unknown field must be explained, got %q", msg.Text)
+	}
+	if msg := owner("/disabletask test-9"); msg.Text != i18n.RU.T(messages.TaskNotFound, "test-9") {
+		t.Errorf("unexpected reply for missing task: %q", msg.Text)
+	}
+	if msg := owner("/disabletask test-1"); msg.Text != i18n.RU.T(messages.TaskDisabled, "test-1") {
+		t.Errorf("unexpected disable reply: %q", msg.Text)
+	}
+
+	// Список - с выключенными заданиями и экранированным текстом
+	list := owner("/tasks").Text
+	for _, want := range []string{"<code>test-1</code> ⏸", "<code>party-1</code> ✏️[БЛИЦ] (0/0) Фото &lt;с&gt; вечеринки"} {
+		if !strings.Contains(list, want) {
+			t.Errorf("missing %q in:\n%s", want, list)
+		}
+	}
+
+	// Раунды сразу видят правки: выключенное задание не выпадает, новый пак в /packs
+	if packs := lastSent(t, hs.command(hs.admin, "/packs")); len(packs.Buttons()) != 2 {
+		t.Errorf("added pack must appear in /packs, got %+v", packs.Buttons())
+	}
+	hs.command(hs.admin, "/startgame")
+	for i := 0; i < 2; i++ {
+		round := lastSent(t, hs.command(hs.admin, "/newround"))
+		if strings.Contains(round.Text, "Сфотографируй кота") {
+			t.Fatalf("disabled task served: %q", round.Text)
+		}
+	}
+}
+
+func TestScenarioTaskHistory(t *testing.T) {
+	hs := newHarness(t, game.Settings{})
+
+	// Задание первой игры не повторяется в следующей, пока есть новые
+	hs.command(hs.admin, "/startgame")
+	first := lastSent(t, hs.command(hs.admin, "/newround")).Text
+	hs.command(hs.admin, "/endgame")
+
+	hs.command(hs.admin, "/startgame")
+	if second := lastSent(t, hs.command(hs.admin, "/newround")).Text; second == first {
+		t.Fatalf("task from the previous game repeated: %q", second)
+	}
+	hs.command(hs.admin, "/endgame")
+
+	if msg := lastSent(t, hs.command(hs.admin, "/resethistory")); msg.Text != i18n.RU.T(messages.HistoryReset, 2) {
+		t.Errorf("unexpected reset reply: %q", msg.Text)
+	}
+	if played, _ := hs.h.History.History.Played(hs.chat.ID); len(played) != 0 {
+		t.Errorf("history not reset: %v", played)
+	}
+}
+
+func TestScenarioSuggest(t *testing.T) {
+	hs := newHarness(t, game.Settings{})
+	member := hs.players[1]
+
+	if msg := lastSent(t, hs.command(member, "/suggest")); msg.Text != i18n.RU.T(messages.SuggestUsage) {
+		t.Errorf("expected usage hint, got %q", msg.Text)
+	}
+
+	prompt := lastSent(t, hs.command(member, "/suggest Фото <кота> соседа"))
+	if prompt.Text != i18n.RU.T(messages.SuggestPending, "@bob", "Фото &lt;кота&gt; соседа") || len(prompt.Buttons()) != 2 {
+		t.Fatalf("expected approval prompt, got %+v", prompt)
+	}
+	if msg := lastSent(t, hs.command(hs.players[2], "/suggest Фото <кота> соседа")); msg.Text != i18n.RU.T(messages.SuggestDuplicate) {
+		t.Errorf("duplicate must be refused, got %q", msg.Text)
+	}
+
+	// Решают только администраторы чата
+	approve := prompt.Button("approve_suggestion")
+	actions := hs.press(member, approve)
+	if len(actions) != 1 || actions[0].Kind != bottest.ActionRespond {
+		t.Fatalf("expected refusal for a member, got %+v", actions)
+	}
+
+	actions = hs.press(hs.admin, approve)
+	edit := bottest.Filter(actions, bottest.ActionEdit)
+	if len(edit) != 1 || edit[0].Text != i18n.RU.T(messages.SuggestApproved, "@bob", "Фото &lt;кота&gt; соседа") {
+		t.Fatalf("expected approval, got %+v", actions)
+	}
+	actions = hs.press(hs.admin, prompt.Button("reject_suggestion"))
+	if len(actions) != 1 || actions[0].Text != i18n.RU.T(messages.SuggestAlreadyDecided) {
+		t.Errorf("second decision must be refused, got %+v", actions)
+	}
+
+	// Кнопка чужого чата не работает
+	foreign := hs.do(bottest.Callback(bottest.GroupChat(-200), hs.admin, *approve))
+	if len(bottest.Filter(foreign, bottest.ActionEdit)) != 0 {
+		t.Errorf("foreign chat must not decide, got %+v", foreign)
+	}
+
+	// Одобренное задание выпадает вместе с паками: за три раунда выпадут все
+	hs.command(hs.admin, "/startgame")
+	found := false
+	for i := 0; i < 3; i++ {
+		if strings.Contains(lastSent(t, hs.command(hs.admin, "/newround")).Text, "Фото &lt;кота&gt; соседа") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("approved chat task never served")
+	}
+
+	// Отклонённое задание в раунды не попадает
+	rejected := lastSent(t, hs.command(member, "/suggest Фото чайника"))
+	hs.press(hs.admin, rejected.Button("reject_suggestion"))
+	if own := hs.h.Suggest.Tasks(bottest.NewContext(hs.fb, bottest.Text(hs.chat, member, ""))); len(own) != 1 {
+		t.Errorf("expected only the approved task, got %+v", own)
+	}
+}
+
+func TestScenarioSubmissions(t *testing.T) {
+	hs := newHarness(t, game.Settings{})
+	member := hs.players[1]
+	private := bottest.PrivateChat(hs.owner)
+	inPrivate := func(from *telebot.User, btn *telebot.InlineButton) []bottest.Action {
+		if btn == nil {
+			t.Fatal("button not found")
+		}
+		return hs.do(bottest.Callback(private, from, *btn))
+	}
+
+	if msg := lastSent(t, hs.command(member, "/submit")); msg.Text != i18n.RU.T(messages.SubmitUsage) {
+		t.Errorf("expected usage hint, got %q", msg.Text)
+	}
+
+	// Автор получает подтверждение в группе, владелец - карточку в личке
+	actions := hs.command(member, "/submit Фото <старого> гаджета")
+	var card bottest.Action
+	for _, a := range bottest.Filter(actions, bottest.ActionSend) {
+		switch a.ChatID {
+		case hs.chat.ID:
+			if a.Text != i18n.RU.T(messages.SubmitReceived) {
+				t.Errorf("unexpected reply to the author: %q", a.Text)
+			}
+		case hs.owner.ID:
+			card = a
+		}
+	}
+	if card.Text != i18n.RU.T(messages.SubmissionCard, 1, "@bob", "Фото &lt;старого&gt; гаджета") || len(card.Buttons()) != 3 {
+		t.Fatalf("expected review card for the owner, got %+v", actions)
+	}
+
+	// Кнопки и очередь - только для владельцев бота
+	if actions := inPrivate(member, card.Button("approve_submission")); len(actions) != 0 {
+		t.Errorf("stranger must be ignored, got %+v", actions)
+	}
+	if actions := hs.command(member, "/queue"); len(actions) != 0 {
+		t.Errorf("queue must be hidden from players, got %+v", actions)
+	}
+
+	hint := lastSent(t, inPrivate(hs.owner, card.Button("edit_submission")))
+	if !strings.Contains(hint.Text, "<code>/editsubmission 1 Фото &lt;старого&gt; гаджета</code>") {
+		t.Errorf("expected edit hint, got %q", hint.Text)
+	}
+	edited := lastSent(t, hs.do(bottest.Text(private, hs.owner, "/editsubmission 1 Фото самого старого гаджета")))
+	if edited.Text != i18n.RU.T(messages.SubmissionCard, 1, "@bob", "Фото самого старого гаджета") {
+		t.Fatalf("expected updated card, got %q", edited.Text)
+	}
+
+	actions = inPrivate(hs.owner, edited.Button("approve_submission"))
+	if edit := bottest.Filter(actions, bottest.ActionEdit); len(edit) != 1 || !strings.HasSuffix(edit[0].Text, i18n.RU.T(messages.SubmissionApproved, "community-1")) {
+		t.Fatalf("expected approval, got %+v", actions)
+	}
+	thanks := bottest.Filter(actions, bottest.ActionSend)
+	if len(thanks) != 1 || thanks[0].ChatID != member.ID {
+		t.Errorf("expected thanks to the author, got %+v", actions)
+	}
+	if actions := inPrivate(hs.owner, edited.Button("reject_submission")); len(actions) != 1 || actions[0].Response.Text != i18n.RU.T(messages.SubmissionAlreadyReviewed) {
+		t.Errorf("second decision must be refused, got %+v", actions)
+	}
+
+	// Задание в общем каталоге, в раунде указан автор
+	task, ok := hs.h.Tasks.Catalog.List().Task("community-1")
+	if !ok || task.TextFor(i18n.RU) != "Фото самого старого гаджета" || task.Author != "@bob" {
+		t.Fatalf("unexpected catalogue task: %+v, %v", task, ok)
+	}
+	hs.command(hs.admin, "/startgame")
+	credit := i18n.RU.T(messages.TaskAuthorCredit, "@bob")
+	found := false
+	for i := 0; i < 3; i++ {
+		if strings.Contains(lastSent(t, hs.command(hs.admin, "/newround")).Text, credit) {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("approved task never served with its author")
+	}
+
+	hs.command(member, "/submit Фото чайника")
+	queue := lastSent(t, hs.do(bottest.Text(private, hs.owner, "/queue")))
+	inPrivate(hs.owner, queue.Button("reject_submission"))
+	if msg := lastSent(t, hs.do(bottest.Text(private, hs.owner, "/queue"))); msg.Text != i18n.RU.T(messages.SubmissionQueueEmpty) {
+		t.Errorf("expected empty queue, got %q", msg.Text)
+	}
+}
+
+func TestScenarioBlitzRound(t *testing.T) {
+	blitz := &tasks.Pack{ID: "blitz", Title: map[i18n.Lang]string{i18n.RU: "Блиц"}, Tasks: []tasks.Task{
+		{ID: "blitz-sink", Text: map[i18n.Lang]string{i18n.RU: "Раковина"}, Blitz: true},
+	}}
+	hs := newHarnessWithTasks(t, game.Settings{SubmitDuration: time.Hour, BlitzDuration: 100 * time.Millisecond}, []*tasks.Pack{blitz})
+
+	hs.command(hs.admin, "/startgame")
+	round := lastSent(t, hs.command(hs.admin, "/newround"))
+	if !strings.Contains(round.Text, i18n.RU.T(messages.BlitzRules, "0:01", game.BlitzBonusPlaces)) {
+		t.Errorf("expected blitz rules instead of the regular deadline, got %q", round.Text)
+	}
+
+	// Фото принимаются молча: в чате ничего, подтверждение - в личку
+	for _, player := range hs.players[:2] {
+		actions := hs.do(bottest.Photo(hs.chat, player, "photo-"+player.Username))
+		for _, a := range bottest.Filter(actions, bottest.ActionSend) {
+			if a.ChatID != player.ID || a.Text != i18n.RU.T(messages.BlitsPhotoReceived) {
+				t.Errorf("blitz photo must be accepted silently, got %+v", a)
+			}
+		}
+	}
+
+	// По таймеру фото открываются сами, первый получает очко за скорость
+	session, _ := hs.gm.GetSession(hs.chat.ID)
+	deadline := time.Now().Add(2 * time.Second)
+	for session.State() != game.VoteState && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if session.State() != game.VoteState {
+		t.Fatalf("expected automatic reveal, got %s", session.State())
+	}
+	texts := bottest.Texts(hs.fb.Take())
+	if len(texts) < 3 || texts[0] != i18n.RU.T(messages.SubmitTimeIsUp) || texts[2] != i18n.RU.T(messages.BlitzSpeedBonus, "@admin") {
+		t.Errorf("expected reveal with speed bonus, got %q", texts)
+	}
+	if score := session.TotalScore(); len(score) != 1 || score[0].UserID != hs.admin.ID || score[0].Value != 1 {
+		t.Errorf("expected one speed point for the fastest, got %+v", score)
+	}
+}
+
+func TestScenarioResumeTimers(t *testing.T) {
+	blitz := &tasks.Pack{ID: "blitz", Title: map[i18n.Lang]string{i18n.RU: "Блиц"}, Tasks: []tasks.Task{
+		{ID: "blitz-sink", Text: map[i18n.Lang]string{i18n.RU: "Раковина"}, Blitz: true},
+	}}
+	hs := newHarnessWithTasks(t, game.Settings{SubmitDuration: time.Hour, BlitzDuration: time.Hour, VoteDuration: time.Hour}, []*tasks.Pack{blitz})
+
+	hs.command(hs.admin, "/startgame")
+	hs.command(hs.admin, "/newround")
+	hs.do(bottest.Photo(hs.chat, hs.players[0], "photo-"+hs.players[0].Username))
+
+	// Рестарт: таймеры потеряны, а срок блиц-раунда прошёл, пока бот не работал
+	session, _ := hs.gm.GetSession(hs.chat.ID)
+	hs.gm.Timers.StopAll()
+	session.Deadline = time.Now().Add(-time.Second)
+	hs.fb.Take()
+
+	hs.h.ResumeTimers()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for session.State() != game.VoteState && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if session.State() != game.VoteState {
+		t.Fatalf("expected stalled blitz round to open voting after restart, got %s", session.State())
+	}
+
+	// Голосование идёт по своему таймеру, который тоже переживает рестарт
+	for !hs.gm.Timers.Active(hs.chat.ID) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	hs.gm.Timers.StopAll()
+	if left := session.TimeLeft(); left <= 0 || left > time.Hour {
+		t.Fatalf("expected vote deadline to be saved, %v left", left)
+	}
+
+	hs.h.ResumeTimers()
+	if !hs.gm.Timers.Active(hs.chat.ID) {
+		t.Error("expected vote timer to be re-armed after restart")
+	}
+	hs.gm.Timers.StopAll()
+}
+
+func TestScenarioFinishVoteStopsCountdown(t *testing.T) {
+	hs := newHarness(t, game.Settings{VoteDuration: time.Hour})
+
+	hs.command(hs.admin, "/startgame")
+	hs.command(hs.admin, "/newround")
+	hs.do(bottest.Photo(hs.chat, hs.players[0], "photo-a"))
+	hs.do(bottest.Photo(hs.chat, hs.players[1], "photo-b"))
+
+	countdown := lastSent(t, hs.command(hs.admin, "/vote"))
+	if countdown.Text != i18n.RU.T(messages.VoteCountdown, "60:00") {
+		t.Fatalf("expected vote countdown, got %q", countdown.Text)
+	}
+
+	// Ручное завершение не должно оставлять в чате застывший отсчёт
+	hs.command(hs.admin, "/finishvote")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, a := range bottest.Filter(hs.fb.Actions(), bottest.ActionEdit) {
+			if a.MessageID == countdown.MessageID && a.Text == i18n.RU.T(messages.VoteCountdownStopped) {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("expected countdown %d to be closed after /finishvote", countdown.MessageID)
+}
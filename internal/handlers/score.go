@@ -5,6 +5,7 @@ import (
 	"github.com/kiselevos/memento_game_bot/internal/bot"
 	"github.com/kiselevos/memento_game_bot/internal/botinterface"
 	"github.com/kiselevos/memento_game_bot/internal/game"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
 
 	"gopkg.in/telebot.v3"
 )
@@ -12,16 +13,18 @@ import (
 type ScoreHandlers struct {
 	Bot         botinterface.BotInterface
 	GameManager *game.GameManager
+	Locales     *i18n.Locales
 
 	RoundHandlers *RoundHandlers
 	GameHandlers  *GameHandlers
 }
 
-func NewScoreHandlers(bot botinterface.BotInterface, gm *game.GameManager) *ScoreHandlers {
+func NewScoreHandlers(bot botinterface.BotInterface, gm *game.GameManager, loc *i18n.Locales) *ScoreHandlers {
 
 	sh := &ScoreHandlers{
 		Bot:         bot,
 		GameManager: gm,
+		Locales:     loc,
 	}
 
 	return sh
@@ -39,17 +42,18 @@ func (sh *ScoreHandlers) Register() {
 // HandleScore - показать общий счет данной сессии
 func (sh *ScoreHandlers) HandleScore(c telebot.Context) error {
 	chatID := c.Chat().ID
+	tr := sh.Locales.For(c)
 
 	markup := &telebot.ReplyMarkup{}
-	markup.InlineKeyboard = [][]telebot.InlineButton{{sh.GameHandlers.StartGameBtn}}
+	markup.InlineKeyboard = [][]telebot.InlineButton{{sh.GameHandlers.startGameBtn(tr)}}
 
 	session, exist := sh.GameManager.GetSession(chatID)
 	if !exist {
-		return c.Send(messages.GameNotStarted, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+		return c.Send(tr.T(messages.GameNotStarted), &telebot.SendOptions{ParseMode: telebot.ModeHTML})
 	}
 
-	markup.InlineKeyboard = [][]telebot.InlineButton{{sh.RoundHandlers.StartRoundBtn}}
+	markup.InlineKeyboard = [][]telebot.InlineButton{{sh.RoundHandlers.startRoundBtn(tr)}}
 
-	result := bot.RenderScore(bot.GameScore, session.TotalScore())
+	result := bot.RenderScore(tr, messages.GameScore, session.TotalScore())
 	return c.Send(result, &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup)
 }
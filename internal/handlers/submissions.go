@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"errors"
+	"html"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	messages "github.com/kiselevos/memento_game_bot/assets"
+	"github.com/kiselevos/memento_game_bot/internal/bot/middleware"
+	"github.com/kiselevos/memento_game_bot/internal/botinterface"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/internal/tasks"
+
+	"gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+)
+
+// SubmissionHandlers - заявки игроков в общий каталог. Игрок присылает задание
+// командой /submit, владельцы бота (AdminsID) разбирают очередь в личке.
+type SubmissionHandlers struct {
+	Bot         botinterface.BotInterface
+	Submissions tasks.Submissions
+	Catalog     *tasks.Catalog
+	AdminsID    []int64
+	Locales     *i18n.Locales
+
+	ApproveBtn telebot.InlineButton
+	EditBtn    telebot.InlineButton
+	RejectBtn  telebot.InlineButton
+}
+
+func NewSubmissionHandlers(bot botinterface.BotInterface, subs tasks.Submissions, catalog *tasks.Catalog, adminsID []int64, loc *i18n.Locales) *SubmissionHandlers {
+	h := &SubmissionHandlers{
+		Bot:         bot,
+		Submissions: subs,
+		Catalog:     catalog,
+		AdminsID:    adminsID,
+		Locales:     loc,
+	}
+
+	h.ApproveBtn = telebot.InlineButton{
+		Unique: "approve_submission",
+	}
+	h.EditBtn = telebot.InlineButton{
+		Unique: "edit_submission",
+	}
+	h.RejectBtn = telebot.InlineButton{
+		Unique: "reject_submission",
+	}
+
+	return h
+}
+
+func (sh *SubmissionHandlers) Register() {
+	onlyOwners := middleware.BotAdmins(sh.AdminsID)
+
+	sh.Bot.Handle("/submit", sh.HandleSubmit)
+	sh.Bot.Handle("/queue", sh.HandleQueue, onlyOwners)
+	sh.Bot.Handle("/editsubmission", sh.HandleEditSubmission, onlyOwners)
+	sh.Bot.Handle(&sh.ApproveBtn, sh.HandleApprove, onlyOwners)
+	sh.Bot.Handle(&sh.EditBtn, sh.HandleEditBtn, onlyOwners)
+	sh.Bot.Handle(&sh.RejectBtn, sh.HandleReject, onlyOwners)
+}
+
+// HandleSubmit - /submit <текст>: заявка уходит в очередь и владельцам бота
+func (sh *SubmissionHandlers) HandleSubmit(c telebot.Context) error {
+	tr := sh.Locales.For(c)
+
+	text := strings.TrimSpace(c.Message().Payload)
+	if text == "" {
+		return c.Send(tr.T(messages.SubmitUsage))
+	}
+	if utf8.RuneCountInString(text) > MaxSuggestionLen {
+		return c.Send(tr.T(messages.SuggestTooLong, MaxSuggestionLen))
+	}
+
+	sub, err := sh.Submissions.Create(&models.TaskSubmission{
+		Text:       text,
+		AuthorID:   c.Sender().ID,
+		AuthorName: displayName(c.Sender()),
+		ChatID:     c.Chat().ID,
+		Status:     models.SubmissionPending,
+	})
+	if err != nil {
+		logging.From(c).Error("Не удалось сохранить заявку на задание", "err", err)
+		return c.Send(tr.T(messages.ErrorMessagesForUser))
+	}
+
+	logging.From(c).Info("Новая заявка на задание", "submission", sub.ID)
+
+	for _, ownerID := range sh.AdminsID {
+		owner := sh.Locales.ForChat(ownerID)
+		if _, err := sh.Bot.Send(&telebot.User{ID: ownerID}, submissionCard(owner, sub), &telebot.SendOptions{ParseMode: telebot.ModeHTML}, sh.reviewMarkup(owner, sub)); err != nil {
+			logging.From(c).Error("Не удалось отправить заявку владельцу бота", "admin_id", ownerID, "err", err)
+		}
+	}
+
+	return c.Send(tr.T(messages.SubmitReceived))
+}
+
+// HandleQueue - /queue: все ждущие заявки, каждая со своими кнопками
+func (sh *SubmissionHandlers) HandleQueue(c telebot.Context) error {
+	tr := sh.Locales.For(c)
+
+	pending, err := sh.Submissions.Pending()
+	if err != nil {
+		logging.From(c).Error("Не удалось получить очередь заявок", "err", err)
+		return c.Send(tr.T(messages.ErrorMessagesForUser))
+	}
+	if len(pending) == 0 {
+		return c.Send(tr.T(messages.SubmissionQueueEmpty))
+	}
+
+	for i := range pending {
+		if err := c.Send(submissionCard(tr, &pending[i]), &telebot.SendOptions{ParseMode: telebot.ModeHTML}, sh.reviewMarkup(tr, &pending[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleEditBtn - подсказка с командой правки, текст заявки уже подставлен
+func (sh *SubmissionHandlers) HandleEditBtn(c telebot.Context) error {
+	tr := sh.Locales.For(c)
+
+	sub, ok := sh.pending(c, tr)
+	if !ok {
+		return nil
+	}
+
+	_ = c.Respond()
+	return c.Send(tr.T(messages.SubmissionEditHint, sub.ID, html.EscapeString(sub.Text)), &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+}
+
+// HandleEditSubmission - /editsubmission <id> <текст>: правка до одобрения
+func (sh *SubmissionHandlers) HandleEditSubmission(c telebot.Context) error {
+	tr := sh.Locales.For(c)
+
+	rawID, text, _ := strings.Cut(strings.TrimSpace(c.Message().Payload), " ")
+	text = strings.TrimSpace(text)
+	id, err := strconv.ParseUint(rawID, 10, 0)
+	if err != nil || text == "" {
+		return c.Send(tr.T(messages.SubmissionEditUsage))
+	}
+
+	ok, err := sh.Submissions.SetText(uint(id), text)
+	if err != nil {
+		logging.From(c).Error("Не удалось изменить заявку", "submission", id, "err", err)
+		return c.Send(tr.T(messages.ErrorMessagesForUser))
+	}
+	if !ok {
+		return c.Send(tr.T(messages.SubmissionAlreadyReviewed))
+	}
+
+	sub, err := sh.Submissions.Get(uint(id))
+	if err != nil {
+		logging.From(c).Error("Не удалось получить заявку", "submission", id, "err", err)
+		return c.Send(tr.T(messages.ErrorMessagesForUser))
+	}
+
+	logging.From(c).Info("Заявка изменена", "submission", id)
+	return c.Send(submissionCard(tr, sub), &telebot.SendOptions{ParseMode: telebot.ModeHTML}, sh.reviewMarkup(tr, sub))
+}
+
+// HandleApprove - задание попадает в пак community с автором, автору приходит спасибо
+func (sh *SubmissionHandlers) HandleApprove(c telebot.Context) error {
+	tr := sh.Locales.For(c)
+
+	sub, ok := sh.pending(c, tr)
+	if !ok {
+		return nil
+	}
+
+	task, err := sh.Catalog.Add(tasks.CommunityPack, sub.Text, sub.AuthorName)
+	if errors.Is(err, tasks.ErrDuplicateText) {
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.TaskDuplicate), ShowAlert: true})
+	}
+	if err != nil {
+		logging.From(c).Error("Не удалось добавить задание из заявки", "submission", sub.ID, "err", err)
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.ErrorMessagesForUser)})
+	}
+
+	decided, err := sh.Submissions.Decide(sub.ID, models.SubmissionApproved, c.Sender().ID, task.ID)
+	if err != nil || !decided {
+		// Задание уже в каталоге: при необходимости его выключат через /disabletask
+		logging.From(c).Error("Не удалось закрыть заявку после одобрения", "submission", sub.ID, "task", task.ID, "err", err)
+	}
+
+	logging.From(c).Info("Заявка одобрена", "submission", sub.ID, "task", task.ID)
+
+	author := sh.Locales.ForChat(sub.AuthorID)
+	if _, err := sh.Bot.Send(&telebot.User{ID: sub.AuthorID}, author.T(messages.SubmissionAuthorThanks, html.EscapeString(sub.Text)), &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+		// Автор мог ни разу не писать боту в личку
+		logging.From(c).Warn("Не удалось поблагодарить автора заявки", "author_id", sub.AuthorID, "err", err)
+	}
+
+	_ = c.Respond()
+	return c.Edit(submissionCard(tr, sub)+"\n\n"+tr.T(messages.SubmissionApproved, task.ID), &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+}
+
+// HandleReject - заявка закрывается без задания
+func (sh *SubmissionHandlers) HandleReject(c telebot.Context) error {
+	tr := sh.Locales.For(c)
+
+	sub, ok := sh.pending(c, tr)
+	if !ok {
+		return nil
+	}
+
+	decided, err := sh.Submissions.Decide(sub.ID, models.SubmissionRejected, c.Sender().ID, "")
+	if err != nil {
+		logging.From(c).Error("Не удалось отклонить заявку", "submission", sub.ID, "err", err)
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.ErrorMessagesForUser)})
+	}
+	if !decided {
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.SubmissionAlreadyReviewed)})
+	}
+
+	logging.From(c).Info("Заявка отклонена", "submission", sub.ID)
+	_ = c.Respond()
+	return c.Edit(submissionCard(tr, sub)+"\n\n"+tr.T(messages.SubmissionRejected), &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+}
+
+// pending - заявка из кнопки, если её ещё не рассмотрели. Иначе отвечает сама.
+func (sh *SubmissionHandlers) pending(c telebot.Context, tr i18n.Lang) (*models.TaskSubmission, bool) {
+	id, err := strconv.ParseUint(c.Data(), 10, 0)
+	if err != nil {
+		_ = c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.ErrorMessagesForUser)})
+		return nil, false
+	}
+
+	sub, err := sh.Submissions.Get(uint(id))
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logging.From(c).Error("Не удалось получить заявку", "submission", id, "err", err)
+		}
+		_ = c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.ErrorMessagesForUser)})
+		return nil, false
+	}
+	if sub.Status != models.SubmissionPending {
+		_ = c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.SubmissionAlreadyReviewed)})
+		return nil, false
+	}
+	return sub, true
+}
+
+func (sh *SubmissionHandlers) reviewMarkup(tr i18n.Lang, sub *models.TaskSubmission) *telebot.ReplyMarkup {
+	id := strconv.FormatUint(uint64(sub.ID), 10)
+	row := make([]telebot.InlineButton, 0, 3)
+	for _, b := range []struct {
+		btn  telebot.InlineButton
+		text messages.Key
+	}{
+		{sh.ApproveBtn, messages.BtnApproveSubmission},
+		{sh.EditBtn, messages.BtnEditSubmission},
+		{sh.RejectBtn, messages.BtnRejectSubmission},
+	} {
+		btn := localized(b.btn, tr.T(b.text))
+		btn.Data = id
+		row = append(row, btn)
+	}
+	return &telebot.ReplyMarkup{InlineKeyboard: [][]telebot.InlineButton{row}}
+}
+
+func submissionCard(tr i18n.Lang, sub *models.TaskSubmission) string {
+	return tr.T(messages.SubmissionCard, sub.ID, html.EscapeString(sub.AuthorName), html.EscapeString(sub.Text))
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"html"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	messages "github.com/kiselevos/memento_game_bot/assets"
+	"github.com/kiselevos/memento_game_bot/internal/bot/middleware"
+	"github.com/kiselevos/memento_game_bot/internal/botinterface"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/internal/tasks"
+
+	"gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+)
+
+// MaxSuggestionLen - предел длины предложенного задания в символах
+const MaxSuggestionLen = 300
+
+// SuggestHandlers - задания, которые участники придумывают для своего чата
+type SuggestHandlers struct {
+	Bot       botinterface.BotInterface
+	ChatTasks tasks.ChatTasks
+	Locales   *i18n.Locales
+
+	ApproveBtn telebot.InlineButton
+	RejectBtn  telebot.InlineButton
+}
+
+func NewSuggestHandlers(bot botinterface.BotInterface, chatTasks tasks.ChatTasks, loc *i18n.Locales) *SuggestHandlers {
+	h := &SuggestHandlers{
+		Bot:       bot,
+		ChatTasks: chatTasks,
+		Locales:   loc,
+	}
+
+	h.ApproveBtn = telebot.InlineButton{
+		Unique: "approve_suggestion",
+	}
+	h.RejectBtn = telebot.InlineButton{
+		Unique: "reject_suggestion",
+	}
+
+	return h
+}
+
+func (sh *SuggestHandlers) Register() {
+	sh.Bot.Handle("/suggest", sh.HandleSuggest)
+	sh.Bot.Handle(&sh.ApproveBtn, sh.HandleApprove, middleware.OnlyAdmins(sh.Bot, sh.Locales))
+	sh.Bot.Handle(&sh.RejectBtn, sh.HandleReject, middleware.OnlyAdmins(sh.Bot, sh.Locales))
+}
+
+// HandleSuggest - /suggest <текст>: любой участник предлагает задание, админы чата решают
+func (sh *SuggestHandlers) HandleSuggest(c telebot.Context) error {
+	tr := sh.Locales.For(c)
+
+	text := strings.TrimSpace(c.Message().Payload)
+	if text == "" {
+		return c.Send(tr.T(messages.SuggestUsage))
+	}
+	if utf8.RuneCountInString(text) > MaxSuggestionLen {
+		return c.Send(tr.T(messages.SuggestTooLong, MaxSuggestionLen))
+	}
+
+	exists, err := sh.ChatTasks.HasText(c.Chat().ID, text)
+	if err != nil {
+		logging.From(c).Error("Не удалось проверить задания чата", "err", err)
+		return c.Send(tr.T(messages.ErrorMessagesForUser))
+	}
+	if exists {
+		return c.Send(tr.T(messages.SuggestDuplicate))
+	}
+
+	task, err := sh.ChatTasks.Create(&models.ChatTask{
+		ChatID:     c.Chat().ID,
+		Text:       text,
+		AuthorID:   c.Sender().ID,
+		AuthorName: displayName(c.Sender()),
+		Status:     models.ChatTaskPending,
+	})
+	if err != nil {
+		logging.From(c).Error("Не удалось сохранить предложенное задание", "err", err)
+		return c.Send(tr.T(messages.ErrorMessagesForUser))
+	}
+
+	logging.From(c).Info("Предложено задание для чата", "suggestion", task.ID)
+
+	id := strconv.FormatUint(uint64(task.ID), 10)
+	approve := localized(sh.ApproveBtn, tr.T(messages.BtnApproveSuggestion))
+	approve.Data = id
+	reject := localized(sh.RejectBtn, tr.T(messages.BtnRejectSuggestion))
+	reject.Data = id
+	markup := &telebot.ReplyMarkup{InlineKeyboard: [][]telebot.InlineButton{{approve, reject}}}
+
+	return c.Send(suggestionText(tr, messages.SuggestPending, task), &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup)
+}
+
+// HandleApprove - задание попадает в раунды этого чата
+func (sh *SuggestHandlers) HandleApprove(c telebot.Context) error {
+	return sh.decide(c, models.ChatTaskApproved, messages.SuggestApproved)
+}
+
+// HandleReject - задание отклонено
+func (sh *SuggestHandlers) HandleReject(c telebot.Context) error {
+	return sh.decide(c, models.ChatTaskRejected, messages.SuggestRejected)
+}
+
+func (sh *SuggestHandlers) decide(c telebot.Context, status string, done messages.Key) error {
+	tr := sh.Locales.For(c)
+
+	id, err := strconv.ParseUint(c.Data(), 10, 0)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.ErrorMessagesForUser)})
+	}
+
+	task, err := sh.ChatTasks.Get(uint(id))
+	// Кнопку из другого чата не принимаем: админ там - не админ здесь
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && task.ChatID != c.Chat().ID) {
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.ErrorMessagesForUser)})
+	}
+	if err != nil {
+		logging.From(c).Error("Не удалось получить предложенное задание", "suggestion", id, "err", err)
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.ErrorMessagesForUser)})
+	}
+
+	ok, err := sh.ChatTasks.Decide(task.ID, status, c.Sender().ID)
+	if err != nil {
+		logging.From(c).Error("Не удалось сохранить решение по заданию", "suggestion", id, "err", err)
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.ErrorMessagesForUser)})
+	}
+	if !ok {
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.SuggestAlreadyDecided)})
+	}
+
+	logging.From(c).Info("Решение по заданию чата", "suggestion", id, "status", status)
+	_ = c.Respond()
+	return c.Edit(suggestionText(tr, done, task), &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+}
+
+// Tasks - одобренные задания чата для выбора раунда. При ошибке раунд идёт без них.
+func (sh *SuggestHandlers) Tasks(c telebot.Context) []tasks.Task {
+	approved, err := sh.ChatTasks.Approved(c.Chat().ID)
+	if err != nil {
+		logging.From(c).Error("Не удалось получить задания чата", "err", err)
+		return nil
+	}
+
+	res := make([]tasks.Task, 0, len(approved))
+	for _, task := range approved {
+		res = append(res, tasks.FromChatTask(task))
+	}
+	return res
+}
+
+func suggestionText(tr i18n.Lang, key messages.Key, task *models.ChatTask) string {
+	return tr.T(key, html.EscapeString(task.AuthorName), html.EscapeString(task.Text))
+}
+
+// displayName - как бот называет участника: @username или имя
+func displayName(u *telebot.User) string {
+	if u.Username != "" {
+		return "@" + u.Username
+	}
+	return u.FirstName
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+
+	messages "github.com/kiselevos/memento_game_bot/assets"
+	"github.com/kiselevos/memento_game_bot/internal/bot/middleware"
+	"github.com/kiselevos/memento_game_bot/internal/botinterface"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/internal/tasks"
+
+	"gopkg.in/telebot.v3"
+)
+
+// maxMessageLen - лимит Telegram на текст сообщения с запасом под разметку
+const maxMessageLen = 4000
+
+// TaskAdminHandlers - каталог заданий для владельцев бота, только в личке
+type TaskAdminHandlers struct {
+	Bot      botinterface.BotInterface
+	Catalog  *tasks.Catalog
+	AdminsID []int64
+	Locales  *i18n.Locales
+}
+
+func NewTaskAdminHandlers(bot botinterface.BotInterface, catalog *tasks.Catalog, adminsID []int64, loc *i18n.Locales) *TaskAdminHandlers {
+	return &TaskAdminHandlers{
+		Bot:      bot,
+		Catalog:  catalog,
+		AdminsID: adminsID,
+		Locales:  loc,
+	}
+}
+
+func (th *TaskAdminHandlers) Register() {
+	onlyOwners := middleware.BotAdmins(th.AdminsID)
+	th.Bot.Handle("/tasks", th.HandleTasks, onlyOwners)
+	th.Bot.Handle("/addtask", th.HandleAddTask, onlyOwners)
+	th.Bot.Handle("/edittask", th.HandleEditTask, onlyOwners)
+	th.Bot.Handle("/disabletask", th.HandleDisableTask, onlyOwners)
+	th.Bot.Handle("/enabletask", th.HandleEnableTask, onlyOwners)
+}
+
+// HandleTasks - /tasks [пак]: задания каталога вместе с выключенными и архивными
+func (th *TaskAdminHandlers) HandleTasks(c telebot.Context) error {
+	tr := th.Locales.For(c)
+
+	entries, err := th.Catalog.Entries(strings.ToLower(strings.TrimSpace(c.Message().Payload)))
+	if err != nil {
+		logging.From(c).Error("Не удалось получить каталог заданий", "err", err)
+		return c.Send(tr.T(messages.ErrorMessagesForUser))
+	}
+	if len(entries) == 0 {
+		return c.Send(tr.T(messages.TaskListEmpty)+"\n\n"+th.help(tr), telebot.ModeHTML)
+	}
+
+	lines := make([]string, 0, len(entries)+1)
+	lines = append(lines, tr.T(messages.TaskListHeader, len(entries)))
+	for _, e := range entries {
+		lines = append(lines, entryLine(tr, e))
+	}
+
+	for _, chunk := range splitMessage(lines, maxMessageLen) {
+		if err := c.Send(chunk, telebot.ModeHTML); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleAddTask - /addtask <пак> <текст>
+func (th *TaskAdminHandlers) HandleAddTask(c telebot.Context) error {
+	tr := th.Locales.For(c)
+
+	pack, text, _ := strings.Cut(strings.TrimSpace(c.Message().Payload), " ")
+	if strings.TrimSpace(text) == "" {
+		return c.Send(th.help(tr), telebot.ModeHTML)
+	}
+
+	task, err := th.Catalog.Add(pack, text, "")
+	if err != nil {
+		return th.fail(c, tr, "", err)
+	}
+
+	logging.From(c).Info("Задание добавлено в каталог", "task", task.ID, "pack", task.Pack)
+	return c.Send(tr.T(messages.TaskAdded, task.ID, html.EscapeString(task.Pack)), telebot.ModeHTML)
+}
+
+// HandleEditTask - /edittask <id> <поле> <значение>
+func (th *TaskAdminHandlers) HandleEditTask(c telebot.Context) error {
+	tr := th.Locales.For(c)
+
+	args := strings.SplitN(strings.TrimSpace(c.Message().Payload), " ", 3)
+	if len(args) < 2 {
+		return c.Send(th.help(tr), telebot.ModeHTML)
+	}
+	value := ""
+	if len(args) == 3 {
+		value = args[2]
+	}
+
+	task, err := th.Catalog.Edit(args[0], args[1], value)
+	if err != nil {
+		return th.fail(c, tr, args[0], err)
+	}
+
+	logging.From(c).Info("Задание изменено", "task", task.ID, "field", args[1])
+	return c.Send(tr.T(messages.TaskUpdated, task.ID), telebot.ModeHTML)
+}
+
+// HandleDisableTask - /disabletask <id>
+func (th *TaskAdminHandlers) HandleDisableTask(c telebot.Context) error {
+	return th.setDisabled(c, true, messages.TaskDisabled)
+}
+
+// HandleEnableTask - /enabletask <id>
+func (th *TaskAdminHandlers) HandleEnableTask(c telebot.Context) error {
+	return th.setDisabled(c, false, messages.TaskEnabled)
+}
+
+func (th *TaskAdminHandlers) setDisabled(c telebot.Context, disabled bool, done messages.Key) error {
+	tr := th.Locales.For(c)
+
+	id := strings.TrimSpace(c.Message().Payload)
+	if id == "" {
+		return c.Send(th.help(tr), telebot.ModeHTML)
+	}
+
+	if err := th.Catalog.SetDisabled(id, disabled); err != nil {
+		return th.fail(c, tr, id, err)
+	}
+
+	logging.From(c).Info("Задание переключено", "task", id, "disabled", disabled)
+	return c.Send(tr.T(done, html.EscapeString(id)), telebot.ModeHTML)
+}
+
+// fail - ответ на ошибку каталога: ошибки ввода объясняются, остальные логируются
+func (th *TaskAdminHandlers) fail(c telebot.Context, tr i18n.Lang, id string, err error) error {
+	switch {
+	case errors.Is(err, tasks.ErrTaskNotFound):
+		return c.Send(tr.T(messages.TaskNotFound, html.EscapeString(id)), telebot.ModeHTML)
+	case errors.Is(err, tasks.ErrUnknownField):
+		return c.Send(tr.T(messages.TaskBadField, strings.Join(tasks.EditableFields(), ", ")))
+	case errors.Is(err, tasks.ErrBadValue):
+		return c.Send(tr.T(messages.TaskBadValue))
+	case errors.Is(err, tasks.ErrDuplicateText):
+		return c.Send(tr.T(messages.TaskDuplicate))
+	}
+	logging.From(c).Error("Не удалось изменить каталог заданий", "task", id, "err", err)
+	return c.Send(tr.T(messages.ErrorMessagesForUser))
+}
+
+func (th *TaskAdminHandlers) help(tr i18n.Lang) string {
+	return tr.T(messages.TaskAdminHelp, strings.Join(tasks.EditableFields(), ", "))
+}
+
+// entryLine - строка списка: ID, пометки, счётчики и текст на языке админа
+func entryLine(tr i18n.Lang, e tasks.Entry) string {
+	var marks string
+	if e.Disabled {
+		marks += "⏸"
+	}
+	if e.Archived {
+		marks += "🗄"
+	}
+	if e.Source == models.TaskSourceAdmin {
+		marks += "✏️"
+	}
+	if e.Blitz {
+		marks += tr.T(messages.BlitzTaskLabel)
+	}
+	if marks != "" {
+		marks = " " + marks
+	}
+	return fmt.Sprintf("<code>%s</code>%s (%d/%d) %s",
+		html.EscapeString(e.ID), marks, e.Uses, e.Skips, html.EscapeString(e.TextFor(tr)))
+}
+
+// splitMessage - склеивает строки в сообщения не длиннее limit
+func splitMessage(lines []string, limit int) []string {
+	var res []string
+	var cur strings.Builder
+	for _, line := range lines {
+		if cur.Len() > 0 && cur.Len()+len(line)+1 > limit {
+			res = append(res, cur.String())
+			cur.Reset()
+		}
+		if cur.Len() > 0 {
+			cur.WriteByte('\n')
+		}
+		cur.WriteString(line)
+	}
+	if cur.Len() > 0 {
+		res = append(res, cur.String())
+	}
+	return res
+}
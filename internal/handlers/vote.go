@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"html"
+	"strconv"
+	"strings"
 	"time"
 
 	messages "github.com/kiselevos/memento_game_bot/assets"
@@ -10,6 +13,8 @@ import (
 	"github.com/kiselevos/memento_game_bot/internal/bot/middleware"
 	"github.com/kiselevos/memento_game_bot/internal/botinterface"
 	"github.com/kiselevos/memento_game_bot/internal/game"
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
 
 	"gopkg.in/telebot.v3"
 )
@@ -17,39 +22,57 @@ import (
 type VoteHandlers struct {
 	Bot         botinterface.BotInterface
 	GameManager *game.GameManager
+	Locales     *i18n.Locales
 
 	RoundHandlers *RoundHandlers
 
+	// RevealDelay - пауза между объявлением голосования и показом фото
+	RevealDelay time.Duration
+
 	StartVoteBtn  telebot.InlineButton
 	FinishVoteBtn telebot.InlineButton
+	VoteBtn       telebot.InlineButton
 }
 
-func NewVoteHandlers(bot botinterface.BotInterface, gm *game.GameManager) *VoteHandlers {
+func NewVoteHandlers(bot botinterface.BotInterface, gm *game.GameManager, loc *i18n.Locales) *VoteHandlers {
 
 	h := &VoteHandlers{
 		Bot:         bot,
 		GameManager: gm,
+		Locales:     loc,
+		RevealDelay: time.Second,
 	}
 
 	h.StartVoteBtn = telebot.InlineButton{
 		Unique: "start_vote",
-		Text:   "Начать голосование",
 	}
 	h.FinishVoteBtn = telebot.InlineButton{
 		Unique: "finish_vote",
-		Text:   "Завершить голосование",
+	}
+	h.VoteBtn = telebot.InlineButton{
+		Unique: "vote",
 	}
 
 	return h
 }
 
+func (vh *VoteHandlers) startVoteBtn(tr i18n.Lang) telebot.InlineButton {
+	return localized(vh.StartVoteBtn, tr.T(messages.BtnStartVote))
+}
+
+func (vh *VoteHandlers) finishVoteBtn(tr i18n.Lang) telebot.InlineButton {
+	return localized(vh.FinishVoteBtn, tr.T(messages.BtnFinishVote))
+}
+
 func (vh *VoteHandlers) Register() {
 
-	vh.Bot.Handle("/vote", vh.StartVote, middleware.OnlyAdmins(vh.Bot))
-	vh.Bot.Handle("/finishvote", vh.HandleFinishVote, middleware.OnlyAdmins(vh.Bot))
+	vh.Bot.Handle("/vote", vh.StartVote, middleware.OnlyAdmins(vh.Bot, vh.Locales))
+	vh.Bot.Handle("/finishvote", vh.HandleFinishVote, middleware.OnlyAdmins(vh.Bot, vh.Locales))
+
+	vh.Bot.Handle(&vh.StartVoteBtn, vh.StartVote, middleware.OnlyAdmins(vh.Bot, vh.Locales))
+	vh.Bot.Handle(&vh.FinishVoteBtn, vh.HandleFinishVote, middleware.OnlyAdmins(vh.Bot, vh.Locales))
 
-	vh.Bot.Handle(&vh.StartVoteBtn, vh.StartVote, middleware.OnlyAdmins(vh.Bot))
-	vh.Bot.Handle(&vh.FinishVoteBtn, vh.HandleFinishVote, middleware.OnlyAdmins(vh.Bot))
+	vh.Bot.Handle(&vh.VoteBtn, vh.HandleVote)
 
 	// для прода
 	// h.Bot.Handle("/vote", GroupOnly(h.StartVote))
@@ -59,16 +82,17 @@ func (vh *VoteHandlers) Register() {
 func (vh *VoteHandlers) StartVote(c telebot.Context) error {
 
 	chat := c.Chat()
+	tr := vh.Locales.For(c)
 
 	session, exist := vh.GameManager.GetSession(chat.ID)
-	if !exist || session.FSM.Current() != game.RoundStartState {
-		log.Printf("[INFO] Попытка запуска голосования без раунда %d", chat.ID)
-		return c.Send("На данный момент нет запущенного раунда")
+	if !exist || session.State() != game.RoundStartState {
+		logging.From(c).Info("Попытка запуска голосования без раунда")
+		return c.Send(tr.T(messages.NoActiveRound))
 	}
 
 	// Зашита от нулевого голосования когда никто не скинул фото)
-	if len(session.UsersPhoto) == 0 {
-		return c.Send(messages.NotEnoughPhoto, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	if session.PhotosCount() == 0 {
+		return c.Send(tr.T(messages.NotEnoughPhoto), &telebot.SendOptions{ParseMode: telebot.ModeHTML})
 	}
 
 	// // Для честного голосования?
@@ -76,95 +100,181 @@ func (vh *VoteHandlers) StartVote(c telebot.Context) error {
 	// 	return c.Send(messages.NotEnoughPlayers)
 	// }
 
-	err := vh.GameManager.StartVoting(session)
+	return vh.OpenVoting(chat, session, session.CurrentRoundID())
+}
+
+// OpenVoting - переводит раунд roundID в голосование и показывает все фото.
+// Вызывается по команде админа или автоматически по таймеру приёма фото.
+func (vh *VoteHandlers) OpenVoting(chat *telebot.Chat, session *game.GameSession, roundID int64) error {
+
+	tr := vh.Locales.ForChat(chat.ID)
+
+	err := vh.GameManager.StartVoting(session, roundID)
+	if errors.Is(err, game.ErrSessionClosed) || errors.Is(err, game.ErrStaleRound) {
+		// Таймер или кнопка пережили /endgame, новую игру или смену задания - сообщать нечего
+		logger.Info("Голосование не открыто: раунд или игра уже сменились", "chat_id", chat.ID, "err", err)
+		return nil
+	}
 	if err != nil {
-		log.Printf("[INFO] Попытка запуска голосования без раунда %d", chat.ID)
-		return c.Send(messages.ErrorMessagesForUser)
+		logger.Info("Попытка запуска голосования без раунда", "chat_id", chat.ID, "err", err)
+		_, err = vh.Bot.Send(chat, tr.T(messages.ErrorMessagesForUser))
+		return err
 	}
 
-	if err := c.Send(messages.VotingStartedMessage, &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
-		log.Printf("[ERROR] Не удалось отправить VotingStartedMessage: %v", err)
+	if _, err := vh.Bot.Send(chat, tr.T(messages.VotingStartedMessage), &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+		logger.Error("Не удалось отправить VotingStartedMessage", "chat_id", chat.ID, "err", err)
 	}
 
-	time.Sleep(1 * time.Second)
+	if names := session.SpeedBonusNames(); len(names) > 0 {
+		for i, name := range names {
+			names[i] = html.EscapeString(bot.PlayerName(tr, name))
+		}
+		if _, err := vh.Bot.Send(chat, tr.T(messages.BlitzSpeedBonus, strings.Join(names, ", ")), &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+			logger.Error("Не удалось отправить BlitzSpeedBonus", "chat_id", chat.ID, "err", err)
+		}
+	}
+
+	time.Sleep(vh.RevealDelay)
+
+	for _, photo := range session.VotePhotos() {
+		button := vh.voteButton(tr, chat.ID, roundID, photo.Index)
+
+		vh.Bot.Send(chat, &telebot.Photo{File: telebot.File{FileID: photo.PhotoID}},
+			&telebot.SendOptions{
+				ReplyMarkup: &telebot.ReplyMarkup{InlineKeyboard: [][]telebot.InlineButton{{button}}},
+			})
+	}
+
+	markup := &telebot.ReplyMarkup{}
+	markup.InlineKeyboard = [][]telebot.InlineButton{{vh.finishVoteBtn(tr)}}
 
-	// вспомогательная структура для вытаскивания фото
-	type photoWithInd struct {
-		UserID  int64
-		PhotoID string
+	if _, err := vh.Bot.Send(chat, tr.T(messages.VoitingMessage), &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup); err != nil {
+		logger.Error("Не удалось отправить VoitingMessage", "chat_id", chat.ID, "err", err)
 	}
 
-	var photos []photoWithInd
+	vh.startVoteTimer(chat, session)
+	return nil
+}
 
-	for userID, photoID := range session.UsersPhoto {
-		photos = append(photos, photoWithInd{UserID: userID, PhotoID: photoID})
+// startVoteTimer - обратный отсчёт в одном сообщении и автоматическое завершение голосования
+func (vh *VoteHandlers) startVoteTimer(chat *telebot.Chat, session *game.GameSession) {
+	if vh.GameManager.Settings.VoteDuration <= 0 {
+		return
 	}
 
-	session.IndexPhotoToUser = make(map[int]int64)
+	tr := vh.Locales.ForChat(chat.ID)
 
-	for id, val := range photos {
-		indexPhoto := id + 1
-		button := telebot.InlineButton{
-			Unique: fmt.Sprintf("vote_%d", indexPhoto),
-			Text:   fmt.Sprintf("Голосовать за фото №%d", indexPhoto),
+	// После рестарта срок мог уже пройти - тогда голосование просто завершится
+	var countdown *telebot.Message
+	if left := session.TimeLeft(); left > 0 {
+		var err error
+		countdown, err = vh.Bot.Send(chat, tr.T(messages.VoteCountdown, formatLeft(left)),
+			&telebot.SendOptions{ParseMode: telebot.ModeHTML})
+		if err != nil {
+			logger.Error("Не удалось отправить таймер голосования", "chat_id", chat.ID, "err", err)
 		}
+	}
 
-		session.IndexPhotoToUser[indexPhoto] = val.UserID
+	onTick := func(left time.Duration) {
+		if countdown == nil {
+			return
+		}
+		_, _ = vh.Bot.Edit(countdown, tr.T(messages.VoteCountdown, formatLeft(left)),
+			&telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	}
 
-		vh.Bot.Handle(&button, vh.makeVoteHandler(chat.ID, indexPhoto))
-		if vh.Bot != nil {
-			vh.Bot.Send(chat, &telebot.Photo{File: telebot.File{FileID: val.PhotoID}},
-				&telebot.SendOptions{
-					ReplyMarkup: &telebot.ReplyMarkup{InlineKeyboard: [][]telebot.InlineButton{{button}}},
-				})
+	onExpire := func() {
+		logger.Info("Автоматическое завершение голосования", "chat_id", chat.ID)
+		if countdown != nil {
+			_, _ = vh.Bot.Edit(countdown, tr.T(messages.VoteAutoFinished))
 		}
+		vh.FinishVoting(chat.ID, session)
 	}
 
-	// go vh.voteTimeout(chat.ID, session)
+	// Голосование завершили вручную, новым раундом или концом игры - отсчёт не должен застыть
+	onCancel := func() {
+		if countdown != nil {
+			_, _ = vh.Bot.Edit(countdown, tr.T(messages.VoteCountdownStopped))
+		}
+	}
 
-	markup := &telebot.ReplyMarkup{}
-	markup.InlineKeyboard = [][]telebot.InlineButton{{vh.FinishVoteBtn}}
+	vh.GameManager.StartVoteTimer(session, onTick, onExpire, onCancel)
+}
 
-	return c.Send(messages.VoitingMessage, &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup)
+// formatLeft - 90s -> "1:30"
+func formatLeft(d time.Duration) string {
+	sec := int((d + time.Second - 1) / time.Second)
+	return fmt.Sprintf("%d:%02d", sec/60, sec%60)
 }
 
-func (vh *VoteHandlers) makeVoteHandler(chatID int64, photoNum int) func(telebot.Context) error {
-	return func(c telebot.Context) error {
-		return vh.HandleVote(c, chatID, photoNum)
+// voteButton - кнопка голоса. В данных кнопки зашиты чат, раунд и номер фото,
+// поэтому один обработчик обслуживает все чаты, а старые кнопки легко отличить.
+func (vh *VoteHandlers) voteButton(tr i18n.Lang, chatID, roundID int64, indexPhoto int) telebot.InlineButton {
+	btn := localized(vh.VoteBtn, tr.T(messages.BtnVoteForPhoto, indexPhoto))
+	btn.Data = fmt.Sprintf("%d|%d|%d", chatID, roundID, indexPhoto)
+	return btn
+}
+
+// parseVoteData - разбирает данные кнопки голоса: чат|раунд|номер фото
+func parseVoteData(args []string) (chatID, roundID int64, photoNum int, err error) {
+	if len(args) != 3 {
+		return 0, 0, 0, fmt.Errorf("неверный формат данных голоса: %v", args)
+	}
+	if chatID, err = strconv.ParseInt(args[0], 10, 64); err != nil {
+		return 0, 0, 0, fmt.Errorf("неверный чат в данных голоса: %w", err)
 	}
+	if roundID, err = strconv.ParseInt(args[1], 10, 64); err != nil {
+		return 0, 0, 0, fmt.Errorf("неверный раунд в данных голоса: %w", err)
+	}
+	if photoNum, err = strconv.Atoi(args[2]); err != nil {
+		return 0, 0, 0, fmt.Errorf("неверный номер фото в данных голоса: %w", err)
+	}
+	return chatID, roundID, photoNum, nil
 }
 
-func (vh *VoteHandlers) HandleVote(c telebot.Context, chatID int64, photoNum int) error {
+// HandleVote - единый обработчик всех кнопок голосования
+func (vh *VoteHandlers) HandleVote(c telebot.Context) error {
 
 	voter := c.Sender()
+	tr := vh.Locales.For(c)
 
-	result, err := vh.GameManager.RegisterVote(chatID, voter, photoNum)
+	chatID, roundID, photoNum, err := parseVoteData(c.Args())
+	if err != nil || c.Chat() == nil || c.Chat().ID != chatID {
+		logging.From(c).Warn("Отклонена кнопка голосования", "data", c.Data(), "err", err)
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.VoteStale), ShowAlert: true})
+	}
+
+	result, err := vh.GameManager.RegisterVote(chatID, roundID, voter, photoNum)
+	if result.Message == messages.VoteCast {
+		result.Args = []interface{}{bot.PlayerName(tr, result.Args[0].(string))}
+	}
+	text := tr.T(result.Message, result.Args...)
 	if err != nil && result.IsCallback {
-		_ = c.Respond(&telebot.CallbackResponse{Text: result.Message})
+		_ = c.Respond(&telebot.CallbackResponse{Text: text, ShowAlert: result.IsAlert})
 		return nil
 	}
 
 	if result.IsCallback {
-		return c.Respond(&telebot.CallbackResponse{Text: result.Message})
+		return c.Respond(&telebot.CallbackResponse{Text: text, ShowAlert: result.IsAlert})
 	}
 
-	_ = c.Respond(&telebot.CallbackResponse{Text: messages.VotedReceived})
+	_ = c.Respond(&telebot.CallbackResponse{Text: tr.T(messages.VotedReceived)})
 
-	return c.Send(result.Message, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	return c.Send(text, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
 }
 
 func (vh *VoteHandlers) FinishVoting(chatID int64, session *game.GameSession) {
 
-	if session.FSM.Current() != game.VoteState {
-		log.Printf("[WARN] Попытка повторного завершения голосования в чате %d", chatID)
+	if !vh.GameManager.FinishVoting(session) {
+		logger.Warn("Попытка повторного завершения голосования", "chat_id", chatID)
 		return
 	}
 
-	vh.GameManager.FinishVoting(session)
-	result := bot.RenderScore(bot.RoundScore, session.RoundScore())
+	tr := vh.Locales.ForChat(chatID)
+	result := bot.RenderScore(tr, messages.RoundScore, session.RoundScore())
 
 	markup := &telebot.ReplyMarkup{}
-	markup.InlineKeyboard = [][]telebot.InlineButton{{vh.RoundHandlers.StartRoundBtn}}
+	markup.InlineKeyboard = [][]telebot.InlineButton{{vh.RoundHandlers.startRoundBtn(tr)}}
 
 	if vh.Bot != nil {
 		vh.Bot.Send(&telebot.Chat{ID: chatID}, result, &telebot.SendOptions{ParseMode: telebot.ModeHTML}, markup)
@@ -175,28 +285,11 @@ func (vh *VoteHandlers) HandleFinishVote(c telebot.Context) error {
 	chatID := c.Chat().ID
 
 	session, exist := vh.GameManager.GetSession(chatID)
-	if !exist || session.FSM.Current() != game.VoteState {
-		log.Printf("[INFO] Попытка окончания голосования без раунда %d", chatID)
-		return c.Send("Сейчас голосование не активно.")
+	if !exist || session.State() != game.VoteState {
+		logging.From(c).Info("Попытка окончания голосования без раунда")
+		return c.Send(vh.Locales.For(c).T(messages.VotingNotActive))
 	}
 
 	vh.FinishVoting(chatID, session)
 	return nil
 }
-
-// Таймер на голосование (отключен)
-func (vh *VoteHandlers) voteTimeout(chatID int64, session *game.GameSession) {
-	const voteDuration = 33 * time.Second
-
-	time.Sleep(voteDuration)
-
-	session, exist := vh.GameManager.GetSession(chatID)
-	if !exist || session.FSM.Current() != game.VoteState {
-		return
-	}
-	if vh.Bot != nil {
-		vh.Bot.Send(&telebot.Chat{ID: chatID}, "⏳ Голосование завершено автоматически!")
-	}
-	log.Printf("[TIMER] Автоматическое завершение голосования в чате %d", chatID)
-	vh.FinishVoting(chatID, session)
-}
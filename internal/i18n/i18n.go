@@ -0,0 +1,62 @@
+// Package i18n - выбор языка и тексты из каталога assets по ключу.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+
+	messages "github.com/kiselevos/memento_game_bot/assets"
+)
+
+// Lang - код языка (ru, en)
+type Lang string
+
+const (
+	RU Lang = "ru"
+	EN Lang = "en"
+)
+
+// Default - язык, если чат его не выбрал
+const Default = RU
+
+var catalog = map[Lang]map[messages.Key]string{
+	RU: messages.RU,
+	EN: messages.EN,
+}
+
+// Supported - поддерживаемые языки, язык по умолчанию первым
+func Supported() []Lang {
+	return []Lang{RU, EN}
+}
+
+// Parse - язык по коду: "en", "EN", "en-US". ok=false, если язык не поддерживается.
+func Parse(code string) (Lang, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if base, _, found := strings.Cut(code, "-"); found {
+		code = base
+	}
+	if _, ok := catalog[Lang(code)]; ok {
+		return Lang(code), true
+	}
+	return "", false
+}
+
+// T - текст по ключу, с аргументами - через fmt.Sprintf.
+// Если перевода нет, берётся язык по умолчанию, затем сам ключ.
+func (l Lang) T(key messages.Key, args ...interface{}) string {
+	text, ok := catalog[l][key]
+	if !ok {
+		if text, ok = catalog[Default][key]; !ok {
+			text = string(key)
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// Name - название языка на нём самом
+func (l Lang) Name() string {
+	return l.T(messages.LanguageName)
+}
@@ -0,0 +1,158 @@
+package i18n
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	messages "github.com/kiselevos/memento_game_bot/assets"
+	"github.com/kiselevos/memento_game_bot/internal/bottest"
+)
+
+// declaredKeys - все константы Key из assets/messages.go
+func declaredKeys(t *testing.T) []messages.Key {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "../../assets/messages.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []messages.Key
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok || len(spec.Values) != 1 {
+			return true
+		}
+		if lit, ok := spec.Values[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			value, _ := strconv.Unquote(lit.Value)
+			keys = append(keys, messages.Key(value))
+		}
+		return true
+	})
+	if len(keys) == 0 {
+		t.Fatal("no keys found")
+	}
+	return keys
+}
+
+var verbs = regexp.MustCompile(`%[-+# 0]*\d*(\.\d+)?[a-zA-Z]`)
+
+// verbsOf - глаголы форматирования текста, без учёта порядка
+func verbsOf(text string) string {
+	found := verbs.FindAllString(text, -1)
+	sort.Strings(found)
+	return strings.Join(found, " ")
+}
+
+func TestCatalogComplete(t *testing.T) {
+	keys := declaredKeys(t)
+
+	for _, lang := range Supported() {
+		texts := catalog[lang]
+		for _, key := range keys {
+			text, ok := texts[key]
+			if !ok || text == "" {
+				t.Errorf("%s: no text for %s", lang, key)
+				continue
+			}
+			// Аргументы подставляются одинаково для всех языков
+			if got, want := verbsOf(text), verbsOf(catalog[Default][key]); got != want {
+				t.Errorf("%s: %s has verbs %q, %s has %q", lang, key, got, Default, want)
+			}
+		}
+		if len(texts) != len(keys) {
+			t.Errorf("%s: %d texts for %d keys", lang, len(texts), len(keys))
+		}
+	}
+}
+
+func TestParseAndFallback(t *testing.T) {
+	for code, want := range map[string]Lang{"en": EN, "EN": EN, "en-US": EN, " ru ": RU} {
+		if got, ok := Parse(code); !ok || got != want {
+			t.Errorf("Parse(%q) = %q, %v", code, got, ok)
+		}
+	}
+	if _, ok := Parse("de"); ok {
+		t.Error("unsupported language parsed")
+	}
+
+	if got := Lang("de").T(messages.LanguageChanged); got != RU.T(messages.LanguageChanged) {
+		t.Errorf("unknown language must fall back to default, got %q", got)
+	}
+	if got := EN.T("NoSuchKey"); got != "NoSuchKey" {
+		t.Errorf("missing key must render as itself, got %q", got)
+	}
+	if got := EN.T(messages.BtnVoteForPhoto, 3); got != "Vote for photo #3" {
+		t.Errorf("args not applied: %q", got)
+	}
+}
+
+type fakeStore struct {
+	langs map[int64]string
+	reads int
+	err   error
+}
+
+func (s *fakeStore) Language(chatID int64) (string, error) {
+	s.reads++
+	return s.langs[chatID], s.err
+}
+
+func (s *fakeStore) SetLanguage(chatID int64, lang string) error {
+	s.langs[chatID] = lang
+	return nil
+}
+
+func TestLocalesResolution(t *testing.T) {
+	store := &fakeStore{langs: map[int64]string{-1: "en"}}
+	loc := NewLocales(store, RU)
+
+	user := bottest.User(7, "dave")
+	user.LanguageCode = "en"
+	fb := bottest.NewFakeBot()
+
+	// Группа: выбор чата, язык пользователя не учитывается
+	group := bottest.NewContext(fb, bottest.Text(bottest.GroupChat(-1), user, "hi"))
+	other := bottest.NewContext(fb, bottest.Text(bottest.GroupChat(-2), user, "hi"))
+	if loc.For(group) != EN || loc.For(other) != RU {
+		t.Errorf("group languages: %q %q", loc.For(group), loc.For(other))
+	}
+	reads := store.reads
+	loc.For(group)
+	loc.ForChat(-2)
+	if store.reads != reads {
+		t.Error("chat language must be cached, including 'not chosen'")
+	}
+
+	// Личка: язык Telegram, пока чат не выбрал свой
+	private := bottest.NewContext(fb, bottest.Text(bottest.PrivateChat(user), user, "hi"))
+	if loc.For(private) != EN || loc.ForChat(user.ID) != EN {
+		t.Error("private chat must use the user's language")
+	}
+	if err := loc.Set(user.ID, RU); err != nil {
+		t.Fatal(err)
+	}
+	if loc.For(private) != RU || store.langs[user.ID] != "ru" {
+		t.Error("explicit choice must win over the user's language")
+	}
+
+	// Ошибка хранилища не запоминается
+	failing := &fakeStore{langs: map[int64]string{}, err: errors.New("db down")}
+	loc = NewLocales(failing, RU)
+	loc.ForChat(-3)
+	loc.ForChat(-3)
+	if failing.reads != 2 {
+		t.Errorf("store errors must not be cached, reads: %d", failing.reads)
+	}
+
+	var nilLoc *Locales
+	if nilLoc.For(group) != Default || nilLoc.ForChat(-1) != Default {
+		t.Error("nil Locales must answer with the default language")
+	}
+}
@@ -0,0 +1,119 @@
+package i18n
+
+import (
+	"sync"
+
+	"github.com/kiselevos/memento_game_bot/internal/logging"
+
+	"gopkg.in/telebot.v3"
+)
+
+var logger = logging.Component("i18n")
+
+// Store - где хранится выбранный язык чата. Пустая строка - язык не выбран.
+type Store interface {
+	Language(chatID int64) (string, error)
+	SetLanguage(chatID int64, lang string) error
+}
+
+// Locales - язык для чата: выбранный через /language, в личке - язык
+// Telegram пользователя, иначе язык по умолчанию. Выбор кэшируется.
+// Нулевой *Locales всегда отвечает языком по умолчанию.
+type Locales struct {
+	store Store
+	def   Lang
+
+	mu      sync.RWMutex
+	chosen  map[int64]Lang // выбор чата из store, "" - не выбран
+	private map[int64]Lang // язык пользователя в личном чате, пока выбора нет
+}
+
+func NewLocales(store Store, def Lang) *Locales {
+	return &Locales{
+		store:   store,
+		def:     def,
+		chosen:  make(map[int64]Lang),
+		private: make(map[int64]Lang),
+	}
+}
+
+// For - язык ответа на апдейт
+func (l *Locales) For(c telebot.Context) Lang {
+	chat := c.Chat()
+	if l == nil || chat == nil {
+		return l.fallback()
+	}
+
+	if lang, ok := l.lookup(chat.ID); ok {
+		return lang
+	}
+
+	if chat.Type == telebot.ChatPrivate && c.Sender() != nil {
+		if lang, ok := Parse(c.Sender().LanguageCode); ok {
+			l.mu.Lock()
+			l.private[chat.ID] = lang
+			l.mu.Unlock()
+			return lang
+		}
+	}
+	return l.def
+}
+
+// ForChat - язык чата вне апдейта (таймеры, рассылки)
+func (l *Locales) ForChat(chatID int64) Lang {
+	if l == nil {
+		return Default
+	}
+	if lang, ok := l.lookup(chatID); ok {
+		return lang
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if lang, ok := l.private[chatID]; ok {
+		return lang
+	}
+	return l.def
+}
+
+// Set - сохраняет выбор языка чата
+func (l *Locales) Set(chatID int64, lang Lang) error {
+	if err := l.store.SetLanguage(chatID, string(lang)); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.chosen[chatID] = lang
+	l.mu.Unlock()
+	return nil
+}
+
+// lookup - выбранный язык чата, из кэша или store
+func (l *Locales) lookup(chatID int64) (Lang, bool) {
+	l.mu.RLock()
+	lang, cached := l.chosen[chatID]
+	l.mu.RUnlock()
+	if cached {
+		return lang, lang != ""
+	}
+
+	raw, err := l.store.Language(chatID)
+	if err != nil {
+		// Не кэшируем: при следующем апдейте попробуем снова
+		logger.Error("Не удалось получить язык чата", "chat_id", chatID, "err", err)
+		return "", false
+	}
+
+	lang, ok := Parse(raw)
+	l.mu.Lock()
+	l.chosen[chatID] = lang
+	l.mu.Unlock()
+	return lang, ok
+}
+
+func (l *Locales) fallback() Lang {
+	if l == nil {
+		return Default
+	}
+	return l.def
+}
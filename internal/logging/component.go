@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Component - логгер подсистемы с полем component. Можно хранить в переменной
+// пакета: записи уходят в обработчик, актуальный на момент вызова, а не создания.
+func Component(name string) *slog.Logger {
+	return slog.New(&defaultHandler{attrs: []slog.Attr{slog.String("component", name)}})
+}
+
+// defaultHandler - делегирует slog.Default() с дополнительными полями
+type defaultHandler struct {
+	attrs  []slog.Attr
+	groups []string
+}
+
+func (h *defaultHandler) target() slog.Handler {
+	target := slog.Default().Handler()
+	if len(h.attrs) > 0 {
+		target = target.WithAttrs(h.attrs)
+	}
+	for _, g := range h.groups {
+		target = target.WithGroup(g)
+	}
+	return target
+}
+
+func (h *defaultHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return slog.Default().Handler().Enabled(ctx, level)
+}
+
+func (h *defaultHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.target().Handle(ctx, r)
+}
+
+func (h *defaultHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(h.groups) > 0 {
+		// Поля после группы относятся к ней - проще собрать обработчик сразу
+		return h.target().WithAttrs(attrs)
+	}
+	return &defaultHandler{attrs: append(append([]slog.Attr{}, h.attrs...), attrs...)}
+}
+
+func (h *defaultHandler) WithGroup(name string) slog.Handler {
+	return &defaultHandler{attrs: h.attrs, groups: append(append([]string{}, h.groups...), name)}
+}
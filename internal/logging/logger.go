@@ -1,26 +1,114 @@
+// Package logging - структурированные логи на slog: уровни, вывод text или json,
+// ротация файла по размеру и времени, поля апдейта из мидлвари.
 package logging
 
 import (
-	"log"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/config"
+
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-func InitLogger(logFilePath string) {
+// Setup - настраивает логгер по умолчанию (slog и стандартный log) по конфигу.
+// Возвращённый Closer останавливает ротацию и закрывает файл.
+func Setup(conf config.LogConfig) (io.Closer, error) {
+	var (
+		out    io.Writer = os.Stdout
+		closer io.Closer = nopCloser{}
+	)
 
-	dir := filepath.Dir(logFilePath)
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			log.Fatalf("Не удалось создать директорию логов: %v", err)
+	if conf.Output != config.LogOutputStdout {
+		if err := os.MkdirAll(filepath.Dir(conf.File), 0755); err != nil {
+			return nil, fmt.Errorf("не удалось создать директорию логов: %w", err)
+		}
+		file := &lumberjack.Logger{
+			Filename:   conf.File,
+			MaxSize:    conf.MaxSizeMB,
+			MaxAge:     conf.MaxAgeDays,
+			MaxBackups: conf.MaxBackups,
 		}
+		r := newRotator(file, conf.RotateEvery)
+		out, closer = file, r
+	}
+
+	slog.SetDefault(slog.New(NewHandler(out, conf)))
+	slog.Info("Start logging", "level", conf.Level.String(), "format", conf.Format, "output", conf.Output)
+
+	return closer, nil
+}
+
+// NewHandler - обработчик slog в нужном формате. Источник сокращается до файл:строка.
+func NewHandler(out io.Writer, conf config.LogConfig) slog.Handler {
+	opts := &slog.HandlerOptions{
+		Level:     conf.Level,
+		AddSource: true,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.SourceKey && len(groups) == 0 {
+				if src, ok := a.Value.Any().(*slog.Source); ok {
+					return slog.String(slog.SourceKey, filepath.Base(src.File)+":"+strconv.Itoa(src.Line))
+				}
+			}
+			return a
+		},
+	}
+
+	if conf.Format == config.LogFormatJSON {
+		return slog.NewJSONHandler(out, opts)
+	}
+	return slog.NewTextHandler(out, opts)
+}
+
+// rotator - ротация файла по времени поверх ротации lumberjack по размеру
+type rotator struct {
+	file *lumberjack.Logger
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+func newRotator(file *lumberjack.Logger, every time.Duration) *rotator {
+	r := &rotator{file: file, stop: make(chan struct{}), done: make(chan struct{})}
+	if every > 0 {
+		go r.run(every)
+	} else {
+		close(r.done)
 	}
+	return r
+}
+
+func (r *rotator) run(every time.Duration) {
+	defer close(r.done)
 
-	file, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	if err != nil {
-		log.Fatalf("Не удалось открыть лог-файл: %v", err)
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.file.Rotate(); err != nil {
+				slog.Error("Не удалось ротировать лог", "err", err)
+			}
+		case <-r.stop:
+			return
+		}
 	}
+}
 
-	log.SetOutput(file)
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
-	log.Println("=====> Start logging....")
+func (r *rotator) Close() error {
+	r.once.Do(func() { close(r.stop) })
+	// Rotate открывает новый файл - закрываем только после него
+	<-r.done
+	return r.file.Close()
 }
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,123 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/config"
+	"github.com/kiselevos/memento_game_bot/internal/bottest"
+
+	"gopkg.in/telebot.v3"
+)
+
+// capture - подменяет логгер по умолчанию на JSON в буфер
+func capture(t *testing.T, level slog.Level) *bytes.Buffer {
+	t.Helper()
+
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(NewHandler(&buf, config.LogConfig{Level: level, Format: config.LogFormatJSON})))
+	return &buf
+}
+
+func records(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var res []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("not a json record %q: %v", line, err)
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
+func TestComponentUsesCurrentDefault(t *testing.T) {
+	logger := Component("game") // создан до настройки, как переменная пакета
+	buf := capture(t, slog.LevelInfo)
+
+	logger.Debug("hidden")
+	logger.With("chat_id", 5).Info("round", "task", "x")
+
+	recs := records(t, buf)
+	if len(recs) != 1 {
+		t.Fatalf("expected one record above debug level, got %d: %s", len(recs), buf)
+	}
+	rec := recs[0]
+	if rec["component"] != "game" || rec["chat_id"] != float64(5) || rec["task"] != "x" || rec["level"] != "INFO" {
+		t.Errorf("unexpected record %v", rec)
+	}
+	if src, _ := rec["source"].(string); !strings.HasPrefix(src, "logging_test.go:") {
+		t.Errorf("expected short source, got %v", rec["source"])
+	}
+}
+
+func TestMiddlewareAddsUpdateFields(t *testing.T) {
+	buf := capture(t, slog.LevelInfo)
+
+	bot := bottest.NewFakeBot()
+	bot.Handle("/start", func(c telebot.Context) error {
+		From(c).Info("hello")
+		return nil
+	}, Middleware)
+
+	u := bottest.Text(bottest.GroupChat(-42), bottest.User(7, "ann"), "/start")
+	u.ID = 1001
+	bot.Dispatch(u)
+
+	recs := records(t, buf)
+	if len(recs) != 1 {
+		t.Fatalf("expected one record, got %s", buf)
+	}
+	rec := recs[0]
+	if rec["update_id"] != float64(1001) || rec["chat_id"] != float64(-42) || rec["user_id"] != float64(7) {
+		t.Errorf("update fields missing: %v", rec)
+	}
+}
+
+func TestSetupRotatesFile(t *testing.T) {
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	dir := t.TempDir()
+	closer, err := Setup(config.LogConfig{
+		Level:       slog.LevelInfo,
+		Format:      config.LogFormatText,
+		Output:      config.LogOutputFile,
+		File:        filepath.Join(dir, "logs", "bot.log"),
+		MaxSizeMB:   1,
+		RotateEvery: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	defer closer.Close()
+
+	slog.Info("first")
+	time.Sleep(100 * time.Millisecond)
+	slog.Info("second")
+
+	files, _ := filepath.Glob(filepath.Join(dir, "logs", "bot*.log"))
+	if len(files) < 2 {
+		t.Fatalf("expected rotated files, got %v", files)
+	}
+	for _, f := range files {
+		data, _ := os.ReadFile(f)
+		if strings.Contains(string(data), "msg=first") && strings.Contains(string(data), "msg=second") {
+			t.Errorf("records before and after rotation in one file %s", f)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"log/slog"
+
+	"gopkg.in/telebot.v3"
+)
+
+const loggerKey = "logger"
+
+// Middleware - кладёт в контекст апдейта логгер с update_id, chat_id и user_id.
+// Обработчики берут его через From(c).
+func Middleware(next telebot.HandlerFunc) telebot.HandlerFunc {
+	return func(c telebot.Context) error {
+		c.Set(loggerKey, forUpdate(c))
+		return next(c)
+	}
+}
+
+// From - логгер апдейта. Без мидлвари поля апдейта собираются на месте.
+func From(c telebot.Context) *slog.Logger {
+	if c == nil {
+		return Component("bot")
+	}
+	if l, ok := c.Get(loggerKey).(*slog.Logger); ok {
+		return l
+	}
+	return forUpdate(c)
+}
+
+func forUpdate(c telebot.Context) *slog.Logger {
+	attrs := []any{"update_id", c.Update().ID}
+	if chat := c.Chat(); chat != nil {
+		attrs = append(attrs, "chat_id", chat.ID)
+	}
+	if sender := c.Sender(); sender != nil {
+		attrs = append(attrs, "user_id", sender.ID)
+	}
+	return Component("bot").With(attrs...)
+}
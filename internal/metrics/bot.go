@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/botinterface"
+
+	"gopkg.in/telebot.v3"
+)
+
+// instrumentedBot - BotInterface, который замеряет время каждого зарегистрированного обработчика
+type instrumentedBot struct {
+	botinterface.BotInterface
+	m *Metrics
+}
+
+// InstrumentBot - оборачивает бота так, что обработчики, зарегистрированные через
+// него, попадают в handler_duration_seconds. Имя обработчика - его эндпоинт.
+func InstrumentBot(bot botinterface.BotInterface, m *Metrics) botinterface.BotInterface {
+	if m == nil {
+		return bot
+	}
+	return &instrumentedBot{BotInterface: bot, m: m}
+}
+
+func (b *instrumentedBot) Handle(endpoint interface{}, handler telebot.HandlerFunc, m ...telebot.MiddlewareFunc) {
+	name := endpointName(endpoint)
+	measured := func(c telebot.Context) error {
+		start := time.Now()
+		err := handler(c)
+		b.m.ObserveHandler(name, time.Since(start), err)
+		return err
+	}
+	b.BotInterface.Handle(endpoint, measured, m...)
+}
+
+// endpointName - "/startgame", "photo" для telebot.OnPhoto, Unique для кнопок
+func endpointName(endpoint interface{}) string {
+	switch e := endpoint.(type) {
+	case string:
+		return strings.TrimLeft(e, "\a\f")
+	case telebot.CallbackEndpoint:
+		return strings.TrimLeft(e.CallbackUnique(), "\f")
+	default:
+		return "unknown"
+	}
+}
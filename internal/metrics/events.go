@@ -0,0 +1,23 @@
+package metrics
+
+import "github.com/kiselevos/memento_game_bot/internal/events"
+
+// FromEvents - подписчик шины событий, считающий раунды, фото, голоса и пропуски заданий
+func FromEvents(m *Metrics) events.Handler {
+	return func(e events.Event) {
+		if m == nil {
+			return
+		}
+		switch e := e.(type) {
+		case events.RoundStarted:
+			m.RoundsStarted.Inc()
+			if e.PrevTask != "" && e.PrevPhotos == 0 {
+				m.TaskSkips.Inc()
+			}
+		case events.PhotoSubmitted:
+			m.PhotosReceived.Inc()
+		case events.VoteCast:
+			m.VotesCast.Inc()
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Package metrics - метрики Prometheus и HTTP-эндпоинты здоровья бота.
+// Все методы Metrics безопасно вызывать у nil - так работают тесты без метрик.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "memento"
+
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	RoundsStarted   prometheus.Counter
+	PhotosReceived  prometheus.Counter
+	VotesCast       prometheus.Counter
+	TaskSkips       prometheus.Counter
+	DBErrors        *prometheus.CounterVec   // op - какая операция с БД упала
+	HandlerDuration *prometheus.HistogramVec // handler, result (ok/error)
+}
+
+// New - метрики на собственном реестре вместе со стандартными метриками Go и процесса
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+
+		RoundsStarted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rounds_started_total",
+			Help:      "Rounds started in all chats.",
+		}),
+		PhotosReceived: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "photos_received_total",
+			Help:      "Photos accepted for rounds.",
+		}),
+		VotesCast: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "votes_cast_total",
+			Help:      "Votes counted.",
+		}),
+		TaskSkips: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "task_skips_total",
+			Help:      "Tasks replaced by a new round without a single photo.",
+		}),
+		DBErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_errors_total",
+			Help:      "Failed database operations.",
+		}, []string{"op"}),
+		HandlerDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "handler_duration_seconds",
+			Help:      "Time spent in update handlers.",
+			Buckets:   []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"handler", "result"}),
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.RoundsStarted,
+		m.PhotosReceived,
+		m.VotesCast,
+		m.TaskSkips,
+		m.DBErrors,
+		m.HandlerDuration,
+	)
+	return m
+}
+
+// DBError - засчитывает упавшую операцию с БД
+func (m *Metrics) DBError(op string) {
+	if m == nil {
+		return
+	}
+	m.DBErrors.WithLabelValues(op).Inc()
+}
+
+// ObserveHandler - время работы обработчика
+func (m *Metrics) ObserveHandler(handler string, d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	m.HandlerDuration.WithLabelValues(handler, result).Observe(d.Seconds())
+}
+
+// WatchSessions - gauge активных игр по состояниям FSM. count вызывается при каждом сборе метрик.
+func (m *Metrics) WatchSessions(count func() map[string]int) {
+	if m == nil {
+		return
+	}
+	m.Registry.MustRegister(&sessionsCollector{count: count})
+}
+
+var sessionsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "active_sessions"),
+	"Active games by FSM state.",
+	[]string{"state"}, nil,
+)
+
+type sessionsCollector struct {
+	count func() map[string]int
+}
+
+func (c *sessionsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- sessionsDesc
+}
+
+func (c *sessionsCollector) Collect(ch chan<- prometheus.Metric) {
+	for state, n := range c.count() {
+		ch <- prometheus.MustNewConstMetric(sessionsDesc, prometheus.GaugeValue, float64(n), state)
+	}
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kiselevos/memento_game_bot/internal/bottest"
+	"github.com/kiselevos/memento_game_bot/internal/events"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gopkg.in/telebot.v3"
+)
+
+func TestFromEvents(t *testing.T) {
+	m := New()
+	handle := FromEvents(m)
+
+	handle(events.RoundStarted{ChatID: 1, Task: "a"})
+	handle(events.RoundStarted{ChatID: 1, Task: "b", PrevTask: "a", PrevPhotos: 0})
+	handle(events.RoundStarted{ChatID: 1, Task: "c", PrevTask: "b", PrevPhotos: 2})
+	handle(events.PhotoSubmitted{ChatID: 1})
+	handle(events.VoteCast{ChatID: 1})
+	handle(events.GameEnded{ChatID: 1})
+
+	if got := testutil.ToFloat64(m.RoundsStarted); got != 3 {
+		t.Errorf("rounds: expected 3, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.TaskSkips); got != 1 {
+		t.Errorf("skips: expected 1, got %v", got)
+	}
+	if testutil.ToFloat64(m.PhotosReceived) != 1 || testutil.ToFloat64(m.VotesCast) != 1 {
+		t.Error("photo and vote must be counted once")
+	}
+}
+
+func TestNilMetrics(t *testing.T) {
+	var m *Metrics
+	m.DBError("save_snapshot")
+	m.ObserveHandler("/start", 0, nil)
+	m.WatchSessions(func() map[string]int { return nil })
+	FromEvents(m)(events.VoteCast{})
+}
+
+func TestInstrumentBot(t *testing.T) {
+	m := New()
+	fake := bottest.NewFakeBot()
+	bot := InstrumentBot(fake, m)
+
+	btn := telebot.InlineButton{Unique: "vote"}
+	bot.Handle("/start", func(c telebot.Context) error { return nil })
+	bot.Handle(telebot.OnPhoto, func(c telebot.Context) error { return errors.New("boom") })
+	bot.Handle(&btn, func(c telebot.Context) error { return nil })
+
+	chat := bottest.GroupChat(-1)
+	user := bottest.User(1, "ann")
+	fake.Dispatch(bottest.Text(chat, user, "/start"))
+	fake.Dispatch(bottest.Photo(chat, user, "p"))
+	fake.Dispatch(bottest.Callback(chat, user, btn))
+
+	rec := httptest.NewRecorder()
+	NewHealth(m, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, want := range []string{
+		`memento_handler_duration_seconds_count{handler="/start",result="ok"} 1`,
+		`memento_handler_duration_seconds_count{handler="photo",result="error"} 1`,
+		`memento_handler_duration_seconds_count{handler="vote",result="ok"} 1`,
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("metrics without %q", want)
+		}
+	}
+}
+
+func TestHealthReady(t *testing.T) {
+	failing := false
+	h := NewHealth(New(), map[string]Check{
+		"db": func(ctx context.Context) error {
+			if failing {
+				return errors.New("connection refused")
+			}
+			return nil
+		},
+	})
+
+	ready := func() (int, string) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	if code, _ := ready(); code != http.StatusOK {
+		t.Fatalf("expected ready, got %d", code)
+	}
+
+	failing = true
+	if code, body := ready(); code != http.StatusServiceUnavailable || !strings.Contains(body, "connection refused") {
+		t.Fatalf("expected failed db check, got %d %s", code, body)
+	}
+
+	failing = false
+	h.SetStopping()
+	if code, _ := ready(); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected not ready while stopping, got %d", code)
+	}
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// checkTimeout - сколько ждать одну проверку готовности
+const checkTimeout = 3 * time.Second
+
+// Check - проверка зависимости для /readyz (БД, Telegram). nil - всё в порядке.
+type Check func(ctx context.Context) error
+
+// Health - HTTP-эндпоинты мониторинга:
+// /healthz - процесс жив, /readyz - зависимости доступны, /metrics - Prometheus.
+type Health struct {
+	checks   map[string]Check
+	stopping atomic.Bool
+	handler  http.Handler
+}
+
+// NewHealth - эндпоинты для метрик m и проверок checks
+func NewHealth(m *Metrics, checks map[string]Check) *Health {
+	h := &Health{checks: checks}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("/readyz", h.ready)
+	if m != nil {
+		mux.Handle("/metrics", promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{}))
+	}
+	h.handler = mux
+
+	return h
+}
+
+func (h *Health) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.ServeHTTP(w, r)
+}
+
+// SetStopping - бот останавливается: /readyz отвечает 503, чтобы на него не слали трафик
+func (h *Health) SetStopping() {
+	h.stopping.Store(true)
+}
+
+// ready - все проверки параллельно, 503 если хоть одна упала
+func (h *Health) ready(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+
+	results := make(map[string]string, len(h.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range h.checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			res := "ok"
+			if err := check(ctx); err != nil {
+				res = err.Error()
+			}
+			mu.Lock()
+			results[name] = res
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	code := http.StatusOK
+	if h.stopping.Load() {
+		code = http.StatusServiceUnavailable
+		results["bot"] = "stopping"
+	}
+	for _, res := range results {
+		if res != "ok" {
+			code = http.StatusServiceUnavailable
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(results)
+}
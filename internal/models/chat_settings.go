@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// ChatSettings - настройки чата, которые переживают игры
+type ChatSettings struct {
+	ChatID        int64     `gorm:"column:chat_id;primaryKey;autoIncrement:false"`
+	Language      string    `gorm:"column:language;size:8"`          // пусто - язык не выбран
+	DisabledPacks string    `gorm:"column:disabled_packs;type:text"` // ID выключенных паков через запятую
+	UpdatedAt     time.Time `gorm:"column:updated_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "gorm.io/gorm"
+
+// Статусы задания, предложенного в чате
+const (
+	ChatTaskPending  = "pending"  // ждёт решения админа чата
+	ChatTaskApproved = "approved" // выпадает в играх этого чата
+	ChatTaskRejected = "rejected"
+)
+
+// ChatTask - задание, которое участник предложил только для своего чата
+type ChatTask struct {
+	gorm.Model
+	ChatID     int64  `gorm:"column:chat_id;index"`
+	Text       string `gorm:"column:text;type:text"`
+	AuthorID   int64  `gorm:"column:author_id"`
+	AuthorName string `gorm:"column:author_name"` // @username или имя на момент предложения
+	Status     string `gorm:"column:status;size:16"`
+	DecidedBy  int64  `gorm:"column:decided_by"` // админ, одобривший или отклонивший
+}
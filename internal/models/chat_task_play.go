@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// ChatTaskPlay - задание, которое уже выпадало в чате, во всех играх
+type ChatTaskPlay struct {
+	ChatID   int64     `gorm:"column:chat_id;primaryKey;autoIncrement:false"`
+	TaskCode string    `gorm:"column:task_code;primaryKey;size:64"`
+	Plays    int       `gorm:"column:plays"`     // сколько раз выпадало
+	PlayedAt time.Time `gorm:"column:played_at"` // когда выпало последний раз
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// GameSnapshot - сохранённое состояние активной игры, чтобы пережить рестарт бота.
+type GameSnapshot struct {
+	ChatID    int64     `gorm:"column:chat_id;primaryKey;autoIncrement:false"`
+	State     string    `gorm:"column:state"`
+	Data      string    `gorm:"column:data;type:text"` // JSON с полным состоянием GameSession
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func NewGameSnapshot(chatID int64, state, data string) *GameSnapshot {
+	return &GameSnapshot{
+		ChatID: chatID,
+		State:  state,
+		Data:   data,
+	}
+}
@@ -1,16 +1,45 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Источник задания в каталоге
+const (
+	TaskSourceFile  = "file"  // из паков, импорт обновляет его
+	TaskSourceAdmin = "admin" // добавлено или изменено админом, импорт его не трогает
+)
 
 type Task struct {
 	gorm.Model
-	Text      string `gorm:"column:text;uniqueIndex"`
-	UseCount  int    `gorm:"column:use_count"`  // количестов фото на данный вопрос
-	SkipCount int    `gorm:"column:skip_count"` // количество раз, когда пропускали этот вопрос.
+	Code      string `gorm:"column:code;size:64;index"` // ID задания из пака
+	Pack      string `gorm:"column:pack;size:64;index"` // ID пака
+	Text      string `gorm:"column:text;uniqueIndex"`   // текст на языке по умолчанию
+	Texts     string `gorm:"column:texts;type:text"`    // JSON: язык -> текст
+	Category  string `gorm:"column:category;size:64"`
+	Tags      string `gorm:"column:tags"` // через запятую
+	Blitz     bool   `gorm:"column:blitz"`
+	Rating    string `gorm:"column:rating;size:16"`
+	Author    string `gorm:"column:author"`         // кто придумал задание
+	Source    string `gorm:"column:source;size:16"` // TaskSourceFile или TaskSourceAdmin
+	Disabled  bool   `gorm:"column:disabled"`       // выключено админом
+	Archived  bool   `gorm:"column:archived"`       // пропало из файлов паков
+	UseCount  int    `gorm:"column:use_count"`      // количестов фото на данный вопрос
+	SkipCount int    `gorm:"column:skip_count"`     // количество раз, когда пропускали этот вопрос.
 }
 
-func NewTask(text string) *Task {
+func NewTask(code, text string) *Task {
 	return &Task{
+		Code: code,
 		Text: text,
 	}
 }
+
+// TaskPack - пак заданий каталога
+type TaskPack struct {
+	ID        string    `gorm:"column:id;primaryKey;size:64"`
+	Titles    string    `gorm:"column:titles;type:text"` // JSON: язык -> название
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
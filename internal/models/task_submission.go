@@ -0,0 +1,22 @@
+package models
+
+import "gorm.io/gorm"
+
+// Статусы заявки на задание в общий каталог
+const (
+	SubmissionPending  = "pending" // ждёт владельцев бота
+	SubmissionApproved = "approved"
+	SubmissionRejected = "rejected"
+)
+
+// TaskSubmission - задание, присланное игроком в общий каталог через /submit
+type TaskSubmission struct {
+	gorm.Model
+	Text       string `gorm:"column:text;type:text"`
+	AuthorID   int64  `gorm:"column:author_id;index"`
+	AuthorName string `gorm:"column:author_name"` // @username или имя на момент заявки
+	ChatID     int64  `gorm:"column:chat_id"`     // откуда прислали
+	Status     string `gorm:"column:status;size:16;index"`
+	ReviewedBy int64  `gorm:"column:reviewed_by"`
+	TaskCode   string `gorm:"column:task_code;size:64"` // ID задания в каталоге после одобрения
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChatHistoryRepositoryInterface interface {
+	Played(chatID int64) (map[string]time.Time, error)
+	MarkPlayed(chatID int64, code string, at time.Time) error
+	ResetHistory(chatID int64) (int64, error)
+}
+
+type ChatHistoryRepository struct {
+	DataBase *db.Db
+}
+
+func NewChatHistoryRepository(db *db.Db) *ChatHistoryRepository {
+	return &ChatHistoryRepository{
+		DataBase: db,
+	}
+}
+
+// Played - задания, выпадавшие в чате, и когда последний раз
+func (repo *ChatHistoryRepository) Played(chatID int64) (map[string]time.Time, error) {
+	var plays []models.ChatTaskPlay
+	result := repo.DataBase.Where("chat_id = ?", chatID).Find(&plays)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	res := make(map[string]time.Time, len(plays))
+	for _, play := range plays {
+		res[play.TaskCode] = play.PlayedAt
+	}
+	return res, nil
+}
+
+// MarkPlayed - отмечает, что задание выпало в чате
+func (repo *ChatHistoryRepository) MarkPlayed(chatID int64, code string, at time.Time) error {
+	result := repo.DataBase.
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "chat_id"}, {Name: "task_code"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"plays":     gorm.Expr("chat_task_plays.plays + 1"),
+				"played_at": at,
+			}),
+		}).
+		Create(&models.ChatTaskPlay{ChatID: chatID, TaskCode: code, Plays: 1, PlayedAt: at})
+	return result.Error
+}
+
+// ResetHistory - забывает сыгранные в чате задания, возвращает сколько их было
+func (repo *ChatHistoryRepository) ResetHistory(chatID int64) (int64, error) {
+	result := repo.DataBase.Where("chat_id = ?", chatID).Delete(&models.ChatTaskPlay{})
+	return result.RowsAffected, result.Error
+}
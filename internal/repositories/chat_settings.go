@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChatSettingsRepositoryInterface interface {
+	Language(chatID int64) (string, error)
+	SetLanguage(chatID int64, lang string) error
+	DisabledPacks(chatID int64) ([]string, error)
+	SetDisabledPacks(chatID int64, packs []string) error
+}
+
+type ChatSettingsRepository struct {
+	DataBase *db.Db
+}
+
+func NewChatSettingsRepository(db *db.Db) *ChatSettingsRepository {
+	return &ChatSettingsRepository{
+		DataBase: db,
+	}
+}
+
+// Language - выбранный язык чата, пусто - не выбран
+func (repo *ChatSettingsRepository) Language(chatID int64) (string, error) {
+	settings, err := repo.get(chatID)
+	if err != nil {
+		return "", err
+	}
+	return settings.Language, nil
+}
+
+// SetLanguage - создаёт настройки чата или меняет в них только язык
+func (repo *ChatSettingsRepository) SetLanguage(chatID int64, lang string) error {
+	result := repo.DataBase.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "chat_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"language", "updated_at"}),
+		}).
+		Create(&models.ChatSettings{ChatID: chatID, Language: lang})
+	return result.Error
+}
+
+// DisabledPacks - паки, выключенные в чате
+func (repo *ChatSettingsRepository) DisabledPacks(chatID int64) ([]string, error) {
+	settings, err := repo.get(chatID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.DisabledPacks == "" {
+		return nil, nil
+	}
+	return strings.Split(settings.DisabledPacks, ","), nil
+}
+
+// SetDisabledPacks - создаёт настройки чата или меняет в них только выключенные паки
+func (repo *ChatSettingsRepository) SetDisabledPacks(chatID int64, packs []string) error {
+	result := repo.DataBase.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "chat_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"disabled_packs", "updated_at"}),
+		}).
+		Create(&models.ChatSettings{ChatID: chatID, DisabledPacks: strings.Join(packs, ",")})
+	return result.Error
+}
+
+// get - настройки чата, пустые, если чат ничего не менял
+func (repo *ChatSettingsRepository) get(chatID int64) (*models.ChatSettings, error) {
+	var settings models.ChatSettings
+	result := repo.DataBase.First(&settings, "chat_id = ?", chatID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return &models.ChatSettings{ChatID: chatID}, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &settings, nil
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/pkg/db"
+)
+
+type ChatTaskRepositoryInterface interface {
+	Create(task *models.ChatTask) (*models.ChatTask, error)
+	Get(id uint) (*models.ChatTask, error)
+	Decide(id uint, status string, decidedBy int64) (bool, error)
+	HasText(chatID int64, text string) (bool, error)
+	Approved(chatID int64) ([]models.ChatTask, error)
+}
+
+type ChatTaskRepository struct {
+	DataBase *db.Db
+}
+
+func NewChatTaskRepository(db *db.Db) *ChatTaskRepository {
+	return &ChatTaskRepository{
+		DataBase: db,
+	}
+}
+
+func (repo *ChatTaskRepository) Create(task *models.ChatTask) (*models.ChatTask, error) {
+	result := repo.DataBase.Create(task)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return task, nil
+}
+
+func (repo *ChatTaskRepository) Get(id uint) (*models.ChatTask, error) {
+	var task models.ChatTask
+	result := repo.DataBase.First(&task, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &task, nil
+}
+
+// Decide - одобряет или отклоняет предложение. false - его уже решил другой админ.
+func (repo *ChatTaskRepository) Decide(id uint, status string, decidedBy int64) (bool, error) {
+	result := repo.DataBase.
+		Model(&models.ChatTask{}).
+		Where("id = ? AND status = ?", id, models.ChatTaskPending).
+		Updates(map[string]interface{}{"status": status, "decided_by": decidedBy})
+	return result.RowsAffected == 1, result.Error
+}
+
+// HasText - такое задание уже предложено или одобрено в чате
+func (repo *ChatTaskRepository) HasText(chatID int64, text string) (bool, error) {
+	var count int64
+	result := repo.DataBase.
+		Model(&models.ChatTask{}).
+		Where("chat_id = ? AND text = ? AND status <> ?", chatID, text, models.ChatTaskRejected).
+		Count(&count)
+	return count > 0, result.Error
+}
+
+// Approved - одобренные задания чата в порядке добавления
+func (repo *ChatTaskRepository) Approved(chatID int64) ([]models.ChatTask, error) {
+	var res []models.ChatTask
+	result := repo.DataBase.
+		Where("chat_id = ? AND status = ?", chatID, models.ChatTaskApproved).
+		Order("id").
+		Find(&res)
+	return res, result.Error
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/repositories"
+)
+
+var _ repositories.ChatHistoryRepositoryInterface = (*ChatHistoryRepo)(nil)
+
+// ChatHistoryRepo - ChatHistoryRepository в памяти
+type ChatHistoryRepo struct {
+	mu     sync.Mutex
+	played map[int64]map[string]time.Time
+}
+
+func NewChatHistoryRepo() *ChatHistoryRepo {
+	return &ChatHistoryRepo{
+		played: make(map[int64]map[string]time.Time),
+	}
+}
+
+func (repo *ChatHistoryRepo) Played(chatID int64) (map[string]time.Time, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := make(map[string]time.Time, len(repo.played[chatID]))
+	for code, at := range repo.played[chatID] {
+		res[code] = at
+	}
+	return res, nil
+}
+
+func (repo *ChatHistoryRepo) MarkPlayed(chatID int64, code string, at time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if repo.played[chatID] == nil {
+		repo.played[chatID] = make(map[string]time.Time)
+	}
+	repo.played[chatID][code] = at
+	return nil
+}
+
+func (repo *ChatHistoryRepo) ResetHistory(chatID int64) (int64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	n := int64(len(repo.played[chatID]))
+	delete(repo.played, chatID)
+	return n, nil
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/kiselevos/memento_game_bot/internal/repositories"
+)
+
+var _ repositories.ChatSettingsRepositoryInterface = (*ChatSettingsRepo)(nil)
+
+// ChatSettingsRepo - ChatSettingsRepository в памяти
+type ChatSettingsRepo struct {
+	mu        sync.Mutex
+	languages map[int64]string
+	disabled  map[int64][]string
+}
+
+func NewChatSettingsRepo() *ChatSettingsRepo {
+	return &ChatSettingsRepo{
+		languages: make(map[int64]string),
+		disabled:  make(map[int64][]string),
+	}
+}
+
+func (repo *ChatSettingsRepo) Language(chatID int64) (string, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return repo.languages[chatID], nil
+}
+
+func (repo *ChatSettingsRepo) SetLanguage(chatID int64, lang string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.languages[chatID] = lang
+	return nil
+}
+
+func (repo *ChatSettingsRepo) DisabledPacks(chatID int64) ([]string, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return append([]string(nil), repo.disabled[chatID]...), nil
+}
+
+func (repo *ChatSettingsRepo) SetDisabledPacks(chatID int64, packs []string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.disabled[chatID] = append([]string(nil), packs...)
+	return nil
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var _ repositories.ChatTaskRepositoryInterface = (*ChatTaskRepo)(nil)
+
+// ChatTaskRepo - ChatTaskRepository в памяти
+type ChatTaskRepo struct {
+	mu     sync.Mutex
+	tasks  []*models.ChatTask
+	nextID uint
+}
+
+func NewChatTaskRepo() *ChatTaskRepo {
+	return &ChatTaskRepo{nextID: 1}
+}
+
+func (repo *ChatTaskRepo) Create(task *models.ChatTask) (*models.ChatTask, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	task.ID = repo.nextID
+	repo.nextID++
+	task.CreatedAt = time.Now()
+	task.UpdatedAt = task.CreatedAt
+
+	stored := *task
+	repo.tasks = append(repo.tasks, &stored)
+	return task, nil
+}
+
+func (repo *ChatTaskRepo) Get(id uint) (*models.ChatTask, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, task := range repo.tasks {
+		if task.ID == id {
+			res := *task
+			return &res, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *ChatTaskRepo) Decide(id uint, status string, decidedBy int64) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, task := range repo.tasks {
+		if task.ID == id && task.Status == models.ChatTaskPending {
+			task.Status = status
+			task.DecidedBy = decidedBy
+			task.UpdatedAt = time.Now()
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (repo *ChatTaskRepo) HasText(chatID int64, text string) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, task := range repo.tasks {
+		if task.ChatID == chatID && task.Text == text && task.Status != models.ChatTaskRejected {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (repo *ChatTaskRepo) Approved(chatID int64) ([]models.ChatTask, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var res []models.ChatTask
+	for _, task := range repo.tasks {
+		if task.ChatID == chatID && task.Status == models.ChatTaskApproved {
+			res = append(res, *task)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var _ repositories.SessionRepositoryInterface = (*SessionRepo)(nil)
+
+// SessionRepo - SessionRepository в памяти
+type SessionRepo struct {
+	mu       sync.Mutex
+	lastID   uint
+	sessions []*models.Session
+}
+
+func NewSessionRepo() *SessionRepo {
+	return &SessionRepo{}
+}
+
+// Create - как и в БД, закрывает прошлые сессии чата
+func (repo *SessionRepo) Create(session *models.Session) (*models.Session, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, s := range repo.sessions {
+		if s.ChatID == session.ChatID {
+			s.IsActive = false
+		}
+	}
+
+	repo.lastID++
+	session.ID = repo.lastID
+	session.CreatedAt = time.Now()
+	session.UpdatedAt = session.CreatedAt
+
+	stored := *session
+	stored.Users = append([]*models.User(nil), session.Users...)
+	repo.sessions = append(repo.sessions, &stored)
+	return session, nil
+}
+
+func (repo *SessionRepo) GetSessionByID(chatID int64) (*models.Session, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	s := repo.active(chatID)
+	if s == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return copySession(s), nil
+}
+
+func (repo *SessionRepo) ChangeIsActive(chatID int64) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	s := repo.active(chatID)
+	if s == nil {
+		return gorm.ErrRecordNotFound
+	}
+	s.IsActive = false
+	return nil
+}
+
+// AddUserToSession - many to many, повторная привязка не дублирует участника
+func (repo *SessionRepo) AddUserToSession(session *models.Session, user *models.User) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	s := repo.byID(session.ID)
+	if s == nil {
+		return gorm.ErrRecordNotFound
+	}
+	for _, u := range s.Users {
+		if u.TgUserId == user.TgUserId {
+			return nil
+		}
+	}
+	s.Users = append(s.Users, user)
+	return nil
+}
+
+// AddPhotosCount - увеличивает счётчик последней сессии чата
+func (repo *SessionRepo) AddPhotosCount(chatID int64, count int) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i := len(repo.sessions) - 1; i >= 0; i-- {
+		if repo.sessions[i].ChatID == chatID {
+			repo.sessions[i].PhotosCount += count
+			return nil
+		}
+	}
+	return nil
+}
+
+// Sessions - все сессии чата, от старых к новым. Для проверок в тестах.
+func (repo *SessionRepo) Sessions(chatID int64) []*models.Session {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var res []*models.Session
+	for _, s := range repo.sessions {
+		if s.ChatID == chatID {
+			res = append(res, copySession(s))
+		}
+	}
+	return res
+}
+
+func (repo *SessionRepo) active(chatID int64) *models.Session {
+	for _, s := range repo.sessions {
+		if s.ChatID == chatID && s.IsActive {
+			return s
+		}
+	}
+	return nil
+}
+
+func (repo *SessionRepo) byID(id uint) *models.Session {
+	for _, s := range repo.sessions {
+		if s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+func copySession(s *models.Session) *models.Session {
+	res := *s
+	res.Users = append([]*models.User(nil), s.Users...)
+	return &res
+}
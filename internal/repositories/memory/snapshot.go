@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/internal/repositories"
+)
+
+var _ repositories.SnapshotRepositoryInterface = (*SnapshotRepo)(nil)
+
+// SnapshotRepo - SnapshotRepository в памяти
+type SnapshotRepo struct {
+	mu        sync.Mutex
+	snapshots map[int64]*models.GameSnapshot
+}
+
+func NewSnapshotRepo() *SnapshotRepo {
+	return &SnapshotRepo{
+		snapshots: make(map[int64]*models.GameSnapshot),
+	}
+}
+
+func (repo *SnapshotRepo) Save(snapshot *models.GameSnapshot) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored := *snapshot
+	stored.UpdatedAt = time.Now()
+	repo.snapshots[snapshot.ChatID] = &stored
+	return nil
+}
+
+func (repo *SnapshotRepo) Delete(chatID int64) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.snapshots, chatID)
+	return nil
+}
+
+func (repo *SnapshotRepo) GetAll() ([]*models.GameSnapshot, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := make([]*models.GameSnapshot, 0, len(repo.snapshots))
+	for _, s := range repo.snapshots {
+		snapshot := *s
+		res = append(res, &snapshot)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ChatID < res[j].ChatID })
+	return res, nil
+}
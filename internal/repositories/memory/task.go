@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var _ repositories.TaskRepositoryInterface = (*TaskRepo)(nil)
+
+// TaskRepo - TaskRepository в памяти
+type TaskRepo struct {
+	mu     sync.Mutex
+	lastID uint
+	tasks  map[uint]*models.Task
+	packs  map[string]*models.TaskPack
+}
+
+func NewTaskRepo() *TaskRepo {
+	return &TaskRepo{
+		tasks: make(map[uint]*models.Task),
+		packs: make(map[string]*models.TaskPack),
+	}
+}
+
+func (repo *TaskRepo) Create(task *models.Task) (*models.Task, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if repo.find(func(t *models.Task) bool { return t.Text == task.Text }) != nil {
+		return nil, gorm.ErrDuplicatedKey
+	}
+
+	repo.lastID++
+	task.ID = repo.lastID
+	task.CreatedAt = time.Now()
+	task.UpdatedAt = task.CreatedAt
+
+	stored := *task
+	repo.tasks[task.ID] = &stored
+	return task, nil
+}
+
+func (repo *TaskRepo) GetTaskByCode(code string) (*models.Task, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return copyOrNotFound(repo.find(func(t *models.Task) bool { return t.Code == code }))
+}
+
+func (repo *TaskRepo) GetAll() ([]models.Task, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := make([]models.Task, 0, len(repo.tasks))
+	for _, task := range repo.tasks {
+		res = append(res, *task)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res, nil
+}
+
+func (repo *TaskRepo) Update(task *models.Task) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored, ok := repo.tasks[task.ID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if other := repo.find(func(t *models.Task) bool { return t.Text == task.Text && t.ID != task.ID }); other != nil {
+		return gorm.ErrDuplicatedKey
+	}
+
+	// Счётчики меняет только статистика
+	updated := *task
+	updated.CreatedAt = stored.CreatedAt
+	updated.UseCount = stored.UseCount
+	updated.SkipCount = stored.SkipCount
+	updated.UpdatedAt = time.Now()
+	repo.tasks[task.ID] = &updated
+	return nil
+}
+
+func (repo *TaskRepo) GetPacks() ([]models.TaskPack, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := make([]models.TaskPack, 0, len(repo.packs))
+	for _, pack := range repo.packs {
+		res = append(res, *pack)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res, nil
+}
+
+func (repo *TaskRepo) SavePack(pack *models.TaskPack) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored := *pack
+	stored.UpdatedAt = time.Now()
+	repo.packs[pack.ID] = &stored
+	return nil
+}
+
+func (repo *TaskRepo) AddTaskStats(code string, use, skip int) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	task := repo.find(func(t *models.Task) bool { return t.Code == code })
+	if task == nil {
+		return nil
+	}
+	task.UseCount += use
+	task.SkipCount += skip
+	task.UpdatedAt = time.Now()
+	return nil
+}
+
+func (repo *TaskRepo) find(match func(*models.Task) bool) *models.Task {
+	for _, task := range repo.tasks {
+		if match(task) {
+			return task
+		}
+	}
+	return nil
+}
+
+func copyOrNotFound(task *models.Task) (*models.Task, error) {
+	if task == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	res := *task
+	return &res, nil
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var _ repositories.TaskSubmissionRepositoryInterface = (*TaskSubmissionRepo)(nil)
+
+// TaskSubmissionRepo - TaskSubmissionRepository в памяти
+type TaskSubmissionRepo struct {
+	mu     sync.Mutex
+	subs   []*models.TaskSubmission
+	nextID uint
+}
+
+func NewTaskSubmissionRepo() *TaskSubmissionRepo {
+	return &TaskSubmissionRepo{nextID: 1}
+}
+
+func (repo *TaskSubmissionRepo) Create(sub *models.TaskSubmission) (*models.TaskSubmission, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	sub.ID = repo.nextID
+	repo.nextID++
+	sub.CreatedAt = time.Now()
+	sub.UpdatedAt = sub.CreatedAt
+
+	stored := *sub
+	repo.subs = append(repo.subs, &stored)
+	return sub, nil
+}
+
+func (repo *TaskSubmissionRepo) Get(id uint) (*models.TaskSubmission, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if sub := repo.find(id); sub != nil {
+		res := *sub
+		return &res, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *TaskSubmissionRepo) Pending() ([]models.TaskSubmission, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var res []models.TaskSubmission
+	for _, sub := range repo.subs {
+		if sub.Status == models.SubmissionPending {
+			res = append(res, *sub)
+		}
+	}
+	return res, nil
+}
+
+func (repo *TaskSubmissionRepo) SetText(id uint, text string) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	sub := repo.find(id)
+	if sub == nil || sub.Status != models.SubmissionPending {
+		return false, nil
+	}
+	sub.Text = text
+	sub.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (repo *TaskSubmissionRepo) Decide(id uint, status string, reviewedBy int64, taskCode string) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	sub := repo.find(id)
+	if sub == nil || sub.Status != models.SubmissionPending {
+		return false, nil
+	}
+	sub.Status = status
+	sub.ReviewedBy = reviewedBy
+	sub.TaskCode = taskCode
+	sub.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (repo *TaskSubmissionRepo) find(id uint) *models.TaskSubmission {
+	for _, sub := range repo.subs {
+		if sub.ID == id {
+			return sub
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var _ repositories.UserRepositoryInterface = (*UserRepo)(nil)
+
+// UserRepo - UserRepository в памяти
+type UserRepo struct {
+	mu     sync.Mutex
+	lastID uint
+	users  map[int64]*models.User // по tg_user_id
+}
+
+func NewUserRepo() *UserRepo {
+	return &UserRepo{
+		users: make(map[int64]*models.User),
+	}
+}
+
+func (repo *UserRepo) Create(user *models.User) (*models.User, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, exist := repo.users[user.TgUserId]; exist {
+		return nil, gorm.ErrDuplicatedKey
+	}
+
+	repo.lastID++
+	user.ID = repo.lastID
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+
+	stored := *user
+	repo.users[user.TgUserId] = &stored
+	return user, nil
+}
+
+func (repo *UserRepo) GetUserByTGID(id int64) (*models.User, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	user, ok := repo.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	res := *user
+	return &res, nil
+}
+
+// AddUserStatistics - как и в БД, для неизвестного пользователя ничего не делает
+func (repo *UserRepo) AddUserStatistics(userID int64, games, photos, votes int) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	user, ok := repo.users[userID]
+	if !ok {
+		return nil
+	}
+	user.GamesPlayed += games
+	user.PhotosSent += photos
+	user.UsersVote += votes
+	user.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,445 @@
+package repositories
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/config"
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/migrations"
+	"github.com/kiselevos/memento_game_bot/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+// forEachBackend - гоняет тест на SQLite и, если задан TEST_POSTGRES_DSN, на Postgres.
+func forEachBackend(t *testing.T, fn func(t *testing.T, database *db.Db)) {
+	t.Helper()
+
+	t.Run(config.DriverSQLite, func(t *testing.T) {
+		database, err := db.NewDB(config.DbConfig{
+			Driver: config.DriverSQLite,
+			Path:   filepath.Join(t.TempDir(), "test.db"),
+		})
+		if err != nil {
+			t.Fatalf("open sqlite: %v", err)
+		}
+		prepareSchema(t, database)
+		fn(t, database)
+	})
+
+	t.Run(config.DriverPostgres, func(t *testing.T) {
+		dsn := os.Getenv("TEST_POSTGRES_DSN")
+		if dsn == "" {
+			t.Skip("TEST_POSTGRES_DSN is not set")
+		}
+		database, err := db.NewDB(config.DbConfig{Driver: config.DriverPostgres, Dsn: dsn})
+		if err != nil {
+			t.Fatalf("open postgres: %v", err)
+		}
+		prepareSchema(t, database)
+		fn(t, database)
+	})
+}
+
+func prepareSchema(t *testing.T, database *db.Db) {
+	t.Helper()
+
+	err := database.Migrator().DropTable(
+		"session_users", "sessions", "users", "tasks", "task_packs", "game_snapshots", "chat_settings", "chat_task_plays", "chat_tasks", "task_submissions", "schema_migrations")
+	if err != nil {
+		t.Fatalf("drop tables: %v", err)
+	}
+	if _, err := migrations.NewMigrator(database.DB).Up(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := database.DB.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+}
+
+func TestUserRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, database *db.Db) {
+		repo := NewUserRepository(database)
+
+		if _, err := repo.Create(models.NewUser(42, "nick", "Nick")); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if _, err := repo.Create(models.NewUser(42, "dup", "Dup")); err == nil {
+			t.Error("expected unique violation on tg_user_id")
+		}
+
+		if err := repo.AddUserStatistics(42, 1, 3, 2); err != nil {
+			t.Fatalf("add stats: %v", err)
+		}
+		if err := repo.AddUserStatistics(42, 1, 0, 1); err != nil {
+			t.Fatalf("add stats: %v", err)
+		}
+
+		user, err := repo.GetUserByTGID(42)
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if user.GamesPlayed != 2 || user.PhotosSent != 3 || user.UsersVote != 3 {
+			t.Errorf("unexpected stats: %+v", user)
+		}
+
+		if _, err := repo.GetUserByTGID(7); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("expected ErrRecordNotFound, got %v", err)
+		}
+	})
+}
+
+func TestSessionRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, database *db.Db) {
+		repo := NewSessionRepository(database)
+		users := NewUserRepository(database)
+
+		first := models.NewSession(100)
+		first.IsActive = true
+		if _, err := repo.Create(first); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+
+		// Новая игра в том же чате деактивирует предыдущую
+		second := models.NewSession(100)
+		second.IsActive = true
+		if _, err := repo.Create(second); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+
+		active, err := repo.GetSessionByID(100)
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if active.ID != second.ID {
+			t.Errorf("expected latest session %d active, got %d", second.ID, active.ID)
+		}
+
+		user, _ := users.Create(models.NewUser(1, "a", "A"))
+		if err := repo.AddUserToSession(active, user); err != nil {
+			t.Fatalf("add user: %v", err)
+		}
+		var count int64
+		database.Table("session_users").Where("session_id = ?", active.ID).Count(&count)
+		if count != 1 {
+			t.Errorf("expected 1 session user, got %d", count)
+		}
+
+		if err := repo.ChangeIsActive(100); err != nil {
+			t.Fatalf("change active: %v", err)
+		}
+		if _, err := repo.GetSessionByID(100); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("expected no active session, got %v", err)
+		}
+
+		// Счётчик фото пишется в последнюю сессию, даже закрытую
+		if err := repo.AddPhotosCount(100, 4); err != nil {
+			t.Fatalf("add photos: %v", err)
+		}
+		var latest, previous models.Session
+		database.First(&latest, second.ID)
+		database.First(&previous, first.ID)
+		if latest.PhotosCount != 4 || previous.PhotosCount != 0 {
+			t.Errorf("unexpected photos count: latest=%d previous=%d", latest.PhotosCount, previous.PhotosCount)
+		}
+	})
+}
+
+func TestTaskRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, database *db.Db) {
+		repo := NewTaskRepository(database)
+
+		if _, err := repo.Create(models.NewTask("classic-cat", "Сфотографируй кота")); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if err := repo.AddTaskStats("classic-cat", 2, 1); err != nil {
+			t.Fatalf("add stats: %v", err)
+		}
+
+		task, err := repo.GetTaskByCode("classic-cat")
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if task.UseCount != 2 || task.SkipCount != 1 {
+			t.Errorf("unexpected task stats: %+v", task)
+		}
+
+		if _, err := repo.GetTaskByCode("нет такого"); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("expected ErrRecordNotFound, got %v", err)
+		}
+	})
+}
+
+func TestTaskRepositoryCatalog(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, database *db.Db) {
+		repo := NewTaskRepository(database)
+
+		for _, task := range []*models.Task{
+			models.NewTask("classic-sky", "Сфотографируй небо"),
+			models.NewTask("classic-cat", "Сфотографируй кота"),
+		} {
+			if _, err := repo.Create(task); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+		}
+		if err := repo.AddTaskStats("classic-sky", 5, 0); err != nil {
+			t.Fatalf("add stats: %v", err)
+		}
+
+		all, err := repo.GetAll()
+		if err != nil || len(all) != 2 || all[0].Code != "classic-sky" {
+			t.Fatalf("get all must keep insert order: %+v, %v", all, err)
+		}
+
+		// Update пишет и нулевые значения, но не трогает статистику
+		sky := all[0]
+		sky.Text = "Сфотографируй закат"
+		sky.Blitz = true
+		sky.UseCount = 0
+		if err := repo.Update(&sky); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+		sky.Blitz = false
+		if err := repo.Update(&sky); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+		got, err := repo.GetTaskByCode("classic-sky")
+		if err != nil || got.Text != "Сфотографируй закат" || got.Blitz || got.UseCount != 5 {
+			t.Errorf("unexpected task after update: %+v, %v", got, err)
+		}
+
+		for _, titles := range []string{`{"ru":"Классика"}`, `{"ru":"Классика","en":"Classic"}`} {
+			if err := repo.SavePack(&models.TaskPack{ID: "classic", Titles: titles}); err != nil {
+				t.Fatalf("save pack: %v", err)
+			}
+		}
+		packs, err := repo.GetPacks()
+		if err != nil || len(packs) != 1 || packs[0].Titles != `{"ru":"Классика","en":"Classic"}` {
+			t.Errorf("pack must be upserted: %+v, %v", packs, err)
+		}
+	})
+}
+
+func TestSnapshotRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, database *db.Db) {
+		repo := NewSnapshotRepository(database)
+
+		if err := repo.Save(models.NewGameSnapshot(1, "waiting", `{"a":1}`)); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+		if err := repo.Save(models.NewGameSnapshot(1, "voting", `{"a":2}`)); err != nil {
+			t.Fatalf("overwrite: %v", err)
+		}
+		if err := repo.Save(models.NewGameSnapshot(2, "waiting", `{}`)); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+
+		all, err := repo.GetAll()
+		if err != nil {
+			t.Fatalf("get all: %v", err)
+		}
+		if len(all) != 2 {
+			t.Fatalf("expected 2 snapshots, got %d", len(all))
+		}
+		for _, s := range all {
+			if s.ChatID == 1 && (s.State != "voting" || s.Data != `{"a":2}`) {
+				t.Errorf("snapshot not overwritten: %+v", s)
+			}
+		}
+
+		if err := repo.Delete(1); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		all, _ = repo.GetAll()
+		if len(all) != 1 || all[0].ChatID != 2 {
+			t.Errorf("unexpected snapshots after delete: %+v", all)
+		}
+	})
+}
+
+func TestChatSettingsRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, database *db.Db) {
+		repo := NewChatSettingsRepository(database)
+
+		lang, err := repo.Language(-1)
+		if err != nil || lang != "" {
+			t.Fatalf("expected no language for a new chat, got %q, %v", lang, err)
+		}
+
+		if err := repo.SetLanguage(-1, "en"); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+		if err := repo.SetLanguage(-1, "ru"); err != nil {
+			t.Fatalf("overwrite: %v", err)
+		}
+		if err := repo.SetLanguage(-2, "en"); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+
+		if lang, _ := repo.Language(-1); lang != "ru" {
+			t.Errorf("expected overwritten language ru, got %q", lang)
+		}
+		if lang, _ := repo.Language(-2); lang != "en" {
+			t.Errorf("expected en, got %q", lang)
+		}
+
+		// Паки и язык меняются независимо
+		if packs, err := repo.DisabledPacks(-1); err != nil || len(packs) != 0 {
+			t.Fatalf("expected no disabled packs, got %v, %v", packs, err)
+		}
+		if err := repo.SetDisabledPacks(-1, []string{"blitz", "party"}); err != nil {
+			t.Fatalf("set packs: %v", err)
+		}
+		if err := repo.SetDisabledPacks(-3, []string{"blitz"}); err != nil {
+			t.Fatalf("set packs for new chat: %v", err)
+		}
+		if packs, _ := repo.DisabledPacks(-1); !reflect.DeepEqual(packs, []string{"blitz", "party"}) {
+			t.Errorf("unexpected disabled packs: %v", packs)
+		}
+		if lang, _ := repo.Language(-1); lang != "ru" {
+			t.Errorf("setting packs changed language to %q", lang)
+		}
+		if lang, _ := repo.Language(-3); lang != "" {
+			t.Errorf("new chat with packs must have no language, got %q", lang)
+		}
+		if err := repo.SetDisabledPacks(-1, nil); err != nil {
+			t.Fatalf("clear packs: %v", err)
+		}
+		if packs, _ := repo.DisabledPacks(-1); len(packs) != 0 {
+			t.Errorf("packs not cleared: %v", packs)
+		}
+	})
+}
+
+func TestChatHistoryRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, database *db.Db) {
+		repo := NewChatHistoryRepository(database)
+		first := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		later := first.Add(7 * 24 * time.Hour)
+
+		for _, play := range []struct {
+			chat int64
+			code string
+			at   time.Time
+		}{
+			{-1, "classic-cat", first},
+			{-1, "classic-sky", first},
+			{-1, "classic-cat", later},
+			{-2, "classic-cat", first},
+		} {
+			if err := repo.MarkPlayed(play.chat, play.code, play.at); err != nil {
+				t.Fatalf("mark played: %v", err)
+			}
+		}
+
+		played, err := repo.Played(-1)
+		if err != nil || len(played) != 2 || !played["classic-cat"].Equal(later) {
+			t.Fatalf("unexpected history: %v, %v", played, err)
+		}
+		var cat models.ChatTaskPlay
+		database.First(&cat, "chat_id = ? AND task_code = ?", -1, "classic-cat")
+		if cat.Plays != 2 {
+			t.Errorf("repeated play must be counted, got %+v", cat)
+		}
+
+		if n, err := repo.ResetHistory(-1); err != nil || n != 2 {
+			t.Fatalf("reset: %d, %v", n, err)
+		}
+		if played, _ := repo.Played(-1); len(played) != 0 {
+			t.Errorf("history not reset: %v", played)
+		}
+		if played, _ := repo.Played(-2); len(played) != 1 {
+			t.Errorf("reset must not touch other chats: %v", played)
+		}
+	})
+}
+
+func TestChatTaskRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, database *db.Db) {
+		repo := NewChatTaskRepository(database)
+
+		var ids []uint
+		for _, text := range []string{"Фото кота соседа", "Фото чайника", "Фото заката"} {
+			task, err := repo.Create(&models.ChatTask{ChatID: -1, Text: text, AuthorID: 2, Status: models.ChatTaskPending})
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			ids = append(ids, task.ID)
+		}
+
+		if ok, err := repo.Decide(ids[0], models.ChatTaskApproved, 1); err != nil || !ok {
+			t.Fatalf("approve: %v, %v", ok, err)
+		}
+		if ok, _ := repo.Decide(ids[0], models.ChatTaskRejected, 1); ok {
+			t.Error("decided task must not be decided again")
+		}
+		repo.Decide(ids[1], models.ChatTaskRejected, 1)
+
+		approved, err := repo.Approved(-1)
+		if err != nil || len(approved) != 1 || approved[0].Text != "Фото кота соседа" || approved[0].DecidedBy != 1 {
+			t.Errorf("unexpected approved tasks: %+v, %v", approved, err)
+		}
+		if approved, _ := repo.Approved(-2); len(approved) != 0 {
+			t.Errorf("tasks leaked to another chat: %+v", approved)
+		}
+
+		// Отклонённое можно предложить снова, ожидающее и одобренное - нет
+		for text, want := range map[string]bool{"Фото кота соседа": true, "Фото заката": true, "Фото чайника": false} {
+			if got, err := repo.HasText(-1, text); err != nil || got != want {
+				t.Errorf("HasText(%q) = %v, %v; want %v", text, got, err, want)
+			}
+		}
+		if _, err := repo.Get(999); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("expected ErrRecordNotFound, got %v", err)
+		}
+	})
+}
+
+func TestTaskSubmissionRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, database *db.Db) {
+		repo := NewTaskSubmissionRepository(database)
+
+		var ids []uint
+		for _, text := range []string{"Фото старого гаджета", "Фото любимой кружки", "Фото вида из окна"} {
+			sub, err := repo.Create(&models.TaskSubmission{Text: text, AuthorID: 7, AuthorName: "@anna", ChatID: -1, Status: models.SubmissionPending})
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			ids = append(ids, sub.ID)
+		}
+
+		if ok, err := repo.SetText(ids[0], "Фото самого старого гаджета"); err != nil || !ok {
+			t.Fatalf("set text: %v, %v", ok, err)
+		}
+		if ok, err := repo.Decide(ids[0], models.SubmissionApproved, 42, "community-1"); err != nil || !ok {
+			t.Fatalf("approve: %v, %v", ok, err)
+		}
+		if ok, _ := repo.Decide(ids[0], models.SubmissionRejected, 42, ""); ok {
+			t.Error("reviewed submission must not be decided again")
+		}
+		if ok, _ := repo.SetText(ids[0], "Другой текст"); ok {
+			t.Error("reviewed submission must not be edited")
+		}
+		repo.Decide(ids[1], models.SubmissionRejected, 42, "")
+
+		got, err := repo.Get(ids[0])
+		if err != nil || got.Text != "Фото самого старого гаджета" || got.Status != models.SubmissionApproved || got.ReviewedBy != 42 || got.TaskCode != "community-1" {
+			t.Errorf("unexpected approved submission: %+v, %v", got, err)
+		}
+
+		pending, err := repo.Pending()
+		if err != nil || len(pending) != 1 || pending[0].ID != ids[2] {
+			t.Errorf("unexpected queue: %+v, %v", pending, err)
+		}
+		if _, err := repo.Get(999); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("expected ErrRecordNotFound, got %v", err)
+		}
+	})
+}
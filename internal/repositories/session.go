@@ -3,6 +3,8 @@ package repositories
 import (
 	"github.com/kiselevos/memento_game_bot/internal/models"
 	"github.com/kiselevos/memento_game_bot/pkg/db"
+
+	"gorm.io/gorm"
 )
 
 type SessionRepositoryInterface interface {
@@ -10,7 +12,7 @@ type SessionRepositoryInterface interface {
 	GetSessionByID(chatID int64) (*models.Session, error)
 	ChangeIsActive(chatID int64) error
 	AddUserToSession(session *models.Session, user *models.User) error
-	AddPhotosCount(chatID int64) error
+	AddPhotosCount(chatID int64, count int) error
 }
 
 type SessionRepository struct {
@@ -67,14 +69,20 @@ func (repo *SessionRepository) AddUserToSession(session *models.Session, user *m
 	return repo.DataBase.Model(session).Association("Users").Append(user)
 }
 
-func (repo *SessionRepository) AddPhotosCount(chatID int64) error {
+// AddPhotosCount - увеличивает счётчик фото последней сессии чата.
+// Счётчики пишутся с задержкой, поэтому игра к этому моменту может быть уже закрыта.
+func (repo *SessionRepository) AddPhotosCount(chatID int64, count int) error {
+
+	latest := repo.DataBase.
+		Model(&models.Session{}).
+		Select("MAX(id)").
+		Where("chat_id = ?", chatID)
+
+	result := repo.DataBase.
+		Model(&models.Session{}).
+		Where("id = (?)", latest).
+		UpdateColumn("photos_count", gorm.Expr("photos_count + ?", count))
 
-	session, err := repo.GetSessionByID(chatID)
-	if err != nil {
-		return err
-	}
-	session.PhotosCount++
-	result := repo.DataBase.Save(session)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/pkg/db"
+
+	"gorm.io/gorm/clause"
+)
+
+type SnapshotRepositoryInterface interface {
+	Save(snapshot *models.GameSnapshot) error
+	Delete(chatID int64) error
+	GetAll() ([]*models.GameSnapshot, error)
+}
+
+type SnapshotRepository struct {
+	DataBase *db.Db
+}
+
+func NewSnapshotRepository(db *db.Db) *SnapshotRepository {
+	return &SnapshotRepository{
+		DataBase: db,
+	}
+}
+
+// Save - создаёт или перезаписывает снимок игры в чате
+func (repo *SnapshotRepository) Save(snapshot *models.GameSnapshot) error {
+	result := repo.DataBase.
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(snapshot)
+	return result.Error
+}
+
+func (repo *SnapshotRepository) Delete(chatID int64) error {
+	result := repo.DataBase.Delete(&models.GameSnapshot{}, "chat_id = ?", chatID)
+	return result.Error
+}
+
+func (repo *SnapshotRepository) GetAll() ([]*models.GameSnapshot, error) {
+	var snapshots []*models.GameSnapshot
+	result := repo.DataBase.Find(&snapshots)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return snapshots, nil
+}
@@ -7,8 +7,19 @@ import (
 	"github.com/kiselevos/memento_game_bot/pkg/db"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+type TaskRepositoryInterface interface {
+	Create(task *models.Task) (*models.Task, error)
+	GetTaskByCode(code string) (*models.Task, error)
+	GetAll() ([]models.Task, error)
+	Update(task *models.Task) error
+	GetPacks() ([]models.TaskPack, error)
+	SavePack(pack *models.TaskPack) error
+	AddTaskStats(code string, use, skip int) error
+}
+
 type TaskRepository struct {
 	DataBase *db.Db
 }
@@ -27,38 +38,66 @@ func (repo *TaskRepository) Create(task *models.Task) (*models.Task, error) {
 	return task, nil
 }
 
-func (repo *TaskRepository) GetTaskByText(text string) (*models.Task, error) {
+func (repo *TaskRepository) GetTaskByCode(code string) (*models.Task, error) {
 
 	var task models.Task
-	result := repo.DataBase.DB.First(&task, "text = ?", text)
+	result := repo.DataBase.DB.First(&task, "code = ?", code)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return &task, nil
 }
 
-func (repo *TaskRepository) AddUseCount(text string) error {
+// GetAll - все задания каталога, включая выключенные и архивные
+func (repo *TaskRepository) GetAll() ([]models.Task, error) {
+	var tasks []models.Task
+	result := repo.DataBase.Order("id").Find(&tasks)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tasks, nil
+}
 
-	result := repo.DataBase.
-		Model(&models.Task{}).
-		Where("text = ?", text).
-		UpdateColumn("use_count", gorm.Expr("use_count + ?", 1))
+// Update - сохраняет задание целиком, кроме счётчиков: их меняет только статистика
+func (repo *TaskRepository) Update(task *models.Task) error {
+	result := repo.DataBase.Model(task).Select("*").Omit("id", "created_at", "deleted_at", "use_count", "skip_count").Updates(task)
+	return result.Error
+}
 
+// GetPacks - паки каталога
+func (repo *TaskRepository) GetPacks() ([]models.TaskPack, error) {
+	var packs []models.TaskPack
+	result := repo.DataBase.Order("id").Find(&packs)
 	if result.Error != nil {
-		return fmt.Errorf("Ошибка уввеличения use_count: %v", result.Error)
+		return nil, result.Error
 	}
+	return packs, nil
+}
 
-	return nil
+// SavePack - создаёт пак или обновляет его название
+func (repo *TaskRepository) SavePack(pack *models.TaskPack) error {
+	result := repo.DataBase.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"titles", "updated_at"}),
+		}).
+		Create(pack)
+	return result.Error
 }
 
-func (repo *TaskRepository) AddSkipCount(text string) error {
+// AddTaskStats - увеличивает счётчики использований и пропусков задания
+func (repo *TaskRepository) AddTaskStats(code string, use, skip int) error {
+
 	result := repo.DataBase.
 		Model(&models.Task{}).
-		Where("text = ?", text).
-		UpdateColumn("skip_count", gorm.Expr("skip_count + ?", 1))
+		Where("code = ?", code).
+		UpdateColumns(map[string]interface{}{
+			"use_count":  gorm.Expr("use_count + ?", use),
+			"skip_count": gorm.Expr("skip_count + ?", skip),
+		})
 
 	if result.Error != nil {
-		return fmt.Errorf("Ошибка уввеличения skip_count: %v", result.Error)
+		return fmt.Errorf("Ошибка увеличения статистики задания: %v", result.Error)
 	}
 
 	return nil
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/pkg/db"
+)
+
+type TaskSubmissionRepositoryInterface interface {
+	Create(sub *models.TaskSubmission) (*models.TaskSubmission, error)
+	Get(id uint) (*models.TaskSubmission, error)
+	Pending() ([]models.TaskSubmission, error)
+	SetText(id uint, text string) (bool, error)
+	Decide(id uint, status string, reviewedBy int64, taskCode string) (bool, error)
+}
+
+type TaskSubmissionRepository struct {
+	DataBase *db.Db
+}
+
+func NewTaskSubmissionRepository(db *db.Db) *TaskSubmissionRepository {
+	return &TaskSubmissionRepository{
+		DataBase: db,
+	}
+}
+
+func (repo *TaskSubmissionRepository) Create(sub *models.TaskSubmission) (*models.TaskSubmission, error) {
+	result := repo.DataBase.Create(sub)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return sub, nil
+}
+
+func (repo *TaskSubmissionRepository) Get(id uint) (*models.TaskSubmission, error) {
+	var sub models.TaskSubmission
+	result := repo.DataBase.First(&sub, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &sub, nil
+}
+
+// Pending - очередь модерации, старые заявки первыми
+func (repo *TaskSubmissionRepository) Pending() ([]models.TaskSubmission, error) {
+	var res []models.TaskSubmission
+	result := repo.DataBase.Where("status = ?", models.SubmissionPending).Order("id").Find(&res)
+	return res, result.Error
+}
+
+// SetText - правка текста до решения. false - заявку уже рассмотрели.
+func (repo *TaskSubmissionRepository) SetText(id uint, text string) (bool, error) {
+	result := repo.DataBase.
+		Model(&models.TaskSubmission{}).
+		Where("id = ? AND status = ?", id, models.SubmissionPending).
+		Update("text", text)
+	return result.RowsAffected == 1, result.Error
+}
+
+// Decide - одобряет или отклоняет заявку. false - её уже рассмотрел другой владелец.
+func (repo *TaskSubmissionRepository) Decide(id uint, status string, reviewedBy int64, taskCode string) (bool, error) {
+	result := repo.DataBase.
+		Model(&models.TaskSubmission{}).
+		Where("id = ? AND status = ?", id, models.SubmissionPending).
+		Updates(map[string]interface{}{"status": status, "reviewed_by": reviewedBy, "task_code": taskCode})
+	return result.RowsAffected == 1, result.Error
+}
@@ -1,16 +1,20 @@
 package repositories
 
 import (
+	"fmt"
+
 	"github.com/kiselevos/memento_game_bot/internal/models"
 	"github.com/kiselevos/memento_game_bot/pkg/db"
-)
 
-const (
-	StatGame  = "game"
-	StatVote  = "vote"
-	StatPhoto = "photo"
+	"gorm.io/gorm"
 )
 
+type UserRepositoryInterface interface {
+	Create(user *models.User) (*models.User, error)
+	GetUserByTGID(id int64) (*models.User, error)
+	AddUserStatistics(userID int64, games, photos, votes int) error
+}
+
 type UserRepository struct {
 	DataBase *db.Db
 }
@@ -39,26 +43,21 @@ func (repo *UserRepository) GetUserByTGID(id int64) (*models.User, error) {
 	return &user, nil
 }
 
-// AddUserStatistic - единая функция для увеличения показателей
-func (repo *UserRepository) AddUserStatistic(userID int64, flag string) error {
+// AddUserStatistics - единая функция для увеличения показателей. Приращения
+// накапливаются пачкой и записываются одним UPDATE.
+func (repo *UserRepository) AddUserStatistics(userID int64, games, photos, votes int) error {
 
-	user, err := repo.GetUserByTGID(userID)
-	if err != nil {
-		return err
-	}
-
-	switch flag {
-	case StatVote:
-		user.UsersVote++
-	case StatGame:
-		user.GamesPlayed++
-	case StatPhoto:
-		user.PhotosSent++
-	}
+	result := repo.DataBase.
+		Model(&models.User{}).
+		Where("tg_user_id = ?", userID).
+		UpdateColumns(map[string]interface{}{
+			"games_played": gorm.Expr("games_played + ?", games),
+			"photos_sent":  gorm.Expr("photos_sent + ?", photos),
+			"users_vote":   gorm.Expr("users_vote + ?", votes),
+		})
 
-	result := repo.DataBase.Save(user)
 	if result.Error != nil {
-		return result.Error
+		return fmt.Errorf("Ошибка увеличения статистики пользователя: %v", result.Error)
 	}
 
 	return nil
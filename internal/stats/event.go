@@ -0,0 +1,33 @@
+package stats
+
+// Kind - какой счётчик увеличивается
+type Kind string
+
+const (
+	UserGame     Kind = "user_game"     // Участник сыграл в игре
+	UserPhoto    Kind = "user_photo"    // Участник прислал фото
+	UserVote     Kind = "user_vote"     // Участник проголосовал
+	SessionPhoto Kind = "session_photo" // В сессии чата сыграно фото
+	TaskUse      Kind = "task_use"      // На задание прислали фото
+	TaskSkip     Kind = "task_skip"     // Задание пропустили
+)
+
+// Event - одно приращение статистики
+type Event struct {
+	Kind   Kind
+	UserID int64
+	ChatID int64
+	Task   string
+}
+
+// Recorder - приёмник статистики. Record не должен блокировать игру.
+type Recorder interface {
+	Record(e Event)
+}
+
+// Store - хранилище, в которое сбрасываются накопленные счётчики
+type Store interface {
+	AddUserStatistics(userID int64, games, photos, votes int) error
+	AddPhotosCount(chatID int64, count int) error
+	AddTaskStats(task string, use, skip int) error
+}
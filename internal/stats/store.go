@@ -0,0 +1,26 @@
+package stats
+
+// RepoStore - Store поверх репозиториев пользователей, сессий и заданий
+type RepoStore struct {
+	Users interface {
+		AddUserStatistics(userID int64, games, photos, votes int) error
+	}
+	Sessions interface {
+		AddPhotosCount(chatID int64, count int) error
+	}
+	Tasks interface {
+		AddTaskStats(task string, use, skip int) error
+	}
+}
+
+func (s *RepoStore) AddUserStatistics(userID int64, games, photos, votes int) error {
+	return s.Users.AddUserStatistics(userID, games, photos, votes)
+}
+
+func (s *RepoStore) AddPhotosCount(chatID int64, count int) error {
+	return s.Sessions.AddPhotosCount(chatID, count)
+}
+
+func (s *RepoStore) AddTaskStats(task string, use, skip int) error {
+	return s.Tasks.AddTaskStats(task, use, skip)
+}
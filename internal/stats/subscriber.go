@@ -0,0 +1,28 @@
+package stats
+
+import "github.com/kiselevos/memento_game_bot/internal/events"
+
+// FromEvents - подписчик шины событий, превращающий игровые события в счётчики
+func FromEvents(rec Recorder) events.Handler {
+	return func(e events.Event) {
+		switch e := e.(type) {
+		case events.RoundStarted:
+			if e.PrevTask == "" {
+				return
+			}
+			if e.PrevPhotos > 0 {
+				rec.Record(Event{Kind: TaskUse, ChatID: e.ChatID, Task: e.PrevTask})
+			} else {
+				rec.Record(Event{Kind: TaskSkip, ChatID: e.ChatID, Task: e.PrevTask})
+			}
+		case events.PhotoSubmitted:
+			if e.NewPlayer {
+				rec.Record(Event{Kind: UserGame, ChatID: e.ChatID, UserID: e.UserID})
+			}
+			rec.Record(Event{Kind: SessionPhoto, ChatID: e.ChatID})
+			rec.Record(Event{Kind: UserPhoto, ChatID: e.ChatID, UserID: e.UserID})
+		case events.VoteCast:
+			rec.Record(Event{Kind: UserVote, ChatID: e.ChatID, UserID: e.VoterID})
+		}
+	}
+}
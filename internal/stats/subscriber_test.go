@@ -0,0 +1,44 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/kiselevos/memento_game_bot/internal/events"
+)
+
+type recorded []Event
+
+func (r *recorded) Record(e Event) { *r = append(*r, e) }
+
+func TestFromEvents(t *testing.T) {
+	var rec recorded
+	handle := FromEvents(&rec)
+
+	handle(events.GameStarted{ChatID: 1})
+	handle(events.RoundStarted{ChatID: 1, Task: "first"})
+	handle(events.PhotoSubmitted{ChatID: 1, UserID: 10, NewPlayer: true})
+	handle(events.PhotoSubmitted{ChatID: 1, UserID: 10})
+	handle(events.VoteCast{ChatID: 1, VoterID: 11, TargetID: 10})
+	handle(events.RoundStarted{ChatID: 1, Task: "second", PrevTask: "first", PrevPhotos: 2})
+	handle(events.RoundStarted{ChatID: 1, Task: "third", PrevTask: "second"})
+
+	want := []Event{
+		{Kind: UserGame, ChatID: 1, UserID: 10},
+		{Kind: SessionPhoto, ChatID: 1},
+		{Kind: UserPhoto, ChatID: 1, UserID: 10},
+		{Kind: SessionPhoto, ChatID: 1},
+		{Kind: UserPhoto, ChatID: 1, UserID: 10},
+		{Kind: UserVote, ChatID: 1, UserID: 11},
+		{Kind: TaskUse, ChatID: 1, Task: "first"},
+		{Kind: TaskSkip, ChatID: 1, Task: "second"},
+	}
+
+	if len(rec) != len(want) {
+		t.Fatalf("Expected %d events, got %d: %+v", len(want), len(rec), rec)
+	}
+	for i := range want {
+		if rec[i] != want[i] {
+			t.Errorf("Event %d: expected %+v, got %+v", i, want[i], rec[i])
+		}
+	}
+}
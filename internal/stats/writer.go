@@ -0,0 +1,200 @@
+package stats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/logging"
+	"github.com/kiselevos/memento_game_bot/internal/metrics"
+)
+
+var logger = logging.Component("stats")
+
+// Сколько раз пытаться сбросить накопленное при остановке, прежде чем сдаться
+const closeAttempts = 3
+
+type userDelta struct {
+	games, photos, votes int
+}
+
+type taskDelta struct {
+	use, skip int
+}
+
+// batch - накопленные приращения, сгруппированные по пользователю, сессии и заданию
+type batch struct {
+	users    map[int64]*userDelta
+	sessions map[int64]int
+	tasks    map[string]*taskDelta
+}
+
+func newBatch() *batch {
+	return &batch{
+		users:    make(map[int64]*userDelta),
+		sessions: make(map[int64]int),
+		tasks:    make(map[string]*taskDelta),
+	}
+}
+
+func (b *batch) empty() bool {
+	return len(b.users) == 0 && len(b.sessions) == 0 && len(b.tasks) == 0
+}
+
+func (b *batch) add(e Event) {
+	switch e.Kind {
+	case UserGame, UserPhoto, UserVote:
+		d, ok := b.users[e.UserID]
+		if !ok {
+			d = &userDelta{}
+			b.users[e.UserID] = d
+		}
+		switch e.Kind {
+		case UserGame:
+			d.games++
+		case UserPhoto:
+			d.photos++
+		case UserVote:
+			d.votes++
+		}
+	case SessionPhoto:
+		b.sessions[e.ChatID]++
+	case TaskUse, TaskSkip:
+		d, ok := b.tasks[e.Task]
+		if !ok {
+			d = &taskDelta{}
+			b.tasks[e.Task] = d
+		}
+		if e.Kind == TaskUse {
+			d.use++
+		} else {
+			d.skip++
+		}
+	default:
+		logger.Warn("Неизвестный тип статистики", "kind", e.Kind)
+	}
+}
+
+// Writer - асинхронная запись статистики. События копятся в буфере,
+// раз в interval сбрасываются в Store пачкой. Неудачные записи остаются
+// в очереди и повторяются при следующем сбросе.
+type Writer struct {
+	store    Store
+	events   chan Event
+	interval time.Duration
+	metrics  *metrics.Metrics // nil - без метрик
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewWriter - создаёт и запускает writer. Каждая неудачная запись в Store
+// засчитывается в m как ошибка БД stats_flush.
+func NewWriter(store Store, bufferSize int, interval time.Duration, m *metrics.Metrics) *Writer {
+	w := &Writer{
+		store:    store,
+		events:   make(chan Event, bufferSize),
+		interval: interval,
+		metrics:  m,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	go w.run()
+
+	return w
+}
+
+// Record - ставит событие в очередь. Никогда не блокирует: при переполненном буфере событие теряется.
+func (w *Writer) Record(e Event) {
+	select {
+	case <-w.stop:
+		logger.Warn("Событие после остановки записи статистики отброшено", "kind", e.Kind)
+	case w.events <- e:
+	default:
+		logger.Warn("Буфер статистики переполнен, событие отброшено", "kind", e.Kind)
+	}
+}
+
+// Close - останавливает writer и сбрасывает всё накопленное
+func (w *Writer) Close() {
+	w.once.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	pending := newBatch()
+
+	for {
+		select {
+		case e := <-w.events:
+			pending.add(e)
+		case <-ticker.C:
+			pending = w.flush(pending)
+		case <-w.stop:
+			// Дочитываем всё, что успели положить в буфер
+			for drained := false; !drained; {
+				select {
+				case e := <-w.events:
+					pending.add(e)
+				default:
+					drained = true
+				}
+			}
+
+			for attempt := 1; attempt <= closeAttempts && !pending.empty(); attempt++ {
+				pending = w.flush(pending)
+				if !pending.empty() {
+					time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+				}
+			}
+			if !pending.empty() {
+				logger.Error("При остановке не удалось записать часть статистики",
+					"users", len(pending.users), "sessions", len(pending.sessions), "tasks", len(pending.tasks))
+			}
+			return
+		}
+	}
+}
+
+// flush - пишет пачку в Store и возвращает то, что записать не удалось
+func (w *Writer) flush(b *batch) *batch {
+	if b.empty() {
+		return b
+	}
+
+	failed := newBatch()
+
+	for userID, d := range b.users {
+		if err := w.store.AddUserStatistics(userID, d.games, d.photos, d.votes); err != nil {
+			logger.Error("Не удалось записать статистику пользователя", "user_id", userID, "err", err)
+			failed.users[userID] = d
+			w.metrics.DBError("stats_flush")
+		}
+	}
+
+	for chatID, count := range b.sessions {
+		if err := w.store.AddPhotosCount(chatID, count); err != nil {
+			logger.Error("Не удалось увеличить PhotosCount", "chat_id", chatID, "err", err)
+			failed.sessions[chatID] = count
+			w.metrics.DBError("stats_flush")
+		}
+	}
+
+	for task, d := range b.tasks {
+		if err := w.store.AddTaskStats(task, d.use, d.skip); err != nil {
+			logger.Error("Не удалось записать статистику задания", "task", task, "err", err)
+			failed.tasks[task] = d
+			w.metrics.DBError("stats_flush")
+		}
+	}
+
+	return failed
+}
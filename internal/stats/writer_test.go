@@ -0,0 +1,179 @@
+package stats
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type userCounts struct{ games, photos, votes int }
+
+// fakeStore - Store в памяти, умеет падать заданное число раз
+type fakeStore struct {
+	mu       sync.Mutex
+	failLeft int
+	calls    int
+
+	users    map[int64]userCounts
+	sessions map[int64]int
+	uses     map[string]int
+	skips    map[string]int
+}
+
+func newFakeStore(failures int) *fakeStore {
+	return &fakeStore{
+		failLeft: failures,
+		users:    make(map[int64]userCounts),
+		sessions: make(map[int64]int),
+		uses:     make(map[string]int),
+		skips:    make(map[string]int),
+	}
+}
+
+func (f *fakeStore) fail() bool {
+	f.calls++
+	if f.failLeft > 0 {
+		f.failLeft--
+		return true
+	}
+	return false
+}
+
+func (f *fakeStore) AddUserStatistics(userID int64, games, photos, votes int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail() {
+		return errors.New("db is down")
+	}
+	c := f.users[userID]
+	f.users[userID] = userCounts{c.games + games, c.photos + photos, c.votes + votes}
+	return nil
+}
+
+func (f *fakeStore) AddPhotosCount(chatID int64, count int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail() {
+		return errors.New("db is down")
+	}
+	f.sessions[chatID] += count
+	return nil
+}
+
+func (f *fakeStore) AddTaskStats(task string, use, skip int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail() {
+		return errors.New("db is down")
+	}
+	f.uses[task] += use
+	f.skips[task] += skip
+	return nil
+}
+
+func TestWriterBatchesPerKey(t *testing.T) {
+	store := newFakeStore(0)
+	w := NewWriter(store, 100, time.Hour, nil)
+
+	for i := 0; i < 3; i++ {
+		w.Record(Event{Kind: UserPhoto, UserID: 1, ChatID: 10})
+		w.Record(Event{Kind: SessionPhoto, ChatID: 10})
+	}
+	w.Record(Event{Kind: UserVote, UserID: 1})
+	w.Record(Event{Kind: UserGame, UserID: 2})
+	w.Record(Event{Kind: TaskUse, Task: "task"})
+	w.Record(Event{Kind: TaskSkip, Task: "task"})
+	w.Record(Event{Kind: TaskUse, Task: "task"})
+
+	w.Close()
+
+	if got := store.users[1]; got != (userCounts{0, 3, 1}) {
+		t.Errorf("Unexpected user 1 counters: %+v", got)
+	}
+	if got := store.users[2]; got != (userCounts{1, 0, 0}) {
+		t.Errorf("Unexpected user 2 counters: %+v", got)
+	}
+	if store.sessions[10] != 3 {
+		t.Errorf("Expected 3 session photos, got %d", store.sessions[10])
+	}
+	if store.uses["task"] != 2 || store.skips["task"] != 1 {
+		t.Errorf("Unexpected task counters: use %d skip %d", store.uses["task"], store.skips["task"])
+	}
+	// Одна запись на ключ, а не на событие
+	if store.calls != 4 {
+		t.Errorf("Expected 4 batched writes, got %d", store.calls)
+	}
+}
+
+func TestWriterFlushesPeriodically(t *testing.T) {
+	store := newFakeStore(0)
+	w := NewWriter(store, 100, 10*time.Millisecond, nil)
+	defer w.Close()
+
+	w.Record(Event{Kind: SessionPhoto, ChatID: 5})
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		store.mu.Lock()
+		n := store.sessions[5]
+		store.mu.Unlock()
+		if n == 1 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("Periodic flush did not happen")
+}
+
+func TestWriterRetriesFailedWrites(t *testing.T) {
+	store := newFakeStore(2)
+	w := NewWriter(store, 100, time.Hour, nil)
+
+	w.Record(Event{Kind: UserPhoto, UserID: 1})
+	w.Close()
+
+	if got := store.users[1]; got.photos != 1 {
+		t.Errorf("Expected failed write to be retried on close, got %+v", got)
+	}
+}
+
+func TestWriterCountsFailedWrites(t *testing.T) {
+	store := newFakeStore(2)
+	m := metrics.New()
+	w := NewWriter(store, 100, time.Hour, m)
+
+	w.Record(Event{Kind: UserPhoto, UserID: 1})
+	w.Record(Event{Kind: SessionPhoto, ChatID: 10})
+	w.Close()
+
+	if got := testutil.ToFloat64(m.DBErrors.WithLabelValues("stats_flush")); got != 2 {
+		t.Errorf("Expected 2 failed writes in db_errors_total, got %v", got)
+	}
+}
+
+func TestWriterRecordNeverBlocks(t *testing.T) {
+	store := newFakeStore(0)
+	w := NewWriter(store, 1, time.Hour, nil)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 1000; i++ {
+			w.Record(Event{Kind: UserVote, UserID: 1})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Record blocked on a full buffer")
+	}
+
+	w.Close()
+	w.Record(Event{Kind: UserVote, UserID: 1}) // после Close не паникует и не блокирует
+}
@@ -0,0 +1,571 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/logging"
+	"github.com/kiselevos/memento_game_bot/internal/models"
+)
+
+var logger = logging.Component("tasks")
+
+var (
+	ErrTaskNotFound  = errors.New("task not found")
+	ErrDuplicateText = errors.New("task with this text already exists")
+	ErrUnknownField  = errors.New("unknown field")
+	ErrBadValue      = errors.New("bad value")
+)
+
+// EditableFields - что админ может менять в задании: тексты на языках и метаданные
+func EditableFields() []string {
+	fields := make([]string, 0, len(i18n.Supported())+5)
+	for _, lang := range i18n.Supported() {
+		fields = append(fields, string(lang))
+	}
+	return append(fields, "pack", "category", "tags", "blitz", "rating")
+}
+
+// Store - хранилище каталога (repositories.TaskRepository)
+type Store interface {
+	Create(task *models.Task) (*models.Task, error)
+	GetAll() ([]models.Task, error)
+	Update(task *models.Task) error
+	GetPacks() ([]models.TaskPack, error)
+	SavePack(pack *models.TaskPack) error
+}
+
+// Entry - задание каталога вместе с состоянием, для списка у админа
+type Entry struct {
+	Task
+	Source   string
+	Disabled bool
+	Archived bool
+}
+
+// Catalog - задания в БД. Импорт паков и правки админов сразу
+// попадают в TasksList, из которого берутся задания раундов.
+type Catalog struct {
+	store Store
+	list  *TasksList
+	mu    sync.Mutex // сериализует правки
+}
+
+// NewCatalog - загружает каталог из БД
+func NewCatalog(store Store) (*Catalog, error) {
+	c := &Catalog{
+		store: store,
+		list:  newTasksList(nil),
+	}
+	if err := c.Reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// List - задания для раундов: включённые и не архивные
+func (c *Catalog) List() *TasksList {
+	return c.list
+}
+
+// RefreshEvery - периодически перечитывает каталог, чтобы выбор заданий
+// видел свежую статистику. stop останавливает обновление.
+func (c *Catalog) RefreshEvery(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.mu.Lock()
+				err := c.Reload()
+				c.mu.Unlock()
+				if err != nil {
+					logger.Error("Не удалось обновить каталог заданий", "err", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// Reload - перечитывает каталог из БД
+func (c *Catalog) Reload() error {
+	rows, err := c.store.GetAll()
+	if err != nil {
+		return err
+	}
+	packRows, err := c.store.GetPacks()
+	if err != nil {
+		return err
+	}
+
+	packs := make(map[string]*Pack)
+	var order []string
+	for _, row := range packRows {
+		pack := &Pack{ID: row.ID}
+		if err := unmarshalTexts(row.Titles, &pack.Title); err != nil {
+			return fmt.Errorf("pack %q: %w", row.ID, err)
+		}
+		packs[row.ID] = pack
+		order = append(order, row.ID)
+	}
+
+	for _, row := range rows {
+		if row.Code == "" || row.Disabled || row.Archived {
+			continue
+		}
+		task, err := fromModel(row)
+		if err != nil {
+			// Одна битая строка не должна останавливать бота
+			logger.Error("Задание каталога пропущено", "task", row.Code, "err", err)
+			continue
+		}
+		pack, ok := packs[task.Pack]
+		if !ok {
+			pack = &Pack{ID: task.Pack}
+			packs[task.Pack] = pack
+			order = append(order, task.Pack)
+		}
+		pack.Tasks = append(pack.Tasks, task)
+	}
+
+	sort.Strings(order)
+	res := make([]*Pack, 0, len(order))
+	for _, id := range order {
+		// Пак без включённых заданий в раундах и /packs не нужен
+		if len(packs[id].Tasks) > 0 {
+			res = append(res, packs[id])
+		}
+	}
+
+	c.list.replace(res)
+	return nil
+}
+
+// ImportResult - что изменил импорт паков
+type ImportResult struct {
+	Added    int // новые задания
+	Updated  int // изменённые в файле
+	Adopted  int // старые строки без ID, найденные по тексту
+	Archived int // пропавшие из файлов
+	Skipped  int // изменены админом, файл их не трогает
+}
+
+// Import - синхронизирует каталог с паками из файлов. Задания сопоставляются
+// по ID; правки админов не затираются, пропавшие из файлов задания уходят в архив
+// (статистика по ним сохраняется).
+func (c *Catalog) Import(packs []*Pack) (ImportResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var res ImportResult
+
+	rows, err := c.store.GetAll()
+	if err != nil {
+		return res, err
+	}
+	byCode := make(map[string]*models.Task, len(rows))
+	byText := make(map[string]*models.Task, len(rows))
+	for i := range rows {
+		if rows[i].Code != "" {
+			byCode[rows[i].Code] = &rows[i]
+		} else {
+			byText[rows[i].Text] = &rows[i]
+		}
+	}
+
+	var errs []error
+	seen := make(map[string]bool)
+	for _, pack := range packs {
+		titles, err := json.Marshal(pack.Title)
+		if err != nil {
+			return res, err
+		}
+		if err := c.store.SavePack(&models.TaskPack{ID: pack.ID, Titles: string(titles)}); err != nil {
+			return res, fmt.Errorf("pack %q: %w", pack.ID, err)
+		}
+
+		for _, task := range pack.Tasks {
+			seen[task.ID] = true
+			fresh, err := toModel(task)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("task %q: %w", task.ID, err))
+				continue
+			}
+			fresh.Source = models.TaskSourceFile
+
+			row, ok := byCode[task.ID]
+			if !ok {
+				row, ok = byText[fresh.Text]
+				if ok {
+					res.Adopted++
+				}
+			}
+			switch {
+			case !ok:
+				if _, err := c.store.Create(fresh); err != nil {
+					errs = append(errs, fmt.Errorf("task %q: %w", task.ID, err))
+					continue
+				}
+				res.Added++
+			case row.Source == models.TaskSourceAdmin:
+				res.Skipped++
+			default:
+				if row.Code != "" && sameFileFields(row, fresh) && !row.Archived {
+					continue
+				}
+				fresh.Model = row.Model
+				fresh.Disabled = row.Disabled
+				if err := c.store.Update(fresh); err != nil {
+					errs = append(errs, fmt.Errorf("task %q: %w", task.ID, err))
+					continue
+				}
+				if row.Code != "" {
+					res.Updated++
+				}
+			}
+		}
+	}
+
+	for _, row := range rows {
+		if row.Code == "" || seen[row.Code] || row.Archived || row.Source == models.TaskSourceAdmin {
+			continue
+		}
+		row.Archived = true
+		if err := c.store.Update(&row); err != nil {
+			errs = append(errs, fmt.Errorf("task %q: %w", row.Code, err))
+			continue
+		}
+		res.Archived++
+	}
+
+	if err := c.Reload(); err != nil {
+		errs = append(errs, err)
+	}
+	return res, errors.Join(errs...)
+}
+
+func sameFileFields(a, b *models.Task) bool {
+	return a.Code == b.Code && a.Pack == b.Pack && a.Text == b.Text && a.Texts == b.Texts &&
+		a.Category == b.Category && a.Tags == b.Tags && a.Blitz == b.Blitz && a.Rating == b.Rating &&
+		a.Author == b.Author
+}
+
+// Entries - все задания пака (или всех паков) для админа, с выключенными и архивными
+func (c *Catalog) Entries(pack string) ([]Entry, error) {
+	rows, err := c.store.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Entry
+	for _, row := range rows {
+		if row.Code == "" || (pack != "" && row.Pack != pack) {
+			continue
+		}
+		task, err := fromModel(row)
+		if err != nil {
+			return nil, fmt.Errorf("task %q: %w", row.Code, err)
+		}
+		res = append(res, Entry{
+			Task:     task,
+			Source:   row.Source,
+			Disabled: row.Disabled,
+			Archived: row.Archived,
+		})
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Pack < res[j].Pack })
+	return res, nil
+}
+
+// Add - новое задание от админа. Текст - на языке по умолчанию, пак создаётся
+// при необходимости, author - автор одобренной заявки или пусто.
+func (c *Catalog) Add(pack, text, author string) (Task, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pack = strings.ToLower(strings.TrimSpace(pack))
+	text = strings.TrimSpace(text)
+	if !validID(pack) || text == "" {
+		return Task{}, ErrBadValue
+	}
+
+	rows, err := c.store.GetAll()
+	if err != nil {
+		return Task{}, err
+	}
+	for _, row := range rows {
+		if row.Text == text {
+			return Task{}, fmt.Errorf("%w: %s", ErrDuplicateText, row.Code)
+		}
+	}
+
+	if err := c.ensurePack(pack); err != nil {
+		return Task{}, err
+	}
+
+	task := Task{
+		ID:       nextID(pack, rows),
+		Text:     map[i18n.Lang]string{i18n.Default: text},
+		Category: "custom",
+		Rating:   RatingGeneral,
+		Author:   author,
+		Pack:     pack,
+	}
+	row, err := toModel(task)
+	if err != nil {
+		return Task{}, err
+	}
+	row.Source = models.TaskSourceAdmin
+	if _, err := c.store.Create(row); err != nil {
+		return Task{}, err
+	}
+
+	return task, c.Reload()
+}
+
+// Edit - меняет одно поле задания. Задание из файла после правки больше не
+// обновляется импортом, чтобы деплой не затёр исправление.
+func (c *Catalog) Edit(id, field, value string) (Task, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	row, rows, err := c.find(id)
+	if err != nil {
+		return Task{}, err
+	}
+	task, err := fromModel(*row)
+	if err != nil {
+		return Task{}, err
+	}
+
+	value = strings.TrimSpace(value)
+	if lang, ok := i18n.Parse(field); ok && string(lang) == strings.ToLower(field) {
+		if value == "" && lang == i18n.Default {
+			return Task{}, ErrBadValue
+		}
+		if value == "" {
+			delete(task.Text, lang)
+		} else {
+			task.Text[lang] = value
+		}
+	} else {
+		switch strings.ToLower(field) {
+		case "pack":
+			value = strings.ToLower(value)
+			if !validID(value) {
+				return Task{}, ErrBadValue
+			}
+			if err := c.ensurePack(value); err != nil {
+				return Task{}, err
+			}
+			task.Pack = value
+		case "category":
+			task.Category = value
+		case "tags":
+			task.Tags = splitList(value)
+		case "blitz":
+			blitz, ok := parseSwitch(value)
+			if !ok {
+				return Task{}, ErrBadValue
+			}
+			task.Blitz = blitz
+		case "rating":
+			switch Rating(strings.ToLower(value)) {
+			case RatingGeneral, RatingMature:
+				task.Rating = Rating(strings.ToLower(value))
+			default:
+				return Task{}, ErrBadValue
+			}
+		default:
+			return Task{}, ErrUnknownField
+		}
+	}
+
+	updated, err := toModel(task)
+	if err != nil {
+		return Task{}, err
+	}
+	updated.Model = row.Model
+	updated.Source = models.TaskSourceAdmin
+	updated.Disabled = row.Disabled
+	updated.Archived = row.Archived
+	for _, other := range rows {
+		if other.Text == updated.Text && other.ID != row.ID {
+			return Task{}, fmt.Errorf("%w: %s", ErrDuplicateText, other.Code)
+		}
+	}
+	if err := c.store.Update(updated); err != nil {
+		return Task{}, err
+	}
+
+	return task, c.Reload()
+}
+
+// SetDisabled - выключает задание или возвращает его в раунды
+func (c *Catalog) SetDisabled(id string, disabled bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	row, _, err := c.find(id)
+	if err != nil {
+		return err
+	}
+	row.Disabled = disabled
+	if err := c.store.Update(row); err != nil {
+		return err
+	}
+	return c.Reload()
+}
+
+// find - строка задания по ID и весь каталог для проверок
+func (c *Catalog) find(id string) (*models.Task, []models.Task, error) {
+	rows, err := c.store.GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range rows {
+		if rows[i].Code == id {
+			row := rows[i]
+			return &row, rows, nil
+		}
+	}
+	return nil, nil, ErrTaskNotFound
+}
+
+// ensurePack - пак, созданный админом, называется по своему ID
+func (c *Catalog) ensurePack(id string) error {
+	packs, err := c.store.GetPacks()
+	if err != nil {
+		return err
+	}
+	for _, pack := range packs {
+		if pack.ID == id {
+			return nil
+		}
+	}
+	title := map[i18n.Lang]string{i18n.Default: id}
+	if id == CommunityPack {
+		title = communityTitle
+	}
+	titles, _ := json.Marshal(title)
+	return c.store.SavePack(&models.TaskPack{ID: id, Titles: string(titles)})
+}
+
+// nextID - следующий свободный ID вида <пак>-<номер>
+func nextID(pack string, rows []models.Task) string {
+	used := make(map[string]bool, len(rows))
+	count := 0
+	for _, row := range rows {
+		used[row.Code] = true
+		if row.Pack == pack {
+			count++
+		}
+	}
+	for n := count + 1; ; n++ {
+		id := pack + "-" + strconv.Itoa(n)
+		if !used[id] {
+			return id
+		}
+	}
+}
+
+func fromModel(row models.Task) (Task, error) {
+	task := Task{
+		ID:       row.Code,
+		Category: row.Category,
+		Tags:     splitList(row.Tags),
+		Blitz:    row.Blitz,
+		Rating:   Rating(row.Rating),
+		Author:   row.Author,
+		Pack:     row.Pack,
+		Uses:     row.UseCount,
+		Skips:    row.SkipCount,
+	}
+	if err := unmarshalTexts(row.Texts, &task.Text); err != nil {
+		return Task{}, err
+	}
+	if task.Text == nil {
+		task.Text = make(map[i18n.Lang]string)
+	}
+	// Строки до каталога знают только текст на языке по умолчанию
+	if task.Text[i18n.Default] == "" {
+		task.Text[i18n.Default] = row.Text
+	}
+	if task.Rating == "" {
+		task.Rating = RatingGeneral
+	}
+	return task, nil
+}
+
+func toModel(task Task) (*models.Task, error) {
+	texts, err := json.Marshal(task.Text)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Task{
+		Code:     task.ID,
+		Pack:     task.Pack,
+		Text:     task.Text[i18n.Default],
+		Texts:    string(texts),
+		Category: task.Category,
+		Tags:     strings.Join(task.Tags, ","),
+		Blitz:    task.Blitz,
+		Rating:   string(task.Rating),
+		Author:   task.Author,
+	}, nil
+}
+
+func unmarshalTexts(raw string, dst *map[i18n.Lang]string) error {
+	if raw == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(raw), dst)
+}
+
+func splitList(raw string) []string {
+	var res []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			res = append(res, part)
+		}
+	}
+	return res
+}
+
+func parseSwitch(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "on", "yes", "true", "1", "да":
+		return true, true
+	case "off", "no", "false", "0", "нет":
+		return false, true
+	}
+	return false, false
+}
+
+// validID - ID пака: латиница, цифры и дефис. ChatPack занят заданиями чатов.
+func validID(id string) bool {
+	if id == "" || len(id) > 32 || id == ChatPack {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package tasks
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/internal/repositories/memory"
+)
+
+func loadTestPacks(t *testing.T, data string) []*Pack {
+	t.Helper()
+	dir := t.TempDir()
+	writePack(t, dir, "a.json", data)
+	packs, err := LoadPacks(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return packs
+}
+
+func TestCatalogImport(t *testing.T) {
+	repo := memory.NewTaskRepo()
+
+	// Строка из времён до паков: без ID, со своей статистикой
+	legacy, _ := repo.Create(&models.Task{Text: "Небо", UseCount: 5})
+
+	catalog, err := NewCatalog(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(catalog.List().Packs()) != 0 {
+		t.Fatal("rows without ID must not be served")
+	}
+
+	res, err := catalog.Import(loadTestPacks(t, `{"id": "a", "title": {"ru": "Пак"}, "tasks": [
+		{"id": "a-1", "text": {"ru": "Кот", "en": "Cat"}, "tags": ["pets"]},
+		{"id": "a-2", "text": {"ru": "Небо"}},
+		{"id": "a-3", "text": {"ru": "Пёс"}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != (ImportResult{Added: 2, Adopted: 1}) {
+		t.Errorf("unexpected first import: %+v", res)
+	}
+	sky, _ := repo.GetTaskByCode("a-2")
+	if sky == nil || sky.ID != legacy.ID || sky.UseCount != 5 {
+		t.Errorf("legacy row must get the ID and keep stats: %+v", sky)
+	}
+	if served, _ := catalog.List().Task("a-2"); served.Uses != 5 {
+		t.Errorf("stats must reach task selection: %+v", served)
+	}
+	cat, ok := catalog.List().Task("a-1")
+	if !ok || cat.TextFor(i18n.EN) != "Cat" || len(cat.Tags) != 1 || catalog.List().Packs()[0].TitleFor(i18n.RU) != "Пак" {
+		t.Errorf("catalog must serve imported tasks: %+v", cat)
+	}
+
+	// Правка админа переживает импорт, пропавшее из файла уходит в архив
+	if _, err := catalog.Edit("a-1", "ru", "Кошка"); err != nil {
+		t.Fatal(err)
+	}
+	res, err = catalog.Import(loadTestPacks(t, `{"id": "a", "tasks": [
+		{"id": "a-1", "text": {"ru": "Кот"}},
+		{"id": "a-2", "text": {"ru": "Закат"}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != (ImportResult{Updated: 1, Archived: 1, Skipped: 1}) {
+		t.Errorf("unexpected second import: %+v", res)
+	}
+	if cat, _ := catalog.List().Task("a-1"); cat.TextFor(i18n.RU) != "Кошка" {
+		t.Errorf("admin edit overwritten by import: %+v", cat)
+	}
+	if _, ok := catalog.List().Task("a-3"); ok {
+		t.Error("archived task must not be served")
+	}
+
+	// Задание вернулось в файл - вернулось и в раунды
+	res, err = catalog.Import(loadTestPacks(t, `{"id": "a", "tasks": [
+		{"id": "a-2", "text": {"ru": "Закат"}},
+		{"id": "a-3", "text": {"ru": "Пёс"}}
+	]}`))
+	if err != nil || res.Updated != 1 {
+		t.Errorf("archived task must be restored: %+v, %v", res, err)
+	}
+	if _, ok := catalog.List().Task("a-3"); !ok {
+		t.Error("restored task must be served")
+	}
+}
+
+func TestCatalogAdminEdits(t *testing.T) {
+	catalog, err := NewCatalog(memory.NewTaskRepo())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := catalog.Add("Party", "Фото с вечеринки", "")
+	if err != nil || task.ID != "party-1" {
+		t.Fatalf("unexpected add: %+v, %v", task, err)
+	}
+	if _, err := catalog.Add("party", "Фото с вечеринки", ""); !errors.Is(err, ErrDuplicateText) {
+		t.Errorf("expected ErrDuplicateText, got %v", err)
+	}
+	if _, err := catalog.Add("bad pack!", "Текст", ""); !errors.Is(err, ErrBadValue) {
+		t.Errorf("expected ErrBadValue, got %v", err)
+	}
+	if packs := catalog.List().Packs(); len(packs) != 1 || packs[0].TitleFor(i18n.EN) != "party" {
+		t.Errorf("new pack must be created and served: %+v", packs)
+	}
+
+	for _, edit := range [][2]string{{"en", "Party photo"}, {"blitz", "on"}, {"rating", "mature"}, {"tags", "fun, night"}} {
+		if _, err := catalog.Edit("party-1", edit[0], edit[1]); err != nil {
+			t.Fatalf("edit %s: %v", edit[0], err)
+		}
+	}
+	task, _ = catalog.List().Task("party-1")
+	if task.TextFor(i18n.EN) != "Party photo" || !task.Blitz || task.Rating != RatingMature || len(task.Tags) != 2 {
+		t.Errorf("edits not applied: %+v", task)
+	}
+
+	for _, tc := range []struct {
+		id, field, value string
+		want             error
+	}{
+		{"party-9", "ru", "x", ErrTaskNotFound},
+		{"party-1", "color", "red", ErrUnknownField},
+		{"party-1", "blitz", "maybe", ErrBadValue},
+		{"party-1", "ru", "", ErrBadValue},
+	} {
+		if _, err := catalog.Edit(tc.id, tc.field, tc.value); !errors.Is(err, tc.want) {
+			t.Errorf("edit %s %s %q: expected %v, got %v", tc.id, tc.field, tc.value, tc.want, err)
+		}
+	}
+
+	if err := catalog.SetDisabled("party-1", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := catalog.List().Task("party-1"); ok {
+		t.Error("disabled task must not be served")
+	}
+	entries, err := catalog.Entries("party")
+	if err != nil || len(entries) != 1 || !entries[0].Disabled || entries[0].Source != models.TaskSourceAdmin {
+		t.Errorf("admin list must show disabled tasks: %+v, %v", entries, err)
+	}
+}
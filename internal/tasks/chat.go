@@ -0,0 +1,33 @@
+package tasks
+
+import (
+	"strconv"
+
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/models"
+)
+
+// ChatPack - пак заданий, придуманных в самом чате. Он не выключается в /packs
+// и не попадает в каталог: такие задания видит только их чат.
+const ChatPack = "chat"
+
+// ChatTasks - задания, предложенные участниками для своего чата (repositories.ChatTaskRepository)
+type ChatTasks interface {
+	Create(task *models.ChatTask) (*models.ChatTask, error)
+	Get(id uint) (*models.ChatTask, error)
+	Decide(id uint, status string, decidedBy int64) (bool, error)
+	HasText(chatID int64, text string) (bool, error)
+	Approved(chatID int64) ([]models.ChatTask, error)
+}
+
+// FromChatTask - одобренное задание чата в виде задания раунда. Текст написан
+// на языке чата, поэтому хранится как текст на языке по умолчанию и показывается всем.
+func FromChatTask(task models.ChatTask) Task {
+	return Task{
+		ID:       ChatPack + "-" + strconv.FormatUint(uint64(task.ID), 10),
+		Text:     map[i18n.Lang]string{i18n.Default: task.Text},
+		Category: "custom",
+		Rating:   RatingGeneral,
+		Pack:     ChatPack,
+	}
+}
@@ -1,23 +1,75 @@
 package tasks
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 )
 
-func loadTasksFromFile(filename string) ([]string, error) {
+// LoadPacks - читает паки из файлов. Пути могут быть шаблонами (assets/tasks/*.json).
+func LoadPacks(patterns ...string) ([]*Pack, error) {
+
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("task packs %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("task packs %q: no files", pattern)
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+
+	packs := make([]*Pack, 0, len(files))
+	for _, file := range files {
+		pack, err := loadPackFromFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		packs = append(packs, pack)
+	}
+
+	// ID задания - ключ использованных заданий и статистики, он должен быть уникален
+	seen := make(map[string]string)
+	ids := make(map[string]bool)
+	for _, pack := range packs {
+		if ids[pack.ID] {
+			return nil, fmt.Errorf("duplicate pack id %q", pack.ID)
+		}
+		ids[pack.ID] = true
+		for _, task := range pack.Tasks {
+			if other, dup := seen[task.ID]; dup {
+				return nil, fmt.Errorf("duplicate task id %q in packs %q and %q", task.ID, other, pack.ID)
+			}
+			seen[task.ID] = pack.ID
+		}
+	}
+	return packs, nil
+}
+
+func loadPackFromFile(filename string) (*Pack, error) {
 
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
 
-	var tasks []string
+	// Опечатка в ключе не должна молча терять метаданные задания
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
 
-	err = json.Unmarshal(data, &tasks)
-	if err != nil {
+	var pack Pack
+	if err := dec.Decode(&pack); err != nil {
 		return nil, err
 	}
 
-	return tasks, nil
+	if err := pack.validate(); err != nil {
+		return nil, err
+	}
+	return &pack, nil
 }
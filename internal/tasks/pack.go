@@ -0,0 +1,106 @@
+package tasks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+)
+
+// Rating - для какой компании подходит задание
+type Rating string
+
+const (
+	RatingGeneral Rating = "general" // для любого чата
+	RatingMature  Rating = "mature"  // может смутить: неловкие и личные фото
+)
+
+// Pack - набор заданий из одного файла. Чат может выключить пак командой /packs.
+type Pack struct {
+	ID    string               `json:"id"`
+	Title map[i18n.Lang]string `json:"title"`
+	Tasks []Task               `json:"tasks"`
+}
+
+// Task - задание раунда. ID не меняется при правке текста: по нему
+// считаются использованные задания и статистика.
+type Task struct {
+	ID       string               `json:"id"`
+	Text     map[i18n.Lang]string `json:"text"`
+	Category string               `json:"category"`
+	Tags     []string             `json:"tags,omitempty"`
+	Blitz    bool                 `json:"blitz,omitempty"`
+	Rating   Rating               `json:"rating"`
+	Author   string               `json:"author,omitempty"` // кто придумал, показывается в раунде
+
+	Pack  string `json:"-"` // ID пака, заполняется при загрузке
+	Uses  int    `json:"-"` // фото на задание, из статистики каталога
+	Skips int    `json:"-"` // сколько раз задание поменяли, не дождавшись фото
+}
+
+// TextFor - текст на языке чата, иначе на языке по умолчанию
+func (t Task) TextFor(lang i18n.Lang) string {
+	if text, ok := t.Text[lang]; ok && text != "" {
+		return text
+	}
+	return t.Text[i18n.Default]
+}
+
+// TitleFor - название пака на языке чата, иначе на языке по умолчанию или ID
+func (p *Pack) TitleFor(lang i18n.Lang) string {
+	if title, ok := p.Title[lang]; ok && title != "" {
+		return title
+	}
+	if title, ok := p.Title[i18n.Default]; ok && title != "" {
+		return title
+	}
+	return p.ID
+}
+
+// validate - проверяет пак и проставляет заданиям ID пака
+func (p *Pack) validate() error {
+	if p.ID == "" {
+		return fmt.Errorf("pack without id")
+	}
+	if len(p.Tasks) == 0 {
+		return fmt.Errorf("pack %q: no tasks", p.ID)
+	}
+
+	for i := range p.Tasks {
+		task := &p.Tasks[i]
+		if task.ID == "" {
+			return fmt.Errorf("pack %q: task #%d without id", p.ID, i+1)
+		}
+		if task.Text[i18n.Default] == "" {
+			return fmt.Errorf("task %q: no text for default language %q", task.ID, i18n.Default)
+		}
+		for lang := range task.Text {
+			if _, ok := i18n.Parse(string(lang)); !ok {
+				return fmt.Errorf("task %q: unsupported language %q", task.ID, lang)
+			}
+		}
+		switch task.Rating {
+		case RatingGeneral, RatingMature:
+		case "":
+			task.Rating = RatingGeneral
+		default:
+			return fmt.Errorf("task %q: unknown rating %q", task.ID, task.Rating)
+		}
+		task.Pack = p.ID
+	}
+	return nil
+}
+
+// PackSettings - где хранятся паки, выключенные в чате командой /packs.
+// Новые паки в чатах включены, пока их не выключат.
+type PackSettings interface {
+	DisabledPacks(chatID int64) ([]string, error)
+	SetDisabledPacks(chatID int64, packs []string) error
+}
+
+// History - задания, выпадавшие в чате во всех играх (repositories.ChatHistoryRepository)
+type History interface {
+	Played(chatID int64) (map[string]time.Time, error)
+	MarkPlayed(chatID int64, code string, at time.Time) error
+	ResetHistory(chatID int64) (int64, error)
+}
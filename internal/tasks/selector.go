@@ -0,0 +1,90 @@
+package tasks
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/kiselevos/memento_game_bot/config"
+)
+
+// Selector - выбирает задание раунда из доступных (не сыгранных, из включённых паков)
+type Selector interface {
+	Pick(candidates []Task) Task
+}
+
+// NewSelector - стратегия по имени из конфигурации: config.TaskSelectionUniform -
+// все доступные задания равновероятны, config.TaskSelectionWeighted - чаще те,
+// на которые присылают фото
+func NewSelector(name string, exploration float64) (Selector, error) {
+	switch name {
+	case config.TaskSelectionUniform:
+		return Uniform{}, nil
+	case config.TaskSelectionWeighted:
+		if exploration < 0 || exploration > 1 {
+			return nil, fmt.Errorf("exploration must be between 0 and 1, got %v", exploration)
+		}
+		return &Weighted{Exploration: exploration}, nil
+	}
+	return nil, fmt.Errorf("unknown task selector %q", name)
+}
+
+// Uniform - равновероятный выбор, как до статистики
+type Uniform struct{}
+
+func (Uniform) Pick(candidates []Task) Task {
+	return candidates[rand.Intn(len(candidates))]
+}
+
+// FreshBelow - задание с меньшим числом фото и пропусков считается новым:
+// статистики по нему ещё мало, чтобы судить
+const FreshBelow = 5
+
+// minWeight - даже самое пропускаемое задание иногда выпадает, чтобы статистика могла исправиться
+const minWeight = 0.05
+
+// Weighted - выбор пропорционально доле фото среди фото и пропусков.
+// С вероятностью Exploration берётся новое задание, иначе новые задания
+// копили бы статистику только по случайности.
+type Weighted struct {
+	Exploration float64
+}
+
+func (w *Weighted) Pick(candidates []Task) Task {
+	if rand.Float64() < w.Exploration {
+		var fresh []Task
+		for _, task := range candidates {
+			if task.Uses+task.Skips < FreshBelow {
+				fresh = append(fresh, task)
+			}
+		}
+		if len(fresh) > 0 {
+			return fresh[rand.Intn(len(fresh))]
+		}
+	}
+
+	weights := make([]float64, len(candidates))
+	var total float64
+	for i, task := range candidates {
+		weights[i] = Weight(task)
+		total += weights[i]
+	}
+
+	r := rand.Float64() * total
+	for i, weight := range weights {
+		if r < weight {
+			return candidates[i]
+		}
+		r -= weight
+	}
+	return candidates[len(candidates)-1]
+}
+
+// Weight - сглаженная доля фото: у задания без статистики 0.5,
+// у того, что всегда отвечают, - ближе к 1, у пропускаемого - к minWeight
+func Weight(task Task) float64 {
+	weight := float64(task.Uses+1) / float64(task.Uses+task.Skips+2)
+	if weight < minWeight {
+		return minWeight
+	}
+	return weight
+}
@@ -0,0 +1,66 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/kiselevos/memento_game_bot/config"
+)
+
+func TestWeight(t *testing.T) {
+	for _, tc := range []struct {
+		uses, skips int
+		want        float64
+	}{
+		{0, 0, 0.5},
+		{8, 0, 0.9},
+		{0, 8, 0.1},
+		{0, 100, minWeight},
+	} {
+		if got := Weight(Task{Uses: tc.uses, Skips: tc.skips}); got != tc.want {
+			t.Errorf("Weight(%d uses, %d skips) = %v, want %v", tc.uses, tc.skips, got, tc.want)
+		}
+	}
+}
+
+func TestWeightedSelector(t *testing.T) {
+	liked := Task{ID: "liked", Uses: 90, Skips: 10}
+	skipped := Task{ID: "skipped", Uses: 0, Skips: 100}
+	fresh := Task{ID: "fresh", Uses: 1}
+
+	const rounds = 2000
+	count := func(s Selector, candidates ...Task) map[string]int {
+		res := make(map[string]int)
+		for i := 0; i < rounds; i++ {
+			res[s.Pick(candidates).ID]++
+		}
+		return res
+	}
+
+	// Без исследования вес решает всё: 0.89 против 0.05
+	got := count(&Weighted{}, liked, skipped)
+	if got["liked"] < rounds*85/100 || got["skipped"] == 0 {
+		t.Errorf("expected mostly liked task and some skipped, got %v", got)
+	}
+
+	// Исследование всегда берёт новое задание, пока оно есть
+	if got := count(&Weighted{Exploration: 1}, liked, skipped, fresh); got["fresh"] != rounds {
+		t.Errorf("full exploration must pick the fresh task, got %v", got)
+	}
+	if got := count(&Weighted{Exploration: 1}, liked, skipped); got["liked"] == 0 {
+		t.Errorf("exploration without fresh tasks must fall back to weights, got %v", got)
+	}
+}
+
+func TestNewSelector(t *testing.T) {
+	if s, err := NewSelector(config.TaskSelectionUniform, 0); err != nil || s != (Uniform{}) {
+		t.Errorf("uniform: %v, %v", s, err)
+	}
+	if s, err := NewSelector(config.TaskSelectionWeighted, 0.3); err != nil || s.(*Weighted).Exploration != 0.3 {
+		t.Errorf("weighted: %v, %v", s, err)
+	}
+	for name, exploration := range map[string]float64{"best": 0, config.TaskSelectionWeighted: 2} {
+		if _, err := NewSelector(name, exploration); err == nil {
+			t.Errorf("NewSelector(%q, %v) must fail", name, exploration)
+		}
+	}
+}
@@ -2,52 +2,140 @@ package tasks
 
 import (
 	"errors"
-	"math/rand"
+	"sort"
+	"strconv"
 	"sync"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
 )
 
+// ErrNoTasks - во включённых паках не осталось неиспользованных заданий
+var ErrNoTasks = errors.New("Все задания уже использованы")
+
 type TasksList struct {
-	AllTasks []string
+	packs    []*Pack
+	byID     map[string]Task
+	selector Selector
 	mu       *sync.Mutex
 }
 
-// Фабрика для тестов
+// Фабрика для тестов: один пак "test" с заданиями test-1, test-2...
 func NewTasksListForTest(all []string) *TasksList {
-	return &TasksList{
-		AllTasks: all,
-		mu:       &sync.Mutex{},
+	pack := &Pack{ID: "test"}
+	for i, text := range all {
+		pack.Tasks = append(pack.Tasks, Task{
+			ID:   "test-" + strconv.Itoa(i+1),
+			Text: map[i18n.Lang]string{i18n.Default: text},
+		})
 	}
+	if err := pack.validate(); err != nil {
+		panic(err)
+	}
+	return newTasksList([]*Pack{pack})
 }
 
-// NewTasksList - Конструктор для структуры списка вопросов
-func NewTasksList(filepath string) (*TasksList, error) {
-	tasks, err := loadTasksFromFile(filepath)
+// NewTasksList - Конструктор для структуры списка вопросов из файлов паков
+func NewTasksList(paths ...string) (*TasksList, error) {
+	packs, err := LoadPacks(paths...)
 	if err != nil {
 		return nil, err
 	}
+	return newTasksList(packs), nil
+}
+
+func newTasksList(packs []*Pack) *TasksList {
+	tl := &TasksList{selector: Uniform{}, mu: &sync.Mutex{}}
+	tl.replace(packs)
+	return tl
+}
+
+// replace - подменяет задания целиком, например после правки каталога
+func (tl *TasksList) replace(packs []*Pack) {
+	byID := make(map[string]Task)
+	for _, pack := range packs {
+		for _, task := range pack.Tasks {
+			byID[task.ID] = task
+		}
+	}
+
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	tl.packs = packs
+	tl.byID = byID
+}
+
+// SetSelector - стратегия выбора задания раунда, по умолчанию Uniform
+func (tl *TasksList) SetSelector(s Selector) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	tl.selector = s
+}
+
+// Packs - все паки с заданиями
+func (tl *TasksList) Packs() []*Pack {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	return tl.packs
+}
 
-	return &TasksList{
-		AllTasks: tasks,
-		mu:       &sync.Mutex{},
-	}, nil
+// Task - задание по ID
+func (tl *TasksList) Task(id string) (Task, bool) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	task, ok := tl.byID[id]
+	return task, ok
 }
 
-// GetRandomTask - метод принимающий мапу использованных вопросов, возвращающий один из несипользуемых.
-func (tl *TasksList) GetRandomTask(used map[string]bool) (string, error) {
+// GetRandomTask - метод принимающий мапу использованных вопросов (по ID),
+// выключенные в чате паки, историю чата из прошлых игр и задания самого чата,
+// возвращающий один из неиспользованных по стратегии selector. Не выпадавшие
+// в чате задания идут первыми.
+func (tl *TasksList) GetRandomTask(used, disabledPacks map[string]bool, played map[string]time.Time, chatTasks []Task) (Task, error) {
 
 	tl.mu.Lock()
 	defer tl.mu.Unlock()
 
-	var avalibalTasks []string
-	for _, task := range tl.AllTasks {
-		if !used[task] {
+	var avalibalTasks []Task
+	for _, pack := range tl.packs {
+		if disabledPacks[pack.ID] {
+			continue
+		}
+		for _, task := range pack.Tasks {
+			if !used[task.ID] {
+				avalibalTasks = append(avalibalTasks, task)
+			}
+		}
+	}
+	for _, task := range chatTasks {
+		if !used[task.ID] {
 			avalibalTasks = append(avalibalTasks, task)
 		}
 	}
 
 	if len(avalibalTasks) == 0 {
-		return "", errors.New("Все задания уже использованы")
+		return Task{}, ErrNoTasks
+	}
+
+	return tl.selector.Pick(preferUnseen(avalibalTasks, played)), nil
+}
+
+// preferUnseen - задания, которых чат ещё не видел. Если видел все - давнее
+// сыгранная половина, чтобы задание с прошлой недели не вернулось сразу.
+func preferUnseen(candidates []Task, played map[string]time.Time) []Task {
+	var unseen []Task
+	for _, task := range candidates {
+		if _, ok := played[task.ID]; !ok {
+			unseen = append(unseen, task)
+		}
+	}
+	if len(unseen) > 0 {
+		return unseen
 	}
 
-	return avalibalTasks[rand.Intn(len(avalibalTasks))], nil
+	oldest := append([]Task(nil), candidates...)
+	sort.SliceStable(oldest, func(i, j int) bool {
+		return played[oldest[i].ID].Before(played[oldest[j].ID])
+	})
+	return oldest[:(len(oldest)+1)/2]
 }
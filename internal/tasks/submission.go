@@ -0,0 +1,23 @@
+package tasks
+
+import (
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/models"
+)
+
+// CommunityPack - пак, в который попадают одобренные заявки игроков
+const CommunityPack = "community"
+
+var communityTitle = map[i18n.Lang]string{
+	i18n.RU: "От игроков",
+	i18n.EN: "From players",
+}
+
+// Submissions - очередь заявок в общий каталог (repositories.TaskSubmissionRepository)
+type Submissions interface {
+	Create(sub *models.TaskSubmission) (*models.TaskSubmission, error)
+	Get(id uint) (*models.TaskSubmission, error)
+	Pending() ([]models.TaskSubmission, error)
+	SetText(id uint, text string) (bool, error)
+	Decide(id uint, status string, reviewedBy int64, taskCode string) (bool, error)
+}
@@ -0,0 +1,154 @@
+package tasks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kiselevos/memento_game_bot/internal/i18n"
+	"github.com/kiselevos/memento_game_bot/internal/models"
+)
+
+func writePack(t *testing.T, dir, name, data string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Паки из assets: загружаются, переведены на все языки
+func TestAssetPacks(t *testing.T) {
+	tl, err := NewTasksList("../../assets/tasks/*.json")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(tl.Packs()) < 2 {
+		t.Fatalf("expected several packs, got %d", len(tl.Packs()))
+	}
+
+	blitz := 0
+	for _, pack := range tl.Packs() {
+		for _, lang := range i18n.Supported() {
+			if pack.Title[lang] == "" {
+				t.Errorf("pack %s: no %s title", pack.ID, lang)
+			}
+		}
+		for _, task := range pack.Tasks {
+			for _, lang := range i18n.Supported() {
+				if task.Text[lang] == "" {
+					t.Errorf("task %s: no %s text", task.ID, lang)
+				}
+			}
+			if strings.Contains(task.TextFor(i18n.RU), "БЛИЦ") {
+				t.Errorf("task %s: blitz is a flag, not a text prefix", task.ID)
+			}
+			if task.Category == "" || task.Pack != pack.ID {
+				t.Errorf("task %s: category %q, pack %q", task.ID, task.Category, task.Pack)
+			}
+			if task.Blitz {
+				blitz++
+			}
+		}
+	}
+	if blitz == 0 {
+		t.Error("expected blitz tasks")
+	}
+}
+
+func TestLoadRejectsBadPacks(t *testing.T) {
+	const task = `{"id": "a-1", "text": {"ru": "Кот"}, "category": "pets"}`
+
+	cases := map[string]struct {
+		packs []string
+		want  string
+	}{
+		"unknown field":    {[]string{`{"id": "a", "tasks": [{"id": "a-1", "text": {"ru": "Кот"}, "blits": true}]}`}, "blits"},
+		"no default text":  {[]string{`{"id": "a", "tasks": [{"id": "a-1", "text": {"en": "Cat"}}]}`}, "default language"},
+		"unknown language": {[]string{`{"id": "a", "tasks": [{"id": "a-1", "text": {"ru": "Кот", "de": "Katze"}}]}`}, "unsupported language"},
+		"bad rating":       {[]string{`{"id": "a", "tasks": [{"id": "a-1", "text": {"ru": "Кот"}, "rating": "x"}]}`}, "unknown rating"},
+		"empty pack":       {[]string{`{"id": "a", "tasks": []}`}, "no tasks"},
+		"duplicate task":   {[]string{`{"id": "a", "tasks": [` + task + `]}`, `{"id": "b", "tasks": [` + task + `]}`}, `duplicate task id "a-1"`},
+		"duplicate pack":   {[]string{`{"id": "a", "tasks": [` + task + `]}`, `{"id": "a", "tasks": [{"id": "a-2", "text": {"ru": "Пёс"}}]}`}, `duplicate pack id "a"`},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			for i, data := range tc.packs {
+				writePack(t, dir, string(rune('a'+i))+".json", data)
+			}
+			_, err := NewTasksList(filepath.Join(dir, "*.json"))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error about %q, got %v", tc.want, err)
+			}
+		})
+	}
+
+	if _, err := NewTasksList(filepath.Join(t.TempDir(), "*.json")); err == nil {
+		t.Error("pattern without files must fail")
+	}
+}
+
+func TestGetRandomTask(t *testing.T) {
+	dir := t.TempDir()
+	writePack(t, dir, "a.json", `{"id": "a", "tasks": [
+		{"id": "a-1", "text": {"ru": "Кот", "en": "Cat"}},
+		{"id": "a-2", "text": {"ru": "Пёс"}, "blitz": true}
+	]}`)
+	writePack(t, dir, "b.json", `{"id": "b", "tasks": [{"id": "b-1", "text": {"ru": "Небо"}}]}`)
+
+	tl, err := NewTasksList(filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := tl.GetRandomTask(map[string]bool{"a-1": true}, map[string]bool{"b": true}, nil, nil)
+	if err != nil || task.ID != "a-2" || !task.Blitz || task.Pack != "a" {
+		t.Errorf("expected the only unused task of enabled packs, got %+v, %v", task, err)
+	}
+	if _, err := tl.GetRandomTask(map[string]bool{"a-1": true, "a-2": true}, map[string]bool{"b": true}, nil, nil); !errors.Is(err, ErrNoTasks) {
+		t.Errorf("expected ErrNoTasks, got %v", err)
+	}
+
+	// Задания чата добавляются к пакам
+	own := models.ChatTask{Text: "Наш кот"}
+	own.ID = 7
+	task, err = tl.GetRandomTask(map[string]bool{"a-1": true, "a-2": true}, map[string]bool{"b": true}, nil, []Task{FromChatTask(own)})
+	if err != nil || task.ID != "chat-7" || task.TextFor(i18n.EN) != "Наш кот" {
+		t.Errorf("expected the chat's own task, got %+v, %v", task, err)
+	}
+
+	cat, _ := tl.Task("a-1")
+	if cat.TextFor(i18n.EN) != "Cat" || cat.Rating != RatingGeneral {
+		t.Errorf("unexpected task: %+v", cat)
+	}
+	if dog, _ := tl.Task("a-2"); dog.TextFor(i18n.EN) != "Пёс" {
+		t.Errorf("missing translation must fall back to default language, got %q", dog.TextFor(i18n.EN))
+	}
+}
+
+func TestGetRandomTaskPrefersUnseen(t *testing.T) {
+	tl := NewTasksListForTest([]string{"Кот", "Пёс", "Небо", "Море"})
+	week := 7 * 24 * time.Hour
+	now := time.Now()
+
+	// Из прошлых игр чат помнит три задания: остаётся единственное новое
+	played := map[string]time.Time{"test-1": now.Add(-week), "test-2": now, "test-3": now}
+	for i := 0; i < 20; i++ {
+		if task, _ := tl.GetRandomTask(nil, nil, played, nil); task.ID != "test-4" {
+			t.Fatalf("expected the unseen task, got %s", task.ID)
+		}
+	}
+
+	// Всё видели - выбирается из давнее сыгранной половины
+	played["test-4"] = now.Add(-2 * week)
+	for i := 0; i < 20; i++ {
+		if task, _ := tl.GetRandomTask(nil, nil, played, nil); task.ID != "test-1" && task.ID != "test-4" {
+			t.Fatalf("expected one of the oldest tasks, got %s", task.ID)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// Структуры зафиксированы на момент миграции, чтобы изменения
+// в internal/models не меняли уже выпущенную схему.
+
+type v1User struct {
+	gorm.Model
+	TgUserId    int64  `gorm:"column:tg_user_id;uniqueIndex"`
+	UserName    string `gorm:"column:username"`
+	FirstName   string `gorm:"column:first_name"`
+	GamesPlayed int    `gorm:"column:games_played"`
+	PhotosSent  int    `gorm:"column:photos_sent"`
+	UsersVote   int    `gorm:"column:users_vote"`
+}
+
+func (v1User) TableName() string { return "users" }
+
+type v1Session struct {
+	gorm.Model
+	ChatID      int64 `gorm:"column:chat_id"`
+	IsActive    bool  `gorm:"column:is_active"`
+	PhotosCount int   `gorm:"column:photos_count"`
+}
+
+func (v1Session) TableName() string { return "sessions" }
+
+type v1SessionUser struct {
+	SessionID uint       `gorm:"column:session_id;primaryKey"`
+	UserID    uint       `gorm:"column:user_id;primaryKey"`
+	Session   *v1Session `gorm:"foreignKey:SessionID"`
+	User      *v1User    `gorm:"foreignKey:UserID"`
+}
+
+func (v1SessionUser) TableName() string { return "session_users" }
+
+type v1Task struct {
+	gorm.Model
+	Text      string `gorm:"column:text;uniqueIndex"`
+	UseCount  int    `gorm:"column:use_count"`
+	SkipCount int    `gorm:"column:skip_count"`
+}
+
+func (v1Task) TableName() string { return "tasks" }
+
+// initial - базовые таблицы. В базах, созданных раньше через AutoMigrate,
+// они уже есть - такие таблицы пропускаются, и база просто получает версию.
+var initial = Migration{
+	Version: 1,
+	Name:    "initial",
+	Up: func(tx *gorm.DB) error {
+		return createMissing(tx, &v1User{}, &v1Session{}, &v1SessionUser{}, &v1Task{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&v1SessionUser{}, &v1Session{}, &v1User{}, &v1Task{})
+	},
+}
+
+// createMissing - создаёт таблицы, которых ещё нет в БД
+func createMissing(tx *gorm.DB, tables ...interface{}) error {
+	for _, table := range tables {
+		if tx.Migrator().HasTable(table) {
+			continue
+		}
+		if err := tx.Migrator().CreateTable(table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type v2GameSnapshot struct {
+	ChatID    int64     `gorm:"column:chat_id;primaryKey;autoIncrement:false"`
+	State     string    `gorm:"column:state"`
+	Data      string    `gorm:"column:data;type:text"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (v2GameSnapshot) TableName() string { return "game_snapshots" }
+
+// gameSnapshots - снимки активных игр для восстановления после рестарта
+var gameSnapshots = Migration{
+	Version: 2,
+	Name:    "game_snapshots",
+	Up: func(tx *gorm.DB) error {
+		return createMissing(tx, &v2GameSnapshot{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&v2GameSnapshot{})
+	},
+}
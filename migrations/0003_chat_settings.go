@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type v3ChatSettings struct {
+	ChatID    int64     `gorm:"column:chat_id;primaryKey;autoIncrement:false"`
+	Language  string    `gorm:"column:language;size:8"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (v3ChatSettings) TableName() string { return "chat_settings" }
+
+// chatSettings - настройки чата, начиная с языка
+var chatSettings = Migration{
+	Version: 3,
+	Name:    "chat_settings",
+	Up: func(tx *gorm.DB) error {
+		return createMissing(tx, &v3ChatSettings{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&v3ChatSettings{})
+	},
+}
@@ -0,0 +1,50 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type v4Task struct {
+	Code string `gorm:"column:code;size:64;index"`
+}
+
+func (v4Task) TableName() string { return "tasks" }
+
+type v4ChatSettings struct {
+	DisabledPacks string `gorm:"column:disabled_packs;type:text"`
+}
+
+func (v4ChatSettings) TableName() string { return "chat_settings" }
+
+// taskPacks - задания из паков: стабильный ID задания в статистике и
+// выключенные паки чата
+var taskPacks = Migration{
+	Version: 4,
+	Name:    "task_packs",
+	Up: func(tx *gorm.DB) error {
+		if err := addMissingColumn(tx, &v4Task{}, "Code"); err != nil {
+			return err
+		}
+		if !tx.Migrator().HasIndex(&v4Task{}, "Code") {
+			if err := tx.Migrator().CreateIndex(&v4Task{}, "Code"); err != nil {
+				return err
+			}
+		}
+		return addMissingColumn(tx, &v4ChatSettings{}, "DisabledPacks")
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropIndex(&v4Task{}, "Code"); err != nil {
+			return err
+		}
+		if err := tx.Migrator().DropColumn(&v4Task{}, "Code"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&v4ChatSettings{}, "DisabledPacks")
+	},
+}
+
+// addMissingColumn - добавляет колонку, если её ещё нет
+func addMissingColumn(tx *gorm.DB, table interface{}, field string) error {
+	if tx.Migrator().HasColumn(table, field) {
+		return nil
+	}
+	return tx.Migrator().AddColumn(table, field)
+}
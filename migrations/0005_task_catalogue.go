@@ -0,0 +1,75 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type v5Task struct {
+	Pack     string `gorm:"column:pack;size:64;index"`
+	Texts    string `gorm:"column:texts;type:text"`
+	Category string `gorm:"column:category;size:64"`
+	Tags     string `gorm:"column:tags"`
+	Blitz    bool   `gorm:"column:blitz"`
+	Rating   string `gorm:"column:rating;size:16"`
+	Source   string `gorm:"column:source;size:16"`
+	Disabled bool   `gorm:"column:disabled"`
+	Archived bool   `gorm:"column:archived"`
+}
+
+func (v5Task) TableName() string { return "tasks" }
+
+var v5TaskColumns = []string{"Pack", "Texts", "Category", "Tags", "Blitz", "Rating", "Source", "Disabled", "Archived"}
+
+type v5TaskPack struct {
+	ID        string    `gorm:"column:id;primaryKey;size:64"`
+	Titles    string    `gorm:"column:titles;type:text"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (v5TaskPack) TableName() string { return "task_packs" }
+
+// taskCatalogue - задания целиком в БД: импорт из паков и правки админов
+var taskCatalogue = Migration{
+	Version: 5,
+	Name:    "task_catalogue",
+	Up: func(tx *gorm.DB) error {
+		for _, column := range v5TaskColumns {
+			if err := addMissingColumn(tx, &v5Task{}, column); err != nil {
+				return err
+			}
+		}
+		if !tx.Migrator().HasIndex(&v5Task{}, "Pack") {
+			if err := tx.Migrator().CreateIndex(&v5Task{}, "Pack"); err != nil {
+				return err
+			}
+		}
+		return createMissing(tx, &v5TaskPack{})
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropTable(&v5TaskPack{}); err != nil {
+			return err
+		}
+		if tx.Migrator().HasIndex(&v5Task{}, "Pack") {
+			if err := tx.Migrator().DropIndex(&v5Task{}, "Pack"); err != nil {
+				return err
+			}
+		}
+		for _, column := range v5TaskColumns {
+			if err := tx.Migrator().DropColumn(&v5Task{}, column); err != nil {
+				return err
+			}
+		}
+		// SQLite пересоздаёт таблицу при удалении колонок и теряет индекс версии 4
+		return ensureIndex(tx, &v4Task{}, "Code")
+	},
+}
+
+// ensureIndex - создаёт индекс, если его нет
+func ensureIndex(tx *gorm.DB, table interface{}, field string) error {
+	if tx.Migrator().HasIndex(table, field) {
+		return nil
+	}
+	return tx.Migrator().CreateIndex(table, field)
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type v6ChatTaskPlay struct {
+	ChatID   int64     `gorm:"column:chat_id;primaryKey;autoIncrement:false"`
+	TaskCode string    `gorm:"column:task_code;primaryKey;size:64"`
+	Plays    int       `gorm:"column:plays"`
+	PlayedAt time.Time `gorm:"column:played_at"`
+}
+
+func (v6ChatTaskPlay) TableName() string { return "chat_task_plays" }
+
+// chatTaskHistory - сыгранные в чате задания между играми
+var chatTaskHistory = Migration{
+	Version: 6,
+	Name:    "chat_task_history",
+	Up: func(tx *gorm.DB) error {
+		return createMissing(tx, &v6ChatTaskPlay{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&v6ChatTaskPlay{})
+	},
+}
@@ -0,0 +1,27 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type v7ChatTask struct {
+	gorm.Model
+	ChatID     int64  `gorm:"column:chat_id;index"`
+	Text       string `gorm:"column:text;type:text"`
+	AuthorID   int64  `gorm:"column:author_id"`
+	AuthorName string `gorm:"column:author_name"`
+	Status     string `gorm:"column:status;size:16"`
+	DecidedBy  int64  `gorm:"column:decided_by"`
+}
+
+func (v7ChatTask) TableName() string { return "chat_tasks" }
+
+// chatTasks - задания, предложенные участниками для своего чата
+var chatTasks = Migration{
+	Version: 7,
+	Name:    "chat_tasks",
+	Up: func(tx *gorm.DB) error {
+		return createMissing(tx, &v7ChatTask{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&v7ChatTask{})
+	},
+}
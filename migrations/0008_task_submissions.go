@@ -0,0 +1,47 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type v8TaskSubmission struct {
+	gorm.Model
+	Text       string `gorm:"column:text;type:text"`
+	AuthorID   int64  `gorm:"column:author_id;index"`
+	AuthorName string `gorm:"column:author_name"`
+	ChatID     int64  `gorm:"column:chat_id"`
+	Status     string `gorm:"column:status;size:16;index"`
+	ReviewedBy int64  `gorm:"column:reviewed_by"`
+	TaskCode   string `gorm:"column:task_code;size:64"`
+}
+
+func (v8TaskSubmission) TableName() string { return "task_submissions" }
+
+type v8Task struct {
+	Author string `gorm:"column:author"`
+}
+
+func (v8Task) TableName() string { return "tasks" }
+
+// taskSubmissions - очередь заявок в общий каталог и автор задания
+var taskSubmissions = Migration{
+	Version: 8,
+	Name:    "task_submissions",
+	Up: func(tx *gorm.DB) error {
+		if err := addMissingColumn(tx, &v8Task{}, "Author"); err != nil {
+			return err
+		}
+		return createMissing(tx, &v8TaskSubmission{})
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropTable(&v8TaskSubmission{}); err != nil {
+			return err
+		}
+		if err := tx.Migrator().DropColumn(&v8Task{}, "Author"); err != nil {
+			return err
+		}
+		// SQLite пересоздаёт таблицу без индексов прошлых версий
+		if err := ensureIndex(tx, &v4Task{}, "Code"); err != nil {
+			return err
+		}
+		return ensureIndex(tx, &v5Task{}, "Pack")
+	},
+}
@@ -0,0 +1,16 @@
+package migrations
+
+// All - все миграции проекта. Новая миграция - новый файл и строка здесь,
+// уже выпущенные миграции не меняются.
+func All() []Migration {
+	return []Migration{
+		initial,
+		gameSnapshots,
+		chatSettings,
+		taskPacks,
+		taskCatalogue,
+		chatTaskHistory,
+		chatTasks,
+		taskSubmissions,
+	}
+}
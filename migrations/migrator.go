@@ -0,0 +1,200 @@
+// Package migrations - версионированные миграции схемы БД.
+// Каждая миграция применяется в своей транзакции, а номер версии
+// фиксируется в таблице schema_migrations.
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrSchemaOutdated - в БД применены не все миграции, известные боту
+var ErrSchemaOutdated = errors.New("схема БД устарела")
+
+// ErrSchemaAhead - в БД есть миграции, о которых бот не знает (старая сборка)
+var ErrSchemaAhead = errors.New("схема БД новее бота")
+
+// Migration - один шаг изменения схемы
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration - запись о применённой миграции
+type SchemaMigration struct {
+	Version   int64     `gorm:"column:version;primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"column:name"`
+	AppliedAt time.Time `gorm:"column:applied_at"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status - состояние одной миграции для команды status
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator - мигратор со списком миграций проекта
+func NewMigrator(db *gorm.DB) *Migrator {
+	return NewMigratorWith(db, All())
+}
+
+// NewMigratorWith - мигратор с произвольным списком миграций
+func NewMigratorWith(db *gorm.DB, migrations []Migration) *Migrator {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	return &Migrator{db: db, migrations: sorted}
+}
+
+// prepare - создаёт schema_migrations перед up и down. Status и Check схему не меняют.
+func (m *Migrator) prepare() (map[int64]SchemaMigration, error) {
+	if err := m.db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("не удалось создать schema_migrations: %w", err)
+	}
+	return m.applied()
+}
+
+// applied - применённые миграции. Таблица schema_migrations должна существовать.
+func (m *Migrator) applied() (map[int64]SchemaMigration, error) {
+	var rows []SchemaMigration
+	if err := m.db.Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("не удалось прочитать schema_migrations: %w", err)
+	}
+
+	res := make(map[int64]SchemaMigration, len(rows))
+	for _, r := range rows {
+		res[r.Version] = r
+	}
+	return res, nil
+}
+
+// Status - список всех миграций с отметкой, применена ли она
+func (m *Migrator) Status() ([]Status, error) {
+	applied := map[int64]SchemaMigration{}
+	if m.db.Migrator().HasTable(&SchemaMigration{}) {
+		var err error
+		if applied, err = m.applied(); err != nil {
+			return nil, err
+		}
+	}
+
+	res := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		row, ok := applied[mig.Version]
+		res = append(res, Status{Migration: mig, Applied: ok, AppliedAt: row.AppliedAt})
+	}
+	return res, nil
+}
+
+// Up - применяет все непримененные миграции по порядку. Возвращает применённые.
+func (m *Migrator) Up() ([]Migration, error) {
+	applied, err := m.prepare()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := mig.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{
+				Version:   mig.Version,
+				Name:      mig.Name,
+				AppliedAt: time.Now().UTC(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("миграция %d_%s up: %w", mig.Version, mig.Name, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Down - откатывает steps последних применённых миграций. Возвращает откаченные.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.prepare()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		mig := m.migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		if mig.Down == nil {
+			return done, fmt.Errorf("у миграции %d_%s нет шага down", mig.Version, mig.Name)
+		}
+
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := mig.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, "version = ?", mig.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("миграция %d_%s down: %w", mig.Version, mig.Name, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Check - проверка перед стартом бота: в БД должны быть применены
+// ровно те миграции, о которых знает текущая сборка. Схему не меняет: у пользователя
+// БД бота может не быть прав на DDL.
+func (m *Migrator) Check() error {
+	if !m.db.Migrator().HasTable(&SchemaMigration{}) {
+		return fmt.Errorf("%w: нет таблицы schema_migrations", ErrSchemaOutdated)
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	known := make(map[int64]bool, len(m.migrations))
+	pending := 0
+	for _, mig := range m.migrations {
+		known[mig.Version] = true
+		if _, ok := applied[mig.Version]; !ok {
+			pending++
+		}
+	}
+
+	for version := range applied {
+		if !known[version] {
+			return fmt.Errorf("%w: неизвестная миграция %d", ErrSchemaAhead, version)
+		}
+	}
+
+	if pending > 0 {
+		return fmt.Errorf("%w: не применено миграций: %d", ErrSchemaOutdated, pending)
+	}
+	return nil
+}
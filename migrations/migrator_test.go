@@ -0,0 +1,201 @@
+package migrations
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/kiselevos/memento_game_bot/config"
+	"github.com/kiselevos/memento_game_bot/internal/models"
+	"github.com/kiselevos/memento_game_bot/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	database, err := db.NewDB(config.DbConfig{
+		Driver: config.DriverSQLite,
+		Path:   filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := database.DB.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return database.DB
+}
+
+func TestMigratorUpDown(t *testing.T) {
+	gdb := newTestDB(t)
+	m := NewMigrator(gdb)
+
+	if err := m.Check(); !errors.Is(err, ErrSchemaOutdated) {
+		t.Fatalf("expected outdated schema on empty db, got %v", err)
+	}
+	if gdb.Migrator().HasTable(&SchemaMigration{}) {
+		t.Fatal("check must not create schema_migrations")
+	}
+	if status, err := m.Status(); err != nil || len(status) != len(All()) || status[0].Applied {
+		t.Fatalf("expected all migrations pending on empty db, got %+v %v", status, err)
+	}
+
+	done, err := m.Up()
+	if err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if len(done) != len(All()) {
+		t.Errorf("expected %d applied, got %d", len(All()), len(done))
+	}
+	if err := m.Check(); err != nil {
+		t.Errorf("expected up to date schema, got %v", err)
+	}
+	for _, table := range []string{"users", "sessions", "session_users", "tasks", "game_snapshots", "chat_settings", "task_packs", "chat_task_plays", "chat_tasks", "task_submissions"} {
+		if !gdb.Migrator().HasTable(table) {
+			t.Errorf("table %s not created", table)
+		}
+	}
+
+	// Повторный up ничего не делает
+	if done, err := m.Up(); err != nil || len(done) != 0 {
+		t.Errorf("expected no-op up, got %d applied, err %v", len(done), err)
+	}
+
+	done, err = m.Down(1)
+	if err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if len(done) != 1 || done[0].Version != taskSubmissions.Version {
+		t.Fatalf("expected last migration rolled back, got %+v", done)
+	}
+	if gdb.Migrator().HasTable("task_submissions") || gdb.Migrator().HasColumn("tasks", "author") {
+		t.Error("task_submissions and tasks.author must be dropped")
+	}
+	if !gdb.Migrator().HasIndex(&v4Task{}, "Code") || !gdb.Migrator().HasIndex(&v5Task{}, "Pack") {
+		t.Error("earlier indexes on tasks must survive the rollback")
+	}
+
+	done, err = m.Down(1)
+	if err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if len(done) != 1 || done[0].Version != chatTasks.Version {
+		t.Fatalf("expected chat_tasks rolled back, got %+v", done)
+	}
+	if gdb.Migrator().HasTable("chat_tasks") {
+		t.Error("chat_tasks must be dropped")
+	}
+
+	done, err = m.Down(1)
+	if err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if len(done) != 1 || done[0].Version != chatTaskHistory.Version {
+		t.Fatalf("expected chat_task_history rolled back, got %+v", done)
+	}
+	if gdb.Migrator().HasTable("chat_task_plays") {
+		t.Error("chat_task_plays must be dropped")
+	}
+
+	done, err = m.Down(1)
+	if err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if len(done) != 1 || done[0].Version != taskCatalogue.Version {
+		t.Fatalf("expected task_catalogue rolled back, got %+v", done)
+	}
+	if gdb.Migrator().HasTable("task_packs") || gdb.Migrator().HasColumn("tasks", "pack") || gdb.Migrator().HasColumn("tasks", "disabled") {
+		t.Error("task_catalogue table and columns must be dropped")
+	}
+	if !gdb.Migrator().HasColumn("tasks", "code") || !gdb.Migrator().HasColumn("tasks", "text") {
+		t.Error("rolling back columns must keep earlier ones")
+	}
+	if !gdb.Migrator().HasIndex(&v4Task{}, "Code") {
+		t.Error("index on tasks.code from version 4 must survive the rollback")
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if !status[0].Applied || !status[3].Applied || status[4].Applied {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	if _, err := m.Down(10); err != nil {
+		t.Fatalf("down all: %v", err)
+	}
+	if gdb.Migrator().HasTable("users") {
+		t.Error("users must be dropped")
+	}
+}
+
+func TestMigratorFailedStepRollsBack(t *testing.T) {
+	gdb := newTestDB(t)
+
+	broken := Migration{
+		Version: 100,
+		Name:    "broken",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec("ALTER TABLE no_such_table ADD COLUMN x int").Error
+		},
+	}
+	m := NewMigratorWith(gdb, []Migration{broken, initial})
+
+	done, err := m.Up()
+	if err == nil {
+		t.Fatal("expected error from broken migration")
+	}
+	if len(done) != 1 || done[0].Version != initial.Version {
+		t.Errorf("expected only initial applied, got %+v", done)
+	}
+
+	var count int64
+	gdb.Model(&SchemaMigration{}).Where("version = ?", broken.Version).Count(&count)
+	if count != 0 {
+		t.Error("failed migration must not be recorded")
+	}
+}
+
+func TestMigratorSchemaAhead(t *testing.T) {
+	gdb := newTestDB(t)
+
+	if _, err := NewMigrator(gdb).Up(); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+
+	// Старая сборка бота знает только о первой миграции
+	old := NewMigratorWith(gdb, []Migration{initial})
+	if err := old.Check(); !errors.Is(err, ErrSchemaAhead) {
+		t.Errorf("expected schema ahead error, got %v", err)
+	}
+}
+
+func TestMigratorAdoptsAutoMigratedSchema(t *testing.T) {
+	gdb := newTestDB(t)
+
+	// Так выглядела база до версионированных миграций
+	err := gdb.AutoMigrate(&models.User{}, &models.Session{}, &models.Task{}, &models.GameSnapshot{})
+	if err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	gdb.Create(models.NewUser(1, "a", "A"))
+
+	m := NewMigrator(gdb)
+	if _, err := m.Up(); err != nil {
+		t.Fatalf("up on existing schema: %v", err)
+	}
+	if err := m.Check(); err != nil {
+		t.Errorf("expected up to date schema, got %v", err)
+	}
+
+	var count int64
+	gdb.Model(&models.User{}).Count(&count)
+	if count != 1 {
+		t.Errorf("existing data lost, users=%d", count)
+	}
+}
@@ -3,11 +3,14 @@ package db
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/kiselevos/memento_game_bot/config"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -18,36 +21,81 @@ type Db struct {
 }
 
 // NewDB - создание нового подключения к DB
-func NewDB(conf *config.Config) (*Db, error) {
-	var db *gorm.DB
-	var err error
+func NewDB(conf config.DbConfig) (*Db, error) {
+	dialector, err := newDialector(conf)
+	if err != nil {
+		return nil, err
+	}
 
-	dsn := conf.Db.Dsn
+	var db *gorm.DB
 	maxAttempts := 5
 	delay := 2 * time.Second
 
 	for i := 1; i <= maxAttempts; i++ {
-		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
+		db, err = gorm.Open(dialector, &gorm.Config{
 			PrepareStmt:            true,
 			SkipDefaultTransaction: true,
-			Logger:                 logger.Default.LogMode(logger.Warn),
+			Logger:                 gormLogger(),
 		})
 		if err == nil {
-			sqlDB, err := db.DB()
+			err = ping(db, conf.Driver)
 			if err == nil {
-				ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-				defer cancel()
-				if err = sqlDB.PingContext(ctx); err == nil {
-					return &Db{db}, nil
-				}
+				return &Db{db}, nil
 			}
+		}
+
+		slog.Warn("Database connection failed, retrying...", "component", "db", "attempt", i, "err", err)
+		time.Sleep(delay)
+	}
 
-			log.Println("Database connection failed, retrying...",
-				"attempt", i, "error", err)
-			time.Sleep(delay)
+	return nil, fmt.Errorf("could not connect to database after %d attempts: %w", maxAttempts, err)
+}
 
+func newDialector(conf config.DbConfig) (gorm.Dialector, error) {
+	switch conf.Driver {
+	case config.DriverPostgres, "":
+		return postgres.Open(conf.Dsn), nil
+	case config.DriverSQLite:
+		if dir := filepath.Dir(conf.Path); dir != "." {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, fmt.Errorf("create sqlite dir: %w", err)
+			}
 		}
+		dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", conf.Path)
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unknown db driver %q", conf.Driver)
+	}
+}
+
+func ping(db *gorm.DB, driver string) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
 	}
 
-	return nil, fmt.Errorf("could not connect to database after %d attempts: %w", maxAttempts, err)
+	// SQLite допускает одного писателя - одно соединение избавляет от "database is locked"
+	if driver == config.DriverSQLite {
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
+
+// gormLogger - медленные запросы и ошибки GORM идут в общий лог.
+// "record not found" - штатный ответ репозиториев, его не логируем.
+func gormLogger() logger.Interface {
+	return logger.New(slogWriter{}, logger.Config{
+		SlowThreshold:             200 * time.Millisecond,
+		LogLevel:                  logger.Warn,
+		IgnoreRecordNotFoundError: true,
+	})
+}
+
+type slogWriter struct{}
+
+func (slogWriter) Printf(format string, args ...interface{}) {
+	slog.Warn(fmt.Sprintf(format, args...), "component", "gorm")
 }